@@ -176,3 +176,27 @@ func reauthenticate(tokens *config.Tokens) error {
 	*tokens = newTokens
 	return nil
 }
+
+// withBackgroundAuth is withAuth for long-running commands. It never prompts
+// for a login, and picks up tokens that another pz command refreshed since
+// they were loaded, because refresh tokens are single-use.
+func withBackgroundAuth[T any](tokens *config.Tokens, fn func(string) (T, error)) (T, error) {
+	result, err := fn(tokens.AccessToken)
+	if !errors.Is(err, api.ErrUnauthorized) {
+		return result, err
+	}
+
+	if stored, loadErr := config.LoadTokens(); loadErr == nil && stored.AccessToken != tokens.AccessToken {
+		*tokens = stored
+		result, err = fn(tokens.AccessToken)
+		if !errors.Is(err, api.ErrUnauthorized) {
+			return result, err
+		}
+	}
+
+	if refreshErr := refreshSession(tokens); refreshErr != nil {
+		var zero T
+		return zero, fmt.Errorf("session expired and could not be refreshed, run pz login: %w", refreshErr)
+	}
+	return fn(tokens.AccessToken)
+}
@@ -267,3 +267,43 @@ func TestRefreshSessionRetainsStoredTokensWhenEntitlementIsUnavailable(t *testin
 		t.Fatalf("tokens changed after retryable failure: %+v", tokens)
 	}
 }
+
+func TestWithBackgroundAuthUsesTokensRefreshedElsewhereAndNeverLogsIn(t *testing.T) {
+	origLogin := loginFunc
+	origRefresh := refreshAccessTokenFunc
+	t.Cleanup(func() {
+		loginFunc = origLogin
+		refreshAccessTokenFunc = origRefresh
+	})
+	loginFunc = func() (config.Tokens, error) {
+		t.Fatal("login should not be called")
+		return config.Tokens{}, nil
+	}
+	savePaperTestTokens(t)
+
+	// Another pz command rotated the stored tokens to access-1.
+	tokens := config.Tokens{AccessToken: "access-stale", RefreshToken: "refresh-stale"}
+	refreshAccessTokenFunc = func(string, string) (config.Tokens, error) {
+		t.Fatal("refresh should not be needed")
+		return config.Tokens{}, nil
+	}
+	result, err := withBackgroundAuth(&tokens, func(accessToken string) (string, error) {
+		if accessToken != "access-1" {
+			return "", api.ErrUnauthorized
+		}
+		return "ok", nil
+	})
+	if err != nil || result != "ok" || tokens.RefreshToken != "refresh-1" {
+		t.Fatalf("result = %q, err = %v, tokens = %+v", result, err, tokens)
+	}
+
+	refreshAccessTokenFunc = func(string, string) (config.Tokens, error) {
+		return config.Tokens{}, errors.New("invalid refresh token")
+	}
+	_, err = withBackgroundAuth(&tokens, func(string) (string, error) {
+		return "", api.ErrUnauthorized
+	})
+	if err == nil || !strings.Contains(err.Error(), "run pz login") {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/paperzilla/pz/internal/layout"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	sourcesCmd.PersistentFlags().BoolP("json", "j", false, "Output as JSON")
+	sourcesCmd.AddCommand(sourcesListCmd)
+
+	categoriesCmd.PersistentFlags().BoolP("json", "j", false, "Output as JSON")
+	categoriesListCmd.Flags().String("source", "", "Only categories of this source (name or ID)")
+	categoriesListCmd.Flags().String("search", "", "Only categories whose code or name contains this text")
+	categoriesCmd.AddCommand(categoriesListCmd)
+}
+
+var sourcesCmd = &cobra.Command{
+	Use:   "sources",
+	Short: "Browse the paper sources projects can watch",
+}
+
+var sourcesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List paper sources",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonOut, _ := cmd.Flags().GetBool("json")
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+		sources, err := withAuth(&tokens, func(at string) ([]api.ProjectSource, error) {
+			return api.FetchSources(at)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch sources: %w", err)
+		}
+		sort.Slice(sources, func(i, j int) bool { return sources[i].ID < sources[j].ID })
+
+		if jsonOut {
+			return writeJSON(cmd.OutOrStdout(), sources)
+		}
+		if len(sources) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No sources found.")
+			return nil
+		}
+		return writePaged(cmd, func(out io.Writer) error {
+			return sourceListTable(sources).Render(out, outputWidth(out))
+		})
+	},
+}
+
+var categoriesCmd = &cobra.Command{
+	Use:   "categories",
+	Short: "Browse the subject categories projects can watch",
+}
+
+var categoriesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List categories, optionally of one source or matching a search",
+	Example: `  pz categories list --source arxiv
+  pz categories list --search cs.LG
+  pz categories list --source 1 --search learning --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonOut, _ := cmd.Flags().GetBool("json")
+		sourceRef, _ := cmd.Flags().GetString("source")
+		search, _ := cmd.Flags().GetString("search")
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+		opts := api.CategoryOptions{Search: strings.TrimSpace(search)}
+		if strings.TrimSpace(sourceRef) != "" {
+			source, err := resolveSource(&tokens, sourceRef)
+			if err != nil {
+				return err
+			}
+			opts.SourceID = source.ID
+		}
+		categories, err := withAuth(&tokens, func(at string) ([]api.Category, error) {
+			return api.FetchCategories(at, opts)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch categories: %w", err)
+		}
+		categories = filterCategories(categories, opts)
+
+		if jsonOut {
+			return writeJSON(cmd.OutOrStdout(), categories)
+		}
+		if len(categories) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No categories found.")
+			return nil
+		}
+		return writePaged(cmd, func(out io.Writer) error {
+			return categoryListTable(categories).Render(out, outputWidth(out))
+		})
+	},
+}
+
+// resolveSource finds a source by ID or by case-insensitive name.
+func resolveSource(tokens *config.Tokens, ref string) (api.ProjectSource, error) {
+	ref = strings.TrimSpace(ref)
+	if id, err := strconv.Atoi(ref); err == nil {
+		return api.ProjectSource{ID: id}, nil
+	}
+	sources, err := withAuth(tokens, func(at string) ([]api.ProjectSource, error) {
+		return api.FetchSources(at)
+	})
+	if err != nil {
+		return api.ProjectSource{}, fmt.Errorf("failed to fetch sources: %w", err)
+	}
+	for _, source := range sources {
+		if strings.EqualFold(source.Name, ref) {
+			return source, nil
+		}
+	}
+	return api.ProjectSource{}, fmt.Errorf("unknown source %q (see pz sources list)", ref)
+}
+
+// filterCategories applies the source and search filters locally as well,
+// and sorts by source and code, so the output does not depend on how the
+// server orders or filters.
+func filterCategories(categories []api.Category, opts api.CategoryOptions) []api.Category {
+	search := strings.ToLower(opts.Search)
+	filtered := []api.Category{}
+	for _, category := range categories {
+		if opts.SourceID > 0 && category.SourceID != opts.SourceID {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(category.Code), search) && !strings.Contains(strings.ToLower(category.Name), search) {
+			continue
+		}
+		filtered = append(filtered, category)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		if filtered[i].SourceName != filtered[j].SourceName {
+			return filtered[i].SourceName < filtered[j].SourceName
+		}
+		return filtered[i].Code < filtered[j].Code
+	})
+	return filtered
+}
+
+func sourceListTable(sources []api.ProjectSource) layout.Table {
+	table := layout.Table{
+		Columns: []layout.Column{
+			{Header: "ID", Priority: 3},
+			{Header: "NAME", Priority: 2, Flexible: true, MinWidth: 8},
+			{Header: "URL", Priority: 1, Flexible: true, MinWidth: 12},
+		},
+	}
+	for _, source := range sources {
+		table.Rows = append(table.Rows, []string{
+			strconv.Itoa(source.ID),
+			terminalSafeInline(source.Name),
+			terminalSafeInline(source.BaseURL),
+		})
+	}
+	return table
+}
+
+// categoryListTable keeps ID and CODE visible longest; NAME shrinks first.
+func categoryListTable(categories []api.Category) layout.Table {
+	table := layout.Table{
+		Columns: []layout.Column{
+			{Header: "ID", Priority: 4},
+			{Header: "CODE", Priority: 3},
+			{Header: "NAME", Priority: 2, Flexible: true, MinWidth: 12},
+			{Header: "SOURCE", Priority: 1},
+		},
+	}
+	for _, category := range categories {
+		table.Rows = append(table.Rows, []string{
+			strconv.Itoa(category.ID),
+			terminalSafeInline(category.Code),
+			terminalSafeInline(category.Name),
+			terminalSafeInline(category.SourceName),
+		})
+	}
+	return table
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/spf13/cobra"
+)
+
+const catalogTestCategories = `[
+	{"id":15,"code":"stat.ML","name":"Machine Learning","source_id":1,"source_name":"arXiv"},
+	{"id":12,"code":"cs.LG","name":"Machine Learning","source_id":1,"source_name":"arXiv"},
+	{"id":30,"code":"bioinformatics","name":"Bioinformatics","source_id":3,"source_name":"bioRxiv"},
+	{"id":31,"code":"cs.LG","name":"Learning","source_id":4,"source_name":"Mirror"}
+]`
+
+func catalogTestRoutes(queries *[]string) testRoutes {
+	return testRoutes{
+		"/api/sources": respondWith(`[{"id":3,"name":"bioRxiv","base_url":"https://biorxiv.org"},{"id":1,"name":"arXiv","base_url":"https://arxiv.org"}]`),
+		"/api/categories": func(w http.ResponseWriter, r *http.Request) {
+			*queries = append(*queries, r.URL.RawQuery)
+			_, _ = w.Write([]byte(catalogTestCategories))
+		},
+	}
+}
+
+func catalogTestFlags(cmd *cobra.Command) {
+	cmd.Flags().String("source", "", "")
+	cmd.Flags().String("search", "", "")
+	cmd.Flags().Bool("json", false, "")
+}
+
+func TestSourcesListTable(t *testing.T) {
+	var queries []string
+	newTestAPI(t, catalogTestRoutes(&queries))
+
+	cmd, stdout, _ := newTestCommand(t, catalogTestFlags)
+	if err := sourcesListCmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	want := "ID  NAME     URL\n1   arXiv    https://arxiv.org\n3   bioRxiv  https://biorxiv.org\n"
+	if stdout.String() != want {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+}
+
+func TestCategoriesListFiltersBySourceNameAndSearch(t *testing.T) {
+	var queries []string
+	newTestAPI(t, catalogTestRoutes(&queries))
+
+	cmd, stdout, _ := newTestCommand(t, catalogTestFlags, "--source", "ARXIV", "--search", "learning", "--json")
+	if err := categoriesListCmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	if len(queries) != 1 || queries[0] != "search=learning&source_id=1" {
+		t.Fatalf("queries = %v", queries)
+	}
+	var categories []api.Category
+	if err := json.Unmarshal(stdout.Bytes(), &categories); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if len(categories) != 2 || categories[0].Code != "cs.LG" || categories[1].Code != "stat.ML" {
+		t.Fatalf("categories = %#v", categories)
+	}
+
+	cmd, _, _ = newTestCommand(t, catalogTestFlags, "--source", "pubmed")
+	if err := categoriesListCmd.RunE(cmd, nil); err == nil || !strings.Contains(err.Error(), `unknown source "pubmed"`) {
+		t.Fatalf("err = %v", err)
+	}
+}
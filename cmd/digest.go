@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"fmt"
+	"net/mail"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/digest"
+	"github.com/paperzilla/pz/internal/download"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultDigestLimit = 50
+	digestPageSize     = 50
+)
+
+var sendMailFunc = func(m digest.Mailer, from string, to []string, msg []byte) error {
+	return m.Send(from, to, msg)
+}
+
+func init() {
+	digestSendCmd.Flags().StringArray("to", nil, "Recipient address (repeatable, or comma-separated)")
+	digestSendCmd.Flags().String("from", "", "Sender address (default: smtp.from in the settings file)")
+	digestSendCmd.Flags().StringP("since", "s", "", "Include papers ready after this date (default: 1 day for daily projects, else 7 days)")
+	digestSendCmd.Flags().BoolP("must-read", "m", false, "Only include must-read papers")
+	digestSendCmd.Flags().IntP("limit", "n", 0, "Maximum number of papers (default: the project's digest size, or 50)")
+	digestSendCmd.Flags().String("subject", "", "Subject line")
+	digestSendCmd.Flags().Bool("dry-run", false, "Write the message to an .eml file instead of sending it")
+	digestSendCmd.Flags().StringP("output", "o", "", "File for --dry-run (default: <project>-digest-<date>.eml)")
+	digestCmd.AddCommand(digestSendCmd)
+}
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Email feed digests to anyone",
+}
+
+var digestSendCmd = &cobra.Command{
+	Use:   "send <project-id>",
+	Short: "Email a digest of recent papers through your SMTP server",
+	Long: "Build an email digest of a project's recent papers, grouped into must-read\n" +
+		"and related, and send it through your own SMTP server. Recipients do not\n" +
+		"need a Paperzilla account.\n\n" +
+		"The server is configured under \"smtp\" in ~/.paperzilla/config.json:\n\n" +
+		"  {\"smtp\": {\"host\": \"smtp.example.com\", \"port\": 587, \"username\": \"bot\",\n" +
+		"            \"from\": \"Lab Bot <bot@example.com>\"}}\n\n" +
+		"The password is read from PZ_SMTP_PASSWORD (or \"password\" in the file).\n" +
+		"Connections use STARTTLS unless \"tls\" is \"tls\" (implicit TLS) or \"none\".",
+	Example: `  pz digest send <project-id> --to team@lab.example
+  pz digest send <project-id> --to a@lab.example,b@lab.example --must-read --since 2026-10-01
+  pz digest send <project-id> --to team@lab.example --dry-run -o digest.eml`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		toValues, _ := cmd.Flags().GetStringArray("to")
+		fromFlag, _ := cmd.Flags().GetString("from")
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		mustRead, _ := cmd.Flags().GetBool("must-read")
+		limit, _ := cmd.Flags().GetInt("limit")
+		subject, _ := cmd.Flags().GetString("subject")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		output, _ := cmd.Flags().GetString("output")
+
+		if limit < 0 {
+			return fmt.Errorf("invalid digest request: limit must be at least 0")
+		}
+		recipients, err := digest.ParseAddresses(toValues)
+		if err != nil {
+			return fmt.Errorf("invalid digest request: %w", err)
+		}
+		if len(recipients) == 0 {
+			return fmt.Errorf("invalid digest request: --to is required")
+		}
+
+		settings, err := loadSettingsFunc()
+		if err != nil {
+			return fmt.Errorf("failed to load settings: %w", err)
+		}
+		var mailer digest.Mailer
+		from := fromFlag
+		if settings.SMTP != nil {
+			mailer = digest.Mailer{
+				Host:     settings.SMTP.Host,
+				Port:     settings.SMTP.Port,
+				Username: settings.SMTP.Username,
+				Password: settings.SMTP.Password,
+				TLS:      settings.SMTP.TLS,
+			}
+			if password := os.Getenv("PZ_SMTP_PASSWORD"); password != "" {
+				mailer.Password = password
+			}
+			if from == "" {
+				from = settings.SMTP.From
+			}
+		}
+		if !dryRun {
+			if settings.SMTP == nil {
+				return fmt.Errorf("no SMTP server configured; add \"smtp\" to ~/.paperzilla/config.json (see pz digest send --help) or use --dry-run")
+			}
+			if err := mailer.Validate(); err != nil {
+				return fmt.Errorf("invalid settings: %w", err)
+			}
+		}
+		if from == "" {
+			return fmt.Errorf("invalid digest request: no sender; set smtp.from in ~/.paperzilla/config.json or pass --from")
+		}
+		sender, err := mail.ParseAddress(from)
+		if err != nil {
+			return fmt.Errorf("invalid digest request: invalid from address %q: %w", from, err)
+		}
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+		project, err := withAuth(&tokens, func(at string) (api.Project, error) {
+			return api.FetchProject(at, args[0])
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch project: %w", err)
+		}
+
+		now := time.Now()
+		since, err := digestSince(sinceFlag, project.EmailFrequency, now)
+		if err != nil {
+			return err
+		}
+		if limit == 0 {
+			limit = defaultDigestLimit
+			if project.MaxPapersPerDigests > 0 {
+				limit = project.MaxPapersPerDigests
+			}
+		}
+
+		var items []api.ProjectPaper
+		for offset := 0; len(items) < limit; {
+			page, err := withAuth(&tokens, func(at string) (api.FeedResponse, error) {
+				return api.FetchFeed(at, project.ID, api.FeedOptions{
+					MustReadOnly: mustRead,
+					Since:        since.UTC().Format(time.RFC3339),
+					Limit:        min(digestPageSize, limit-len(items)),
+					Offset:       offset,
+				})
+			})
+			if err != nil {
+				return fmt.Errorf("failed to fetch feed: %w", err)
+			}
+			items = append(items, page.Items...)
+			offset += len(page.Items)
+			if len(page.Items) == 0 || offset >= page.Total {
+				break
+			}
+		}
+
+		out := cmd.OutOrStdout()
+		if len(items) == 0 {
+			fmt.Fprintf(out, "No new papers in %s since %s; nothing to send.\n", terminalSafeInline(project.Name), since.Format("2006-01-02"))
+			return nil
+		}
+
+		d := buildDigest(project, items, since, now)
+		html, err := d.HTML()
+		if err != nil {
+			return err
+		}
+		if subject == "" {
+			subject = d.Subject()
+		}
+		to := make([]string, len(recipients))
+		for i, recipient := range recipients {
+			to[i] = recipient.Address
+		}
+		msg, err := digest.Compose(digest.Header{From: sender.String(), To: toValues, Subject: subject, Date: now}, d.Text(), html)
+		if err != nil {
+			return fmt.Errorf("failed to build digest: %w", err)
+		}
+
+		if dryRun {
+			if output == "" {
+				output = fmt.Sprintf("%s-digest-%s.eml", download.Slugify(project.Name), now.Format("2006-01-02"))
+			}
+			if err := os.WriteFile(output, msg, 0o644); err != nil {
+				return fmt.Errorf("failed to write digest: %w", err)
+			}
+			fmt.Fprintf(out, "Wrote %s (%d papers, not sent)\n", terminalSafeInline(output), d.Count())
+			return nil
+		}
+
+		if err := sendMailFunc(mailer, sender.Address, to, msg); err != nil {
+			return fmt.Errorf("failed to send digest: %w", err)
+		}
+		fmt.Fprintf(out, "Sent %d papers to %s\n", d.Count(), terminalSafeInline(strings.Join(to, ", ")))
+		return nil
+	},
+}
+
+// digestSince parses --since, or picks a window that matches the project's
+// own email schedule.
+func digestSince(value, emailFrequency string, now time.Time) (time.Time, error) {
+	if value == "" {
+		if strings.EqualFold(emailFrequency, "daily") {
+			return now.AddDate(0, 0, -1), nil
+		}
+		return now.AddDate(0, 0, -7), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid digest request: --since must be a date like 2026-01-31")
+}
+
+func buildDigest(project api.Project, items []api.ProjectPaper, since, until time.Time) digest.Digest {
+	d := digest.Digest{ProjectName: plainText(project.Name), Since: since, Until: until}
+	for _, item := range items {
+		entry := digestEntry(item)
+		if item.RelevanceClass == 2 {
+			d.MustRead = append(d.MustRead, entry)
+		} else {
+			d.Related = append(d.Related, entry)
+		}
+	}
+	return d
+}
+
+func digestEntry(item api.ProjectPaper) digest.Entry {
+	title := item.PaperTitle
+	if strings.TrimSpace(title) == "" {
+		title = item.Paper.Title
+	}
+	entry := digest.Entry{
+		Title:     plainText(title),
+		Relevance: int(item.RelevanceScore * 100),
+		Summary:   plainText(item.Summary),
+		Note:      plainText(item.PersonalizedNote),
+	}
+
+	byline := []string{}
+	if len(item.Paper.Authors) > 0 {
+		byline = append(byline, firstAuthorSurname(item.Paper.Authors))
+	}
+	if venue := paperListLabel(item.Paper); venue != "" {
+		byline = append(byline, venue)
+	}
+	if len(item.Paper.PublishedDate) >= 4 {
+		byline = append(byline, item.Paper.PublishedDate[:4])
+	}
+	entry.Byline = plainText(strings.Join(byline, " · "))
+
+	for _, link := range []digest.Link{
+		{Label: "Paperzilla", URL: recommendationWebURL(item.ID)},
+		{Label: "Paper", URL: item.Paper.URL},
+		{Label: "PDF", URL: item.Paper.PdfURL},
+		{Label: "DOI", URL: doiURL(item.Paper.DOI)},
+	} {
+		if target, ok := safeLinkTarget(link.URL); ok {
+			entry.Links = append(entry.Links, digest.Link{Label: link.Label, URL: target})
+		}
+	}
+	return entry
+}
+
+// plainText drops control characters and collapses whitespace so API text
+// cannot break the layout of either part.
+func plainText(s string) string {
+	return strings.Join(strings.Fields(strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f {
+			return ' '
+		}
+		return r
+	}, s)), " ")
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/paperzilla/pz/internal/digest"
+	"github.com/spf13/cobra"
+)
+
+func newDigestSendTestCmd(t *testing.T, flags ...string) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.Flags().StringArray("to", nil, "")
+	cmd.Flags().String("from", "", "")
+	cmd.Flags().StringP("since", "s", "", "")
+	cmd.Flags().BoolP("must-read", "m", false, "")
+	cmd.Flags().IntP("limit", "n", 0, "")
+	cmd.Flags().String("subject", "", "")
+	cmd.Flags().Bool("dry-run", false, "")
+	cmd.Flags().StringP("output", "o", "", "")
+	if err := cmd.Flags().Parse(flags); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	return cmd, &stdout
+}
+
+func serveDigestFeed(t *testing.T, feed string) *[]string {
+	t.Helper()
+	var sinces []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/projects/proj-1":
+			w.Write([]byte(`{"id":"proj-1","name":"Graph Learning","email_frequency":"daily","max_papers_per_digests":10}`))
+		case "/api/projects/proj-1/feed":
+			sinces = append(sinces, r.URL.Query().Get("since")+" limit="+r.URL.Query().Get("limit"))
+			w.Write([]byte(feed))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+	return &sinces
+}
+
+func stubSettings(t *testing.T, settings config.Settings) {
+	t.Helper()
+	originalLoad := loadSettingsFunc
+	loadSettingsFunc = func() (config.Settings, error) { return settings, nil }
+	t.Cleanup(func() { loadSettingsFunc = originalLoad })
+}
+
+const digestTestFeed = `{"items":[
+	{"id":"pp-1","paper_title":"Related one","relevance_class":1,"paper":{"url":"javascript:alert(1)"}},
+	{"id":"pp-2","paper_title":"Must one","relevance_class":2,"relevance_score":0.9,"summary":"Big\nresult.",
+	 "paper":{"authors":[{"name":"Ada Lovelace"}],"venue_name":"ICML","published_date":"2026-05-01","pdf_url":"https://example.com/a.pdf"}}
+],"total":2}`
+
+func TestDigestSendDeliversThroughSMTP(t *testing.T) {
+	sinces := serveDigestFeed(t, digestTestFeed)
+	stubSettings(t, config.Settings{SMTP: &config.SMTP{Host: "smtp.lab.example", Username: "bot", Password: "file", From: "Lab Bot <bot@lab.example>"}})
+	t.Setenv("PZ_SMTP_PASSWORD", "env-secret")
+
+	var sent digest.Mailer
+	var envelope []string
+	var message string
+	originalSend := sendMailFunc
+	sendMailFunc = func(m digest.Mailer, from string, to []string, msg []byte) error {
+		sent, envelope, message = m, append([]string{from}, to...), string(msg)
+		return nil
+	}
+	t.Cleanup(func() { sendMailFunc = originalSend })
+
+	cmd, stdout := newDigestSendTestCmd(t, "--to", "a@lab.example, b@lab.example", "--to", "c@lab.example", "--since", "2026-10-01")
+	if err := digestSendCmd.RunE(cmd, []string{"proj-1"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+
+	if got := strings.Join(envelope, " "); got != "bot@lab.example a@lab.example b@lab.example c@lab.example" {
+		t.Fatalf("envelope = %q", got)
+	}
+	if sent.Password != "env-secret" || sent.Host != "smtp.lab.example" {
+		t.Fatalf("mailer = %+v", sent)
+	}
+	if !strings.HasPrefix((*sinces)[0], "2026-10-01T") || !strings.HasSuffix((*sinces)[0], " limit=10") {
+		t.Fatalf("feed query = %q", *sinces)
+	}
+	for _, want := range []string{"Subject: =?utf-8?q?Graph_Learning:_2_new_papers", "multipart/alternative", "Must read", "Lovelace =C2=B7 ICML =C2=B7 2026", "Big result.", "PDF: https://example.com/a.pdf"} {
+		if !strings.Contains(message, want) {
+			t.Errorf("message missing %q", want)
+		}
+	}
+	if strings.Contains(message, "javascript:") || strings.Index(message, "Must one") > strings.Index(message, "Related one") {
+		t.Errorf("unexpected message:\n%s", message)
+	}
+	if stdout.String() != "Sent 2 papers to a@lab.example, b@lab.example, c@lab.example\n" {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+}
+
+func TestDigestSendDryRunWritesEML(t *testing.T) {
+	serveDigestFeed(t, digestTestFeed)
+	stubSettings(t, config.Settings{})
+	output := filepath.Join(t.TempDir(), "digest.eml")
+
+	cmd, stdout := newDigestSendTestCmd(t, "--to", "team@lab.example", "--from", "me@lab.example", "--dry-run", "-o", output, "--subject", "Weekly picks")
+	if err := digestSendCmd.RunE(cmd, []string{"proj-1"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil || !strings.Contains(string(data), "Subject: Weekly picks\r\n") || !strings.Contains(string(data), "To: <team@lab.example>\r\n") {
+		t.Fatalf("eml = %q, %v", data, err)
+	}
+	if !strings.Contains(stdout.String(), "(2 papers, not sent)") {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+}
+
+func TestDigestSendValidatesBeforeFetching(t *testing.T) {
+	stubSettings(t, config.Settings{})
+	tests := []struct {
+		flags []string
+		want  string
+	}{
+		{nil, "--to is required"},
+		{[]string{"--to", "nope"}, "invalid address"},
+		{[]string{"--to", "a@lab.example"}, "no SMTP server configured"},
+		{[]string{"--to", "a@lab.example", "--dry-run"}, "no sender"},
+	}
+	for _, tt := range tests {
+		cmd, _ := newDigestSendTestCmd(t, tt.flags...)
+		if err := digestSendCmd.RunE(cmd, []string{"proj-1"}); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("flags %q: err = %v, want %q", tt.flags, err, tt.want)
+		}
+	}
+}
+
+func TestDigestSinceDefaultsToProjectSchedule(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	if got, _ := digestSince("", "daily", now); !got.Equal(now.AddDate(0, 0, -1)) {
+		t.Errorf("daily since = %s", got)
+	}
+	if got, _ := digestSince("", "weekly", now); !got.Equal(now.AddDate(0, 0, -7)) {
+		t.Errorf("weekly since = %s", got)
+	}
+	if _, err := digestSince("last week", "", now); err == nil {
+		t.Error("invalid since should fail")
+	}
+}
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/download"
+	"github.com/spf13/cobra"
+)
+
+const downloadFeedPageSize = 50
+
+func init() {
+	addDownloadFlags(downloadCmd)
+
+	addDownloadFlags(feedDownloadCmd)
+	feedDownloadCmd.Flags().BoolP("must-read", "m", false, "Only download must-read papers")
+	feedDownloadCmd.Flags().StringP("since", "s", "", "Only papers ready after this date")
+	feedDownloadCmd.Flags().IntP("limit", "n", 0, "Download at most this many papers (0 for all)")
+}
+
+func addDownloadFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("dir", "d", ".", "Directory to save PDFs in")
+	cmd.Flags().String("name", download.DefaultNameTemplate, "Filename template using {author}, {year}, {short_id}, {id} and {slug}")
+	cmd.Flags().Int("jobs", download.DefaultWorkers, "Number of parallel downloads")
+	cmd.Flags().Int("max-size", download.DefaultMaxSize>>20, "Largest PDF to accept, in MB")
+}
+
+var downloadCmd = &cobra.Command{
+	Use:   "download <paper-ref|project-paper-ref>...",
+	Short: "Download paper PDFs",
+	Long: "Download paper PDFs.\n\n" +
+		"Interrupted downloads resume on the next run. Completed files are\n" +
+		"recorded in " + download.ManifestName + " in the download directory\n" +
+		"with their source URL, size and SHA-256, and are skipped on re-runs.",
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		downloader, template, err := downloaderFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		tokens, hasAuth, err := loadOptionalAuth()
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		fetchProjectPaper := projectPaperLookup(&tokens, &hasAuth)
+		var papers []downloadPaper
+		failed := 0
+		for _, ref := range args {
+			paper, _, err := lookupPaperRef(ref, fetchProjectPaper)
+			if err != nil {
+				fmt.Fprintf(out, "Failed   %s: %s\n", terminalSafeInline(ref), terminalSafeInline(err.Error()))
+				failed++
+				continue
+			}
+			papers = append(papers, downloadPaper{ref: ref, paper: paper})
+		}
+
+		return runDownloads(cmd.Context(), out, downloader, template, papers, failed)
+	},
+}
+
+var feedDownloadCmd = &cobra.Command{
+	Use:   "download <project-id>",
+	Short: "Download PDFs for a project feed",
+	Long: "Download PDFs for a project feed.\n\n" +
+		"Interrupted downloads resume on the next run. Completed files are\n" +
+		"recorded in " + download.ManifestName + " in the download directory\n" +
+		"with their source URL, size and SHA-256, and are skipped on re-runs.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mustRead, _ := cmd.Flags().GetBool("must-read")
+		since, _ := cmd.Flags().GetString("since")
+		limit, _ := cmd.Flags().GetInt("limit")
+		if limit < 0 {
+			return fmt.Errorf("invalid download request: limit must be at least 0")
+		}
+
+		downloader, template, err := downloaderFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+
+		var papers []downloadPaper
+		for offset := 0; limit == 0 || len(papers) < limit; {
+			pageSize := downloadFeedPageSize
+			if limit > 0 {
+				pageSize = min(pageSize, limit-len(papers))
+			}
+			page, err := withAuth(&tokens, func(at string) (api.FeedResponse, error) {
+				return api.FetchFeed(at, args[0], api.FeedOptions{
+					MustReadOnly: mustRead,
+					Since:        since,
+					Limit:        pageSize,
+					Offset:       offset,
+				})
+			})
+			if err != nil {
+				return fmt.Errorf("failed to fetch feed: %w", err)
+			}
+			for _, item := range page.Items {
+				paper := item.Paper
+				if strings.TrimSpace(paper.Title) == "" {
+					paper.Title = item.PaperTitle
+				}
+				papers = append(papers, downloadPaper{ref: item.ID, paper: paper})
+			}
+			offset += len(page.Items)
+			if len(page.Items) == 0 || offset >= page.Total {
+				break
+			}
+		}
+
+		return runDownloads(cmd.Context(), cmd.OutOrStdout(), downloader, template, papers, 0)
+	},
+}
+
+type downloadPaper struct {
+	ref   string
+	paper api.Paper
+}
+
+func downloaderFromFlags(cmd *cobra.Command) (download.Downloader, string, error) {
+	dir, _ := cmd.Flags().GetString("dir")
+	template, _ := cmd.Flags().GetString("name")
+	jobs, _ := cmd.Flags().GetInt("jobs")
+	maxSize, _ := cmd.Flags().GetInt("max-size")
+
+	if jobs < 1 {
+		return download.Downloader{}, "", fmt.Errorf("invalid download request: jobs must be at least 1")
+	}
+	if maxSize < 1 {
+		return download.Downloader{}, "", fmt.Errorf("invalid download request: max-size must be at least 1")
+	}
+	if err := download.ValidateTemplate(template); err != nil {
+		return download.Downloader{}, "", err
+	}
+
+	downloader := download.NewDownloader(dir)
+	downloader.Workers = jobs
+	downloader.MaxSize = int64(maxSize) << 20
+	downloader.UserAgent = api.UserAgent()
+	return downloader, template, nil
+}
+
+// downloadJobs names one job per paper with a PDF. Papers whose names
+// collide, in this run or with a file recorded in the manifest for another
+// URL, get their ID appended. recorded maps manifest names to their URLs;
+// because a name keeps its URL there, a paper keeps its name across runs.
+func downloadJobs(papers []downloadPaper, template string, recorded map[string]string) ([]download.Job, []downloadPaper, error) {
+	var jobs []download.Job
+	var missing []downloadPaper
+	used := map[string]string{}
+
+	for _, p := range papers {
+		target, ok := safeLinkTarget(p.paper.PdfURL)
+		if !ok {
+			missing = append(missing, p)
+			continue
+		}
+
+		firstAuthor := ""
+		if len(p.paper.Authors) > 0 {
+			firstAuthor = p.paper.Authors[0].Name
+		}
+		id := p.paper.ID
+		if id == "" {
+			id = p.ref
+		}
+		fields := download.FieldsFor(id, p.paper.ShortID, p.paper.Slug, p.paper.Title, firstAuthor, p.paper.PublishedDate)
+		name, err := download.RenderName(template, fields)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		name, duplicate := uniqueDownloadName(used, recorded, name, id, target)
+		if duplicate {
+			continue
+		}
+		used[name] = target
+		jobs = append(jobs, download.Job{Ref: p.ref, URL: target, Name: name})
+	}
+	return jobs, missing, nil
+}
+
+// uniqueDownloadName appends the paper's ID to a name that is already used
+// in this run, or recorded for another URL, then a counter until the name
+// is free. duplicate reports that this run already downloads target under
+// the name, so the paper can be skipped.
+func uniqueDownloadName(used, recorded map[string]string, name, id, target string) (string, bool) {
+	base := strings.TrimSuffix(name, ".pdf") + "-" + download.Slugify(id)
+	for n := 1; ; n++ {
+		if url, taken := used[name]; taken {
+			if url == target {
+				return name, true
+			}
+		} else if url, ok := recorded[name]; !ok || url == target {
+			return name, false
+		}
+		name = base + ".pdf"
+		if n > 1 {
+			name = fmt.Sprintf("%s-%d.pdf", base, n)
+		}
+	}
+}
+
+// runDownloads downloads papers and prints one line per file plus a summary.
+// lookupFailures counts refs that could not be resolved to a paper.
+func runDownloads(ctx context.Context, out io.Writer, downloader download.Downloader, template string, papers []downloadPaper, lookupFailures int) error {
+	manifest, err := download.LoadManifest(downloader.Dir)
+	if err != nil {
+		return err
+	}
+	recorded := map[string]string{}
+	for name, entry := range manifest.Files {
+		recorded[name] = entry.URL
+	}
+	jobs, missing, err := downloadJobs(papers, template, recorded)
+	if err != nil {
+		return err
+	}
+	for _, p := range missing {
+		fmt.Fprintf(out, "No PDF   %s\n", terminalSafeInline(p.ref))
+	}
+
+	downloader.Progress = func(result download.Result) {
+		name := terminalSafeInline(result.Job.Name)
+		switch result.Status {
+		case download.StatusDownloaded:
+			resumed := ""
+			if result.Resumed {
+				resumed = ", resumed"
+			}
+			fmt.Fprintf(out, "Saved    %s (%s%s)\n", name, download.FormatSize(result.Size), resumed)
+		case download.StatusSkipped:
+			fmt.Fprintf(out, "Skipped  %s (already downloaded)\n", name)
+		case download.StatusFailed:
+			fmt.Fprintf(out, "Failed   %s: %s\n", name, terminalSafeInline(result.Err.Error()))
+		}
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	results, err := downloader.Run(ctx, jobs)
+	if err != nil {
+		return err
+	}
+
+	counts := map[download.Status]int{}
+	for _, result := range results {
+		counts[result.Status]++
+	}
+	failed := lookupFailures + counts[download.StatusFailed]
+	fmt.Fprintf(out, "\n%d downloaded, %d already present, %d without PDF, %d failed in %s\n",
+		counts[download.StatusDownloaded], counts[download.StatusSkipped], len(missing), failed, terminalSafeInline(downloader.Dir))
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d downloads failed", failed, len(jobs)+lookupFailures)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/download"
+	"github.com/spf13/cobra"
+)
+
+const testPDF = "%PDF-1.4\nbody\n%%EOF\n"
+
+// downloadTestRoutes serves a feed with two PDFs that would get the same
+// name and one paper without a PDF.
+var downloadTestRoutes = testRoutes{
+	"/api/projects/proj-1/feed": func(w http.ResponseWriter, r *http.Request) {
+		feed := `{"items":[
+			{"id":"pp-1","paper_title":"Graph Networks","paper":{"id":"paper-1","short_id":"aa11","published_date":"2024-03-01","authors":[{"name":"Ada Lovelace"}],"pdf_url":"PDF/1.pdf"}},
+			{"id":"pp-2","paper_title":"No PDF","paper":{"id":"paper-2"}},
+			{"id":"pp-3","paper_title":"Graph Networks","paper":{"id":"paper-3","short_id":"aa11","published_date":"2024-03-01","authors":[{"name":"Ada Lovelace"}],"pdf_url":"PDF/3.pdf"}}
+		],"total":3}`
+		_, _ = w.Write([]byte(strings.ReplaceAll(feed, "PDF/", "http://"+r.Host+"/pdf/")))
+	},
+	"/pdf/1.pdf": downloadTestPDF,
+	"/pdf/3.pdf": downloadTestPDF,
+}
+
+func downloadTestPDF(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/pdf")
+	_, _ = w.Write([]byte(testPDF))
+}
+
+func downloadTestFlags(cmd *cobra.Command) {
+	addDownloadFlags(cmd)
+	cmd.Flags().BoolP("must-read", "m", false, "")
+	cmd.Flags().StringP("since", "s", "", "")
+	cmd.Flags().IntP("limit", "n", 0, "")
+}
+
+func TestFeedDownloadSavesPDFsAndSkipsOnRerun(t *testing.T) {
+	newTestAPI(t, downloadTestRoutes)
+	dir := t.TempDir()
+
+	cmd, stdout, _ := newTestCommand(t, downloadTestFlags, "--dir", dir)
+	if err := feedDownloadCmd.RunE(cmd, []string{"proj-1"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+
+	for _, name := range []string{"lovelace2024-aa11-graph-networks.pdf", "lovelace2024-aa11-graph-networks-paper-3.pdf"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(data) != testPDF {
+			t.Errorf("%s = %q, %v", name, data, err)
+		}
+	}
+	out := stdout.String()
+	for _, want := range []string{"No PDF   pp-2", "2 downloaded, 0 already present, 1 without PDF, 0 failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	cmd, stdout, _ = newTestCommand(t, downloadTestFlags, "--dir", dir)
+	if err := feedDownloadCmd.RunE(cmd, []string{"proj-1"}); err != nil {
+		t.Fatalf("second RunE: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "0 downloaded, 2 already present") {
+		t.Fatalf("second run output:\n%s", stdout.String())
+	}
+}
+
+func TestDownloadReportsUnknownRefsAndFails(t *testing.T) {
+	server := newTestAPI(t, downloadTestRoutes)
+
+	original := fetchPublicPaperFunc
+	fetchPublicPaperFunc = func(ref string) (api.Paper, error) {
+		if ref == "known" {
+			return api.Paper{ID: "paper-1", Title: "Known", PdfURL: server.URL + "/pdf/1.pdf"}, nil
+		}
+		return api.Paper{}, &api.APIError{StatusCode: 500, Detail: "boom"}
+	}
+	t.Cleanup(func() { fetchPublicPaperFunc = original })
+
+	dir := t.TempDir()
+	cmd, stdout, _ := newTestCommand(t, downloadTestFlags, "--dir", dir, "--name", "{id}")
+	err := downloadCmd.RunE(cmd, []string{"known", "broken"})
+	if err == nil || err.Error() != "1 of 2 downloads failed" {
+		t.Fatalf("err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "paper-1.pdf")); err != nil {
+		t.Fatalf("known paper should be saved: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, download.ManifestName)); err != nil {
+		t.Fatalf("manifest missing: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Failed   broken: failed to fetch paper") {
+		t.Fatalf("output:\n%s", stdout.String())
+	}
+}
+
+func TestDownloadRejectsInvalidFlags(t *testing.T) {
+	for _, flags := range [][]string{{"--jobs", "0"}, {"--max-size", "0"}, {"--name", "{title}"}} {
+		cmd, _, _ := newTestCommand(t, downloadTestFlags, flags...)
+		if err := downloadCmd.RunE(cmd, []string{"x"}); err == nil {
+			t.Errorf("flags %q should fail", flags)
+		}
+	}
+}
+
+func TestUniqueDownloadNameNeverReusesARename(t *testing.T) {
+	used := map[string]string{"smith.pdf": "https://a/1.pdf", "smith-b.pdf": "https://a/2.pdf"}
+	if name, duplicate := uniqueDownloadName(used, nil, "smith.pdf", "b", "https://a/3.pdf"); name != "smith-b-2.pdf" || duplicate {
+		t.Fatalf("name = %q, duplicate = %v", name, duplicate)
+	}
+	if name, duplicate := uniqueDownloadName(used, nil, "smith.pdf", "b", "https://a/2.pdf"); name != "smith-b.pdf" || !duplicate {
+		t.Fatalf("name = %q, duplicate = %v", name, duplicate)
+	}
+	if name, duplicate := uniqueDownloadName(used, nil, "jones.pdf", "c", "https://a/4.pdf"); name != "jones.pdf" || duplicate {
+		t.Fatalf("name = %q, duplicate = %v", name, duplicate)
+	}
+}
+
+func TestDownloadKeepsNamesRecordedForOtherPapers(t *testing.T) {
+	server := newTestAPI(t, downloadTestRoutes)
+	dir := t.TempDir()
+
+	cmd, _, _ := newTestCommand(t, downloadTestFlags, "--dir", dir)
+	if err := feedDownloadCmd.RunE(cmd, []string{"proj-1"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "lovelace2024-aa11-graph-networks.pdf"), []byte("paper 1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Downloaded alone, paper 3 keeps its name instead of taking paper 1's.
+	original := fetchPublicPaperFunc
+	fetchPublicPaperFunc = func(ref string) (api.Paper, error) {
+		return api.Paper{ID: "paper-3", ShortID: "aa11", Title: "Graph Networks", PublishedDate: "2024-03-01",
+			Authors: []api.Author{{Name: "Ada Lovelace"}}, PdfURL: server.URL + "/pdf/3.pdf"}, nil
+	}
+	t.Cleanup(func() { fetchPublicPaperFunc = original })
+	cmd, stdout, _ := newTestCommand(t, downloadTestFlags, "--dir", dir)
+	if err := downloadCmd.RunE(cmd, []string{"paper-3"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Skipped  lovelace2024-aa11-graph-networks-paper-3.pdf (already downloaded)") {
+		t.Fatalf("output:\n%s", stdout.String())
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "lovelace2024-aa11-graph-networks.pdf")); string(data) != "paper 1" {
+		t.Fatalf("paper 1 was overwritten: %q", data)
+	}
+}
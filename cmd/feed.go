@@ -3,11 +3,13 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/url"
 	"strings"
 
 	"github.com/paperzilla/pz/internal/api"
 	"github.com/paperzilla/pz/internal/config"
+	"github.com/paperzilla/pz/internal/feedmerge"
 	"github.com/spf13/cobra"
 )
 
@@ -18,19 +20,40 @@ func init() {
 	feedCmd.Flags().IntP("limit", "n", 0, "Limit number of results")
 	feedCmd.Flags().Int("offset", 0, "Number of results to skip")
 	feedCmd.Flags().Bool("atom", false, "Print Atom feed URL for use in feed readers")
-	feedCmd.AddCommand(feedSearchCmd)
+	feedCmd.Flags().Bool("new", false, "Only show papers not shown or opened before")
+	feedCmd.Flags().Bool("all-projects", false, "Merge the feeds of all your projects")
+	feedCmd.Flags().String("sort", feedmerge.SortReady, "Order of a merged feed: ready (newest first) or score (reads whole feeds)")
+	feedCmd.AddCommand(feedSearchCmd, feedDownloadCmd, feedMarkSeenCmd)
 }
 
 var feedCmd = &cobra.Command{
-	Use:   "feed <project-id>",
+	Use:   "feed <project-id>...",
 	Short: "Show relevant curated papers for a project",
-	Args:  cobra.ExactArgs(1),
+	Long: "Show relevant curated papers for a project. With several project IDs or\n" +
+		"--all-projects, the feeds are merged into one list with one entry per paper,\n" +
+		"showing which projects recommended it and how relevant each found it.",
+	Example: `  pz feed <project-id>
+  pz feed <project-id> <project-id> --must-read
+  pz feed --all-projects --new
+  pz feed --all-projects --sort score --limit 20`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		out := cmd.OutOrStdout()
+		allProjects, _ := cmd.Flags().GetBool("all-projects")
+		if allProjects && len(args) > 0 {
+			return fmt.Errorf("invalid feed request: pass project IDs or --all-projects, not both")
+		}
+		if !allProjects && len(args) == 0 {
+			return fmt.Errorf("invalid feed request: pass a project ID or --all-projects")
+		}
+
 		tokens, err := loadAuth()
 		if err != nil {
 			return err
 		}
+		if allProjects || len(args) > 1 {
+			return runMergedFeed(cmd, tokens, args)
+		}
 
 		projectID := args[0]
 
@@ -55,10 +78,14 @@ var feedCmd = &cobra.Command{
 		since, _ := cmd.Flags().GetString("since")
 		limit, _ := cmd.Flags().GetInt("limit")
 		offset, _ := cmd.Flags().GetInt("offset")
+		newOnly, _ := cmd.Flags().GetBool("new")
 
 		if offset < 0 {
 			return fmt.Errorf("invalid feed request: offset must be at least 0")
 		}
+		if newOnly && offset > 0 {
+			return fmt.Errorf("invalid feed request: --new cannot be combined with --offset")
+		}
 
 		opts := api.FeedOptions{
 			MustReadOnly: mustRead,
@@ -67,9 +94,15 @@ var feedCmd = &cobra.Command{
 			Offset:       offset,
 		}
 
-		feed, err := withAuth(&tokens, func(at string) (api.FeedResponse, error) {
-			return api.FetchFeed(at, projectID, opts)
-		})
+		tracker := loadSeenTracker(tokens, cmd.ErrOrStderr())
+		var feed api.FeedResponse
+		if newOnly {
+			feed, err = fetchUnseenFeed(tokenAuth(&tokens), projectID, opts, tracker)
+		} else {
+			feed, err = withAuth(&tokens, func(at string) (api.FeedResponse, error) {
+				return api.FetchFeed(at, projectID, opts)
+			})
+		}
 		if err != nil {
 			return fmt.Errorf("failed to fetch feed: %w", err)
 		}
@@ -90,10 +123,23 @@ var feedCmd = &cobra.Command{
 			return fmt.Errorf("failed to fetch project: %w", err)
 		}
 
-		fmt.Fprintf(out, "%s — %d papers (total: %d)\n\n", terminalSafeInline(project.Name), len(feed.Items), feed.Total)
-
-		writeProjectPaperFeedList(out, feed.Items)
+		err = writePaged(cmd, func(out io.Writer) error {
+			if newOnly {
+				fmt.Fprintf(out, "%s — %d new papers\n\n", terminalSafeInline(project.Name), len(feed.Items))
+				if len(feed.Items) == 0 {
+					fmt.Fprintln(out, "Nothing new since last time.")
+				}
+			} else {
+				fmt.Fprintf(out, "%s — %d papers (total: %d)\n\n", terminalSafeInline(project.Name), len(feed.Items), feed.Total)
+			}
 
+			writeProjectPaperFeedList(out, feed.Items, tracker.isNew)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		tracker.markShown(cmd.OutOrStdout(), projectID, projectPaperIDs(feed.Items)...)
 		return nil
 	},
 }
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/paperzilla/pz/internal/feedmerge"
+	"github.com/spf13/cobra"
+)
+
+// defaultMergedFeedLimit is the merged list's length without --limit, the
+// size of the first page pz feed gets for one project.
+const defaultMergedFeedLimit = 20
+
+type mergedFeedResponse struct {
+	Projects []feedmerge.Project `json:"projects"`
+	Items    []feedmerge.Item    `json:"items"`
+	// Total is only set when every feed was read to the end.
+	Total *int `json:"total,omitempty"`
+}
+
+// runMergedFeed shows the feeds of several projects as one list, with one
+// entry per paper. --limit and --offset apply to the merged list, and only
+// as many pages are read as that list needs (see fetchProjectFeeds).
+func runMergedFeed(cmd *cobra.Command, tokens config.Tokens, projectIDs []string) error {
+	out := cmd.OutOrStdout()
+	jsonOut, _ := cmd.Flags().GetBool("json")
+	mustRead, _ := cmd.Flags().GetBool("must-read")
+	since, _ := cmd.Flags().GetString("since")
+	limit, _ := cmd.Flags().GetInt("limit")
+	offset, _ := cmd.Flags().GetInt("offset")
+	newOnly, _ := cmd.Flags().GetBool("new")
+	atom, _ := cmd.Flags().GetBool("atom")
+	sortFlag, _ := cmd.Flags().GetString("sort")
+
+	if offset < 0 {
+		return fmt.Errorf("invalid feed request: offset must be at least 0")
+	}
+	if newOnly && offset > 0 {
+		return fmt.Errorf("invalid feed request: --new cannot be combined with --offset")
+	}
+	sortBy, err := feedmerge.ParseSort(sortFlag)
+	if err != nil {
+		return fmt.Errorf("invalid feed request: %w", err)
+	}
+
+	projects, err := feedProjects(&tokens, projectIDs)
+	if err != nil {
+		return err
+	}
+	if len(projects) == 0 {
+		if jsonOut {
+			return writeJSON(out, mergedFeedResponse{Projects: projects, Items: []feedmerge.Item{}})
+		}
+		fmt.Fprintln(out, "No projects yet.")
+		return nil
+	}
+
+	if atom {
+		tokenResp, err := withAuth(&tokens, func(at string) (api.FeedTokenResponse, error) {
+			return api.FetchFeedToken(at)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get feed token: %w", err)
+		}
+		for _, project := range projects {
+			feedURL, err := atomFeedURL(config.APIURL(), project.ID, tokenResp.Token)
+			if err != nil {
+				return fmt.Errorf("failed to build feed URL: %w", err)
+			}
+			fmt.Fprintf(out, "%s\t%s\n", terminalSafeInline(project.Name), terminalSafeInline(feedURL))
+		}
+		return nil
+	}
+
+	if limit <= 0 {
+		limit = defaultMergedFeedLimit
+		if newOnly {
+			limit = defaultNewLimit
+		}
+	}
+	opts := api.FeedOptions{MustReadOnly: mustRead, Since: since}
+	tracker := loadSeenTracker(tokens, cmd.ErrOrStderr())
+	feeds, complete, err := fetchProjectFeeds(sharedAuth(&tokens), projects, opts, sortBy, offset+limit, newOnly, tracker)
+	if err != nil {
+		return err
+	}
+
+	items := feedmerge.Merge(feeds)
+	feedmerge.Sort(items, sortBy)
+	var total *int
+	if complete {
+		n := len(items)
+		total = &n
+	}
+	items = items[min(offset, len(items)):]
+	items = items[:min(limit, len(items))]
+
+	if jsonOut {
+		return writeJSON(out, mergedFeedResponse{Projects: projects, Items: items, Total: total})
+	}
+
+	err = writePaged(cmd, func(out io.Writer) error {
+		if newOnly {
+			fmt.Fprintf(out, "%d projects — %d new papers\n\n", len(projects), len(items))
+			if len(items) == 0 {
+				fmt.Fprintln(out, "Nothing new since last time.")
+			}
+		} else if total != nil {
+			fmt.Fprintf(out, "%d projects — %d papers (total: %d)\n\n", len(projects), len(items), *total)
+		} else {
+			fmt.Fprintf(out, "%d projects — %d papers\n\n", len(projects), len(items))
+		}
+
+		writeMergedFeedList(out, items, tracker.isNew)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	shown := map[string][]string{}
+	for _, item := range items {
+		for _, rec := range item.Recommendations {
+			shown[rec.ProjectID] = append(shown[rec.ProjectID], rec.ID)
+		}
+	}
+	for _, project := range projects {
+		if ids := shown[project.ID]; len(ids) > 0 {
+			tracker.markShown(cmd.OutOrStdout(), project.ID, ids...)
+		}
+	}
+	return nil
+}
+
+// feedProjects returns every project when ids is empty, or the given
+// projects in the given order, skipping repeats.
+func feedProjects(tokens *config.Tokens, ids []string) ([]feedmerge.Project, error) {
+	all, err := withAuth(tokens, func(at string) ([]api.Project, error) {
+		return api.FetchProjects(at)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch projects: %w", err)
+	}
+
+	projects := []feedmerge.Project{}
+	if len(ids) == 0 {
+		for _, project := range all {
+			projects = append(projects, feedmerge.Project{ID: project.ID, Name: project.Name})
+		}
+		return projects, nil
+	}
+	for _, id := range ids {
+		if slices.ContainsFunc(projects, func(p feedmerge.Project) bool { return p.ID == id }) {
+			continue
+		}
+		i := slices.IndexFunc(all, func(p api.Project) bool { return p.ID == id })
+		if i < 0 {
+			project, err := withAuth(tokens, func(at string) (api.Project, error) {
+				return api.FetchProject(at, id)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch project %s: %w", terminalSafeInline(id), err)
+			}
+			all = append(all, project)
+			i = len(all) - 1
+		}
+		projects = append(projects, feedmerge.Project{ID: all[i].ID, Name: all[i].Name})
+	}
+	return projects, nil
+}
+
+// fetchProjectFeeds reads the projects' feeds page by page, all projects at
+// the same time, keeping only unseen items with newOnly. Sorted by score,
+// every page is read, because the best papers can be anywhere in a feed.
+// Sorted by ready time, a project stops once its last page is older than
+// the first want merged items: feeds are served newest first, so its later
+// pages can only hold older papers. complete reports that every feed was
+// read to the end. The feeds come back in project order.
+func fetchProjectFeeds(auth requestAuth, projects []feedmerge.Project, opts api.FeedOptions, sortBy string, want int, newOnly bool, tracker *seenTracker) ([]feedmerge.Feed, bool, error) {
+	pageSize := feedPageSize
+	if sortBy == feedmerge.SortReady && !newOnly {
+		pageSize = min(want, feedPageSize)
+	}
+
+	feeds := make([]feedmerge.Feed, len(projects))
+	offsets := make([]int, len(projects))
+	oldest := make([]time.Time, len(projects))
+	// open projects may have more pages; ended ones were read to the end.
+	open := make([]bool, len(projects))
+	ended := make([]bool, len(projects))
+	for i, project := range projects {
+		feeds[i] = feedmerge.Feed{Project: project, Items: []api.ProjectPaper{}}
+		open[i] = true
+	}
+
+	for slices.Contains(open, true) {
+		errs := make([]error, len(projects))
+		var wg sync.WaitGroup
+		for i, project := range projects {
+			if !open[i] {
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				page, err := fetchFeedPage(auth, project.ID, api.FeedOptions{
+					MustReadOnly: opts.MustReadOnly,
+					Since:        opts.Since,
+					Limit:        pageSize,
+					Offset:       offsets[i],
+				})
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				for _, item := range page.Items {
+					if !newOnly || tracker.isNew(item.ID) {
+						feeds[i].Items = append(feeds[i].Items, item)
+					}
+				}
+				if n := len(page.Items); n > 0 {
+					oldest[i] = feedmerge.ReadyTime(page.Items[n-1].ReadyAt)
+				}
+				offsets[i] += len(page.Items)
+				if len(page.Items) == 0 || offsets[i] >= page.Total {
+					open[i], ended[i] = false, true
+				}
+			}()
+		}
+		wg.Wait()
+		for i, err := range errs {
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to fetch feed of %s: %w", terminalSafeInline(projects[i].Name), err)
+			}
+		}
+
+		if sortBy != feedmerge.SortReady {
+			continue
+		}
+		items := feedmerge.Merge(feeds)
+		if len(items) < want {
+			continue
+		}
+		feedmerge.Sort(items, feedmerge.SortReady)
+		cutoff := feedmerge.ReadyTime(items[want-1].ReadyAt)
+		for i := range projects {
+			if open[i] && oldest[i].Before(cutoff) {
+				open[i] = false
+			}
+		}
+	}
+	return feeds, !slices.Contains(ended, false), nil
+}
@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const mergedFeedTestProjects = `[{"id":"proj-a","name":"Graphs"},{"id":"proj-b","name":"Proteins"}]`
+
+// mergedFeedTestRoutes serves two projects that share a paper. With requests,
+// it records each request as path?query.
+func mergedFeedTestRoutes(requests *[]string) testRoutes {
+	record := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if requests != nil {
+				*requests = append(*requests, r.URL.Path+"?"+r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(body))
+		}
+	}
+	return testRoutes{
+		"/api/projects": record(mergedFeedTestProjects),
+		"/api/projects/proj-a/feed": record(`{"items":[
+			{"id":"pp-a1","paper_title":"Shared Paper","relevance_score":0.62,"relevance_class":1,"ready_at":"2026-04-02T08:00:00Z","feedback":{"vote":"upvote"},
+				"paper":{"id":"paper-1","authors":[{"name":"Jane Smith"}],"venue_name":"arXiv","published_date":"2026-04-01"}},
+			{"id":"pp-a2","paper_title":"Only Graphs","relevance_score":0.5,"relevance_class":1,"ready_at":"2026-04-03T08:00:00Z",
+				"paper":{"id":"paper-2","authors":[{"name":"John Chen"}],"published_date":"2026-04-02"}}
+		],"total":2}`),
+		"/api/projects/proj-b/feed": record(`{"items":[
+			{"id":"pp-b1","paper_title":"Shared Paper","relevance_score":0.91,"relevance_class":2,"ready_at":"2026-04-01T08:00:00Z",
+				"paper":{"id":"paper-1","authors":[{"name":"Jane Smith"}],"venue_name":"arXiv","published_date":"2026-04-01"}}
+		],"total":1}`),
+	}
+}
+
+func mergedFeedTestFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolP("json", "j", false, "")
+	cmd.Flags().Bool("must-read", false, "")
+	cmd.Flags().String("since", "", "")
+	cmd.Flags().Int("limit", 0, "")
+	cmd.Flags().Int("offset", 0, "")
+	cmd.Flags().Bool("atom", false, "")
+	cmd.Flags().Bool("new", false, "")
+	cmd.Flags().Bool("all-projects", false, "")
+	cmd.Flags().String("sort", "ready", "")
+}
+
+func TestMergedFeedShowsEachPaperOnce(t *testing.T) {
+	newTestAPI(t, mergedFeedTestRoutes(nil))
+
+	cmd, _, _ := newTestCommand(t, mergedFeedTestFlags, "--all-projects")
+	stdout := asTerminal(t, cmd)
+	if err := feedCmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	want := "2 projects — 2 papers (total: 2)\n\n" +
+		"○ Related NEW  Only Graphs\n" +
+		"  Chen · 2026-04-02 · relevance: 50%\n" +
+		"  Graphs 50%\n\n" +
+		"★ Must Read NEW  Shared Paper\n" +
+		"  Smith · arXiv · 2026-04-01 · relevance: 91%\n" +
+		"  Proteins 91% · Graphs 62% [↑]\n\n"
+	if stdout.String() != want {
+		t.Fatalf("output = %q", stdout.String())
+	}
+
+	// Shown recommendations are marked seen in their own projects.
+	cmd, _, _ = newTestCommand(t, mergedFeedTestFlags, "--all-projects", "--new")
+	stdout = asTerminal(t, cmd)
+	if err := feedCmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("RunE --new: %v", err)
+	}
+	if stdout.String() != "2 projects — 0 new papers\n\nNothing new since last time.\n" {
+		t.Fatalf("output = %q", stdout.String())
+	}
+}
+
+func TestMergedFeedJSONSortsByScoreAndPages(t *testing.T) {
+	var requests []string
+	newTestAPI(t, mergedFeedTestRoutes(&requests))
+
+	cmd, _, _ := newTestCommand(t, mergedFeedTestFlags, "--json", "--sort", "score", "--limit", "1")
+	stdout := asTerminal(t, cmd)
+	if err := feedCmd.RunE(cmd, []string{"proj-b", "proj-a", "proj-b"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	var resp struct {
+		Projects []struct {
+			ID string `json:"id"`
+		} `json:"projects"`
+		Items []struct {
+			PaperTitle      string  `json:"paper_title"`
+			BestScore       float64 `json:"best_score"`
+			MustRead        bool    `json:"must_read"`
+			Recommendations []struct {
+				ProjectID string `json:"project_id"`
+				ID        string `json:"id"`
+			} `json:"recommendations"`
+		} `json:"items"`
+		Total int `json:"total"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		t.Fatalf("json: %v\n%s", err, stdout.String())
+	}
+	if len(resp.Projects) != 2 || resp.Projects[0].ID != "proj-b" || resp.Total != 2 || len(resp.Items) != 1 {
+		t.Fatalf("resp = %+v", resp)
+	}
+	item := resp.Items[0]
+	if item.PaperTitle != "Shared Paper" || item.BestScore != 0.91 || !item.MustRead || len(item.Recommendations) != 2 || item.Recommendations[1].ID != "pp-a1" {
+		t.Fatalf("item = %+v", item)
+	}
+	for _, request := range requests {
+		if strings.Contains(request, "/feed") && !strings.Contains(request, fmt.Sprintf("limit=%d", feedPageSize)) {
+			t.Fatalf("feed request does not page through the whole feed: %s", request)
+		}
+	}
+}
+
+func TestMergedFeedSortsByScoreOverWholeFeeds(t *testing.T) {
+	item := `{"id":"%s","paper_title":"%s","relevance_score":%g,"relevance_class":1,"ready_at":"%s","paper":{"id":"%s"}}`
+	newTestAPI(t, testRoutes{
+		"/api/projects": respondWith(mergedFeedTestProjects),
+		"/api/projects/proj-a/feed": func(w http.ResponseWriter, r *http.Request) {
+			// The best paper is old, so it is only on the second page.
+			if r.URL.Query().Get("offset") == "" {
+				fmt.Fprintf(w, `{"items":[%s,%s],"total":3}`,
+					fmt.Sprintf(item, "pp-a1", "Newest", 0.4, "2026-04-03T08:00:00Z", "paper-1"),
+					fmt.Sprintf(item, "pp-a2", "Newer", 0.5, "2026-04-02T08:00:00Z", "paper-2"))
+				return
+			}
+			fmt.Fprintf(w, `{"items":[%s],"total":3}`, fmt.Sprintf(item, "pp-a3", "Old but great", 0.97, "2025-01-01T08:00:00Z", "paper-3"))
+		},
+		"/api/projects/proj-b/feed": respondWith(fmt.Sprintf(`{"items":[%s],"total":1}`, fmt.Sprintf(item, "pp-b1", "Newer", 0.6, "2026-03-01T08:00:00Z", "paper-2"))),
+	})
+
+	cmd, _, _ := newTestCommand(t, mergedFeedTestFlags, "--all-projects", "--json", "--sort", "score", "--limit", "1")
+	stdout := asTerminal(t, cmd)
+	if err := feedCmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	var resp struct {
+		Items []struct {
+			PaperTitle string `json:"paper_title"`
+			ReadyAt    string `json:"ready_at"`
+		} `json:"items"`
+		Total int `json:"total"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		t.Fatalf("json: %v\n%s", err, stdout.String())
+	}
+	if len(resp.Items) != 1 || resp.Items[0].PaperTitle != "Old but great" || resp.Total != 3 {
+		t.Fatalf("resp = %+v", resp)
+	}
+
+	// Sorted by ready time, a shared paper still shows when it first arrived.
+	cmd, _, _ = newTestCommand(t, mergedFeedTestFlags, "--all-projects", "--json", "--offset", "1", "--limit", "1")
+	stdout = asTerminal(t, cmd)
+	if err := feedCmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		t.Fatalf("json: %v\n%s", err, stdout.String())
+	}
+	if len(resp.Items) != 1 || resp.Items[0].PaperTitle != "Newer" || resp.Items[0].ReadyAt != "2026-03-01T08:00:00Z" {
+		t.Fatalf("resp = %+v", resp)
+	}
+}
+
+func TestPipedMergedFeedDoesNotMarkPapersSeen(t *testing.T) {
+	newTestAPI(t, mergedFeedTestRoutes(nil))
+
+	cmd, _, _ := newTestCommand(t, mergedFeedTestFlags, "--all-projects")
+	var piped bytes.Buffer
+	cmd.SetOut(&piped)
+	if err := feedCmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	cmd, _, _ = newTestCommand(t, mergedFeedTestFlags, "--all-projects", "--new")
+	stdout := asTerminal(t, cmd)
+	if err := feedCmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("RunE --new: %v", err)
+	}
+	if !strings.HasPrefix(stdout.String(), "2 projects — 2 new papers\n") {
+		t.Fatalf("output = %q", stdout.String())
+	}
+}
+
+func TestMergedFeedRejectsIDsWithAllProjects(t *testing.T) {
+	cmd, _, _ := newTestCommand(t, mergedFeedTestFlags, "--all-projects")
+	err := feedCmd.RunE(cmd, []string{"proj-a"})
+	if err == nil || !strings.Contains(err.Error(), "pass project IDs or --all-projects, not both") {
+		t.Fatalf("err = %v", err)
+	}
+	cmd, _, _ = newTestCommand(t, mergedFeedTestFlags)
+	if err := feedCmd.RunE(cmd, nil); err == nil {
+		t.Fatal("expected an error without project IDs")
+	}
+}
+
+func TestMergedFeedByReadyStopsPagingPastTheLimit(t *testing.T) {
+	var pages []string
+	item := `{"id":"%s","paper_title":"%s","relevance_score":0.5,"relevance_class":1,"ready_at":"%s","paper":{"id":"%s"}}`
+	newTestAPI(t, testRoutes{
+		"/api/projects": respondWith(mergedFeedTestProjects),
+		"/api/projects/proj-a/feed": func(w http.ResponseWriter, r *http.Request) {
+			pages = append(pages, r.URL.Query().Get("offset")+"+"+r.URL.Query().Get("limit"))
+			// 200 papers, one a day, newest first.
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			var items []string
+			for i := offset; i < min(offset+limit, 200); i++ {
+				ready := time.Date(2026, 4, 1, 8, 0, 0, 0, time.UTC).AddDate(0, 0, -i).Format(time.RFC3339)
+				items = append(items, fmt.Sprintf(item, fmt.Sprintf("pp-a%d", i), fmt.Sprintf("Graphs %d", i), ready, fmt.Sprintf("paper-a%d", i)))
+			}
+			fmt.Fprintf(w, `{"items":[%s],"total":200}`, strings.Join(items, ","))
+		},
+		"/api/projects/proj-b/feed": respondWith(fmt.Sprintf(`{"items":[%s],"total":1}`, fmt.Sprintf(item, "pp-b1", "Proteins", "2026-03-30T20:00:00Z", "paper-b1"))),
+	})
+
+	cmd, _, _ := newTestCommand(t, mergedFeedTestFlags, "--all-projects", "--json", "--limit", "3")
+	stdout := asTerminal(t, cmd)
+	if err := feedCmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	var resp struct {
+		Items []struct {
+			PaperTitle string `json:"paper_title"`
+		} `json:"items"`
+		Total *int `json:"total"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		t.Fatalf("json: %v\n%s", err, stdout.String())
+	}
+	var titles []string
+	for _, item := range resp.Items {
+		titles = append(titles, item.PaperTitle)
+	}
+	if strings.Join(titles, "|") != "Graphs 0|Graphs 1|Proteins" || resp.Total != nil {
+		t.Fatalf("titles = %q, total = %v", titles, resp.Total)
+	}
+	if strings.Join(pages, ",") != "+3" {
+		t.Fatalf("proj-a pages = %q", pages)
+	}
+}
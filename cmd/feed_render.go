@@ -3,11 +3,21 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/feedmerge"
+	"github.com/paperzilla/pz/internal/layout"
 )
 
-func writeProjectPaperFeedList(w io.Writer, items []api.ProjectPaper) {
+// defaultFeedTitleWidth caps list titles when the output width is unknown.
+const defaultFeedTitleWidth = 80
+
+// writeProjectPaperFeedList prints a feed page. isNew, when set, flags
+// recommendations that have not been shown before.
+func writeProjectPaperFeedList(w io.Writer, items []api.ProjectPaper, isNew func(id string) bool) {
+	width := outputWidth(w)
+	link := linkerFor(w)
 	for _, p := range items {
 		prefix := "○ Related"
 		if p.RelevanceClass == 2 {
@@ -16,11 +26,15 @@ func writeProjectPaperFeedList(w io.Writer, items []api.ProjectPaper) {
 		if marker := feedbackMarker(p.Feedback); marker != "" {
 			prefix += " " + marker
 		}
+		if isNew != nil && isNew(p.ID) {
+			prefix += " NEW"
+		}
 
-		title := terminalSafeInline(p.PaperTitle)
-		if len(title) > 80 {
-			title = title[:77] + "..."
+		titleWidth := defaultFeedTitleWidth
+		if width > 0 {
+			titleWidth = max(width-layout.Width(prefix)-2, 20)
 		}
+		title := link(layout.Truncate(terminalSafeInline(p.PaperTitle), titleWidth), p.Paper.URL)
 
 		fmt.Fprintf(w, "%s  %s\n", prefix, title)
 
@@ -37,3 +51,47 @@ func writeProjectPaperFeedList(w io.Writer, items []api.ProjectPaper) {
 		fmt.Fprintf(w, "  %s\n\n", meta)
 	}
 }
+
+// writeMergedFeedList prints a merged feed page. Below each paper it lists
+// the projects that recommended it, with their relevance and feedback.
+func writeMergedFeedList(w io.Writer, items []feedmerge.Item, isNew func(id string) bool) {
+	width := outputWidth(w)
+	link := linkerFor(w)
+	for _, item := range items {
+		prefix := "○ Related"
+		if item.MustRead {
+			prefix = "★ Must Read"
+		}
+		projects := make([]string, len(item.Recommendations))
+		anyNew := false
+		for i, rec := range item.Recommendations {
+			projects[i] = fmt.Sprintf("%s %d%%", terminalSafeInline(rec.ProjectName), int(rec.RelevanceScore*100))
+			if marker := feedbackMarker(rec.Feedback); marker != "" {
+				projects[i] += " " + marker
+			}
+			anyNew = anyNew || (isNew != nil && isNew(rec.ID))
+		}
+		if anyNew {
+			prefix += " NEW"
+		}
+
+		titleWidth := defaultFeedTitleWidth
+		if width > 0 {
+			titleWidth = max(width-layout.Width(prefix)-2, 20)
+		}
+		title := link(layout.Truncate(terminalSafeInline(item.PaperTitle), titleWidth), item.Paper.URL)
+		fmt.Fprintf(w, "%s  %s\n", prefix, title)
+
+		meta := joinDisplayParts(
+			firstAuthorSurname(item.Paper.Authors),
+			paperListLabel(item.Paper),
+			formatTime(item.Paper.PublishedDate),
+			fmt.Sprintf("relevance: %d%%", int(item.BestScore*100)),
+		)
+		recommended := strings.Join(projects, " · ")
+		if width > 2 {
+			recommended = layout.Truncate(recommended, width-2)
+		}
+		fmt.Fprintf(w, "  %s\n  %s\n\n", meta, recommended)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/paperzilla/pz/internal/api"
@@ -72,10 +73,18 @@ var feedSearchCmd = &cobra.Command{
 			return fmt.Errorf("failed to fetch project: %w", err)
 		}
 
-		fmt.Fprintf(out, "%s — %d papers\n", terminalSafeInline(project.Name), len(search.Items))
-		fmt.Fprintf(out, "Query: %s\n", terminalSafeInline(search.Query))
-		fmt.Fprintf(out, "Has more: %t\n\n", search.HasMore)
-		writeProjectPaperFeedList(out, search.Items)
+		tracker := loadSeenTracker(tokens, cmd.ErrOrStderr())
+		err = writePaged(cmd, func(out io.Writer) error {
+			fmt.Fprintf(out, "%s — %d papers\n", terminalSafeInline(project.Name), len(search.Items))
+			fmt.Fprintf(out, "Query: %s\n", terminalSafeInline(search.Query))
+			fmt.Fprintf(out, "Has more: %t\n\n", search.HasMore)
+			writeProjectPaperFeedList(out, search.Items, tracker.isNew)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		tracker.markShown(cmd.OutOrStdout(), projectID, projectPaperIDs(search.Items)...)
 		return nil
 	},
 }
@@ -57,10 +57,10 @@ func TestFeedSearchCommandShowsResultsAndMetadata(t *testing.T) {
 	if !strings.Contains(output, "Has more: true") {
 		t.Fatalf("stdout = %q", output)
 	}
-	if !strings.Contains(output, "★ Must Read [★]  Latent Retrieval for Papers") {
+	if !strings.Contains(output, "★ Must Read [★] NEW  Latent Retrieval for Papers") {
 		t.Fatalf("stdout = %q", output)
 	}
-	if !strings.Contains(output, "○ Related  Prefix Matching in Search") {
+	if !strings.Contains(output, "○ Related NEW  Prefix Matching in Search") {
 		t.Fatalf("stdout = %q", output)
 	}
 	if strings.Contains(output, "total:") {
@@ -51,16 +51,16 @@ func TestFeedCommandShowsInlineFeedbackMarkers(t *testing.T) {
 	}
 
 	output := stdout.String()
-	if !strings.Contains(output, "★ Must Read [↑]  Upvoted Paper") {
+	if !strings.Contains(output, "★ Must Read [↑] NEW  Upvoted Paper") {
 		t.Fatalf("output = %q", output)
 	}
 	if !strings.Contains(output, "Smith · arXiv · 2026-04-01 · relevance: 95%") {
 		t.Fatalf("output = %q", output)
 	}
-	if !strings.Contains(output, "★ Must Read [★]  Starred Paper") {
+	if !strings.Contains(output, "★ Must Read [★] NEW  Starred Paper") {
 		t.Fatalf("output = %q", output)
 	}
-	if !strings.Contains(output, "○ Related [↓]  Downvoted Paper") {
+	if !strings.Contains(output, "○ Related [↓] NEW  Downvoted Paper") {
 		t.Fatalf("output = %q", output)
 	}
 	if strings.Contains(output, "crossref") || strings.Contains(output, "crossref_vc") {
@@ -2,15 +2,20 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/paperzilla/pz/internal/feedback"
 	"github.com/spf13/cobra"
 )
 
 func init() {
 	feedbackCmd.PersistentFlags().BoolP("json", "j", false, "Output as JSON")
 	feedbackCmd.Flags().String("reason", "", "Optional downvote reason (not_relevant or low_quality)")
+	feedbackCmd.Flags().Bool("dry-run", false, "Show the change without sending it")
+	feedbackClearCmd.Flags().Bool("dry-run", false, "Show the change without sending it")
 	feedbackCmd.AddCommand(feedbackClearCmd)
 }
 
@@ -43,28 +48,28 @@ var feedbackCmd = &cobra.Command{
 			return fmt.Errorf("--reason is only allowed with downvote")
 		}
 		jsonOut, _ := cmd.Flags().GetBool("json")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
 
 		tokens, err := loadRequiredAuth()
 		if err != nil {
 			return err
 		}
 
-		feedback, err := withAuth(&tokens, func(at string) (api.Feedback, error) {
-			return api.SetProjectPaperFeedback(at, args[0], vote, reason)
-		})
+		client := newFeedbackClient(tokenAuth(&tokens), tokens, cmd.ErrOrStderr())
+		client.dryRun = dryRun
+		change, err := applyFeedback(client, feedbackRequest{Ref: args[0], Vote: vote, Reason: reason})
 		if err != nil {
 			return fmt.Errorf("failed to set feedback: %w", err)
 		}
 
+		if dryRun {
+			return writeFeedbackDryRun(cmd.OutOrStdout(), jsonOut, change)
+		}
 		if jsonOut {
-			return writeJSON(cmd.OutOrStdout(), feedback)
+			return writeJSON(cmd.OutOrStdout(), change.New)
 		}
 
-		message := fmt.Sprintf("Feedback set: %s", terminalSafeInline(feedback.Vote))
-		if feedback.Vote == "downvote" && strings.TrimSpace(feedback.DownvoteReason) != "" {
-			message += fmt.Sprintf(" (%s)", terminalSafeInline(feedback.DownvoteReason))
-		}
-		fmt.Fprintln(cmd.OutOrStdout(), message)
+		fmt.Fprintf(cmd.OutOrStdout(), "Feedback set: %s\n", describeFeedback(change.New))
 		return nil
 	},
 }
@@ -75,16 +80,21 @@ var feedbackClearCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		jsonOut, _ := cmd.Flags().GetBool("json")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
 		tokens, err := loadRequiredAuth()
 		if err != nil {
 			return err
 		}
 
-		if _, err := withAuth(&tokens, func(at string) (struct{}, error) {
-			return struct{}{}, api.ClearProjectPaperFeedback(at, args[0])
-		}); err != nil {
+		client := newFeedbackClient(tokenAuth(&tokens), tokens, cmd.ErrOrStderr())
+		client.dryRun = dryRun
+		change, err := applyFeedback(client, feedbackRequest{Ref: args[0], Vote: "clear"})
+		if err != nil {
 			return fmt.Errorf("failed to clear feedback: %w", err)
 		}
+		if dryRun {
+			return writeFeedbackDryRun(cmd.OutOrStdout(), jsonOut, change)
+		}
 
 		if jsonOut {
 			return writeJSON(cmd.OutOrStdout(), feedbackClearResponse{
@@ -97,3 +107,163 @@ var feedbackClearCmd = &cobra.Command{
 		return nil
 	},
 }
+
+// requestAuth runs one API request with a valid access token.
+type requestAuth func(fn func(accessToken string) error) error
+
+// tokenAuth is withAuth as a requestAuth.
+func tokenAuth(tokens *config.Tokens) requestAuth {
+	return func(fn func(string) error) error {
+		_, err := withAuth(tokens, func(at string) (struct{}, error) {
+			return struct{}{}, fn(at)
+		})
+		return err
+	}
+}
+
+// feedbackClient sends feedback changes for one account and records each
+// one in the feedback log so it can be undone.
+type feedbackClient struct {
+	auth    requestAuth
+	profile string
+	log     *feedback.Log
+	errOut  io.Writer
+	dryRun  bool
+}
+
+func newFeedbackClient(auth requestAuth, tokens config.Tokens, errOut io.Writer) feedbackClient {
+	return feedbackClient{
+		auth:    auth,
+		profile: tokens.Account(),
+		log:     &feedback.Log{Path: config.FeedbackLogPath()},
+		errOut:  errOut,
+	}
+}
+
+type feedbackRequest struct {
+	Ref    string
+	Vote   string
+	Reason string
+	// Undoes is the feedback log entry this request reverts.
+	Undoes string
+}
+
+// feedbackChange is a recommendation's feedback before and after a change.
+type feedbackChange struct {
+	Ref      string        `json:"ref"`
+	Title    string        `json:"title,omitempty"`
+	Previous *api.Feedback `json:"previous"`
+	New      *api.Feedback `json:"new"`
+	DryRun   bool          `json:"dry_run,omitempty"`
+}
+
+// applyFeedback sets vote on a project paper, or clears its feedback when
+// vote is "clear". The current feedback is read first and the change is
+// appended to the feedback log. In a dry run nothing is sent or logged.
+func applyFeedback(c feedbackClient, req feedbackRequest) (feedbackChange, error) {
+	change, err := currentFeedback(c, req.Ref)
+	if err != nil {
+		return feedbackChange{}, err
+	}
+	return sendFeedback(c, change, req)
+}
+
+// currentFeedback reads a project paper's feedback before a change, as the
+// Previous side of the returned change.
+func currentFeedback(c feedbackClient, ref string) (feedbackChange, error) {
+	var projectPaper api.ProjectPaper
+	err := c.auth(func(at string) error {
+		var err error
+		projectPaper, err = api.FetchProjectPaper(at, ref)
+		return err
+	})
+	if err != nil {
+		return feedbackChange{}, fmt.Errorf("failed to read current feedback: %w", err)
+	}
+	change := feedbackChange{Ref: ref, Title: projectPaper.PaperTitle, Previous: projectPaper.Feedback, DryRun: c.dryRun}
+	if projectPaper.ID != "" {
+		change.Ref = projectPaper.ID
+	}
+	if strings.TrimSpace(change.Title) == "" {
+		change.Title = projectPaper.Paper.Title
+	}
+	return change, nil
+}
+
+// sendFeedback sends req for the project paper that change, from
+// currentFeedback, was read for and logs it. Retries call it again with the
+// same change, so a write that reached the server before the request failed
+// is not mistaken for the feedback it replaced.
+func sendFeedback(c feedbackClient, change feedbackChange, req feedbackRequest) (feedbackChange, error) {
+	var err error
+	if c.dryRun {
+		if req.Vote != "clear" {
+			change.New = &api.Feedback{Vote: req.Vote, DownvoteReason: req.Reason}
+		}
+		return change, nil
+	}
+
+	if req.Vote == "clear" {
+		err = c.auth(func(at string) error {
+			return api.ClearProjectPaperFeedback(at, change.Ref)
+		})
+	} else {
+		var updated api.Feedback
+		err = c.auth(func(at string) error {
+			var err error
+			updated, err = api.SetProjectPaperFeedback(at, change.Ref, req.Vote, req.Reason)
+			return err
+		})
+		change.New = &updated
+	}
+	if err != nil {
+		return feedbackChange{}, err
+	}
+
+	if c.log != nil {
+		_, err := c.log.Append(feedback.LogEntry{
+			Profile:  c.profile,
+			Ref:      change.Ref,
+			Title:    change.Title,
+			Previous: change.Previous,
+			New:      change.New,
+			Undoes:   req.Undoes,
+		})
+		if err != nil && c.errOut != nil {
+			fmt.Fprintf(c.errOut, "Warning: %s\n", terminalSafeInline(err.Error()))
+		}
+	}
+	return change, nil
+}
+
+// sameFeedback reports whether a and b are the same vote and reason. No
+// feedback and an empty vote are the same.
+func sameFeedback(a, b *api.Feedback) bool {
+	var av, ar, bv, br string
+	if a != nil {
+		av, ar = a.Vote, a.DownvoteReason
+	}
+	if b != nil {
+		bv, br = b.Vote, b.DownvoteReason
+	}
+	return av == bv && (av != "downvote" || ar == br)
+}
+
+// describeFeedback is a one-line summary of a feedback state.
+func describeFeedback(f *api.Feedback) string {
+	if f == nil || f.Vote == "" {
+		return "none"
+	}
+	if f.Vote == "downvote" && strings.TrimSpace(f.DownvoteReason) != "" {
+		return terminalSafeInline(f.Vote + " (" + f.DownvoteReason + ")")
+	}
+	return terminalSafeInline(f.Vote)
+}
+
+func writeFeedbackDryRun(out io.Writer, jsonOut bool, change feedbackChange) error {
+	if jsonOut {
+		return writeJSON(out, change)
+	}
+	fmt.Fprintf(out, "Dry run: %s would change from %s to %s\n", terminalSafeInline(change.Ref), describeFeedback(change.Previous), describeFeedback(change.New))
+	return nil
+}
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/paperzilla/pz/internal/feedback"
+	"github.com/spf13/cobra"
+)
+
+// feedbackRetrySleep waits between retries; nil uses the runner's timer.
+var feedbackRetrySleep func(context.Context, time.Duration) error
+
+func init() {
+	feedbackApplyCmd.Flags().StringP("file", "f", "", "Feedback file (.jsonl or .csv), or - for stdin")
+	feedbackApplyCmd.Flags().String("format", "", "Input format: jsonl or csv (default: from the file extension, jsonl for stdin)")
+	feedbackApplyCmd.Flags().Int("jobs", feedback.DefaultWorkers, "Number of parallel requests")
+	feedbackApplyCmd.Flags().Bool("dry-run", false, "Check the file and show each change without sending it")
+	feedbackCmd.AddCommand(feedbackApplyCmd)
+}
+
+type feedbackApplyResult struct {
+	Line     int    `json:"line"`
+	Ref      string `json:"ref"`
+	Vote     string `json:"vote"`
+	Reason   string `json:"reason,omitempty"`
+	Status   string `json:"status"`
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error,omitempty"`
+	// Previous is the feedback before the change, for rows that succeeded.
+	Previous *api.Feedback `json:"previous,omitempty"`
+}
+
+var feedbackApplyCmd = &cobra.Command{
+	Use:   "apply -f <file|->",
+	Short: "Set feedback for many project papers from a JSONL or CSV file",
+	Long: "Set feedback for many project papers at once. Each row has a project-paper\n" +
+		"ref, a vote (upvote, downvote, star or clear) and an optional downvote\n" +
+		"reason (not_relevant or low_quality):\n\n" +
+		"  {\"ref\": \"pp-1\", \"vote\": \"downvote\", \"reason\": \"low_quality\"}\n\n" +
+		"  ref,vote,reason\n" +
+		"  pp-1,downvote,low_quality\n\n" +
+		"The whole file is checked before anything is sent. Requests that fail with a\n" +
+		"network error, rate limit or server error are retried; the command exits\n" +
+		"non-zero if any row still failed.",
+	Example: `  pz feedback apply -f votes.jsonl
+  pz feedback apply -f votes.csv --jobs 8
+  jq -c '.items[] | {ref: .id, vote: "star"}' feed.json | pz feedback apply -f -`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		format, _ := cmd.Flags().GetString("format")
+		jobs, _ := cmd.Flags().GetInt("jobs")
+		jsonOut, _ := cmd.Flags().GetBool("json")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		if file == "" {
+			return fmt.Errorf("invalid feedback request: --file is required (use - for stdin)")
+		}
+		if jobs < 1 {
+			return fmt.Errorf("invalid feedback request: jobs must be at least 1")
+		}
+		if format == "" {
+			format = feedback.DetectFormat(file)
+		}
+
+		rows, err := readFeedbackRows(cmd.InOrStdin(), file, format)
+		if err != nil {
+			return err
+		}
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		client := newFeedbackClient(sharedAuth(&tokens), tokens, cmd.ErrOrStderr())
+		client.dryRun = dryRun
+		var mu sync.Mutex
+		previous := map[int]*api.Feedback{}
+		// The current feedback is read once per row: a retry only resends
+		// the change, because a failed attempt may still have reached the
+		// server and the feedback read then would be the new vote.
+		current := map[int]feedbackChange{}
+		runner := feedback.Runner{
+			Workers:  jobs,
+			Attempts: feedback.DefaultAttempts,
+			Sleep:    feedbackRetrySleep,
+			Apply: func(row feedback.Row) error {
+				mu.Lock()
+				change, ok := current[row.Line]
+				mu.Unlock()
+				if !ok {
+					var err error
+					if change, err = currentFeedback(client, row.Ref); err != nil {
+						return err
+					}
+					mu.Lock()
+					current[row.Line] = change
+					mu.Unlock()
+				}
+				change, err := sendFeedback(client, change, feedbackRequest{Ref: row.Ref, Vote: row.Vote, Reason: row.Reason})
+				if err == nil {
+					mu.Lock()
+					previous[row.Line] = change.Previous
+					mu.Unlock()
+				}
+				return err
+			},
+		}
+		if !jsonOut {
+			runner.Progress = func(result feedback.Result) {
+				mu.Lock()
+				was := previous[result.Row.Line]
+				mu.Unlock()
+				writeFeedbackApplyResult(out, result, dryRun, was)
+			}
+		}
+
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		results := runner.Run(ctx, rows)
+
+		status := "applied"
+		if dryRun {
+			status = "dry-run"
+		}
+		failed := 0
+		report := make([]feedbackApplyResult, len(results))
+		for i, result := range results {
+			report[i] = feedbackApplyResult{
+				Line:     result.Row.Line,
+				Ref:      result.Row.Ref,
+				Vote:     result.Row.Vote,
+				Reason:   result.Row.Reason,
+				Status:   status,
+				Attempts: result.Attempts,
+				Previous: previous[result.Row.Line],
+			}
+			if result.Err != nil {
+				failed++
+				report[i].Status = "failed"
+				report[i].Error = result.Err.Error()
+			}
+		}
+
+		if jsonOut {
+			if err := writeJSON(out, report); err != nil {
+				return err
+			}
+		} else if dryRun {
+			fmt.Fprintf(out, "\nDry run: %d would be applied, %d failed; nothing was sent\n", len(results)-failed, failed)
+		} else {
+			fmt.Fprintf(out, "\n%d applied, %d failed\n", len(results)-failed, failed)
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d feedback rows failed", failed, len(results))
+		}
+		return nil
+	},
+}
+
+func readFeedbackRows(stdin io.Reader, file, format string) ([]feedback.Row, error) {
+	in := stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open feedback file: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	rows, err := feedback.Parse(in, format)
+	var invalid *feedback.ValidationError
+	if errors.As(err, &invalid) {
+		return nil, fmt.Errorf("invalid feedback file, nothing was sent:\n  %s", terminalSafeBlock(invalid.Error(), "  "))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid feedback file: %w", err)
+	}
+	return rows, nil
+}
+
+func writeFeedbackApplyResult(out io.Writer, result feedback.Result, dryRun bool, previous *api.Feedback) {
+	ref := terminalSafeInline(result.Row.Ref)
+	switch {
+	case result.Err == nil && dryRun:
+		change := describeFeedback(&api.Feedback{Vote: result.Row.Vote, DownvoteReason: result.Row.Reason})
+		if result.Row.Vote == "clear" {
+			change = "none"
+		}
+		fmt.Fprintf(out, "Would set %s to %s (now %s)\n", ref, change, describeFeedback(previous))
+	case result.Err != nil:
+		fmt.Fprintf(out, "Failed   %s: %s\n", ref, terminalSafeInline(result.Err.Error()))
+	case result.Row.Vote == "clear":
+		fmt.Fprintf(out, "Cleared  %s\n", ref)
+	case result.Row.Reason != "":
+		fmt.Fprintf(out, "Set      %s %s (%s)\n", ref, result.Row.Vote, result.Row.Reason)
+	default:
+		fmt.Fprintf(out, "Set      %s %s\n", ref, result.Row.Vote)
+	}
+}
+
+// sharedAuth lets parallel requests share one session. The first request to
+// get a 401 refreshes it and the others retry with the new token, because
+// refresh tokens are single-use. It never prompts for a login.
+func sharedAuth(tokens *config.Tokens) requestAuth {
+	var mu sync.Mutex
+	return func(fn func(string) error) error {
+		mu.Lock()
+		at := tokens.AccessToken
+		mu.Unlock()
+
+		err := fn(at)
+		if !errors.Is(err, api.ErrUnauthorized) {
+			return err
+		}
+
+		mu.Lock()
+		if tokens.AccessToken == at {
+			if refreshErr := refreshSession(tokens); refreshErr != nil {
+				mu.Unlock()
+				return fmt.Errorf("session expired and could not be refreshed, run pz login: %w", refreshErr)
+			}
+		}
+		at = tokens.AccessToken
+		mu.Unlock()
+		return fn(at)
+	}
+}
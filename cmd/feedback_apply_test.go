@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/paperzilla/pz/internal/feedback"
+	"github.com/spf13/cobra"
+)
+
+func newFeedbackApplyTestCommand(file string, jsonOut bool, stdin string) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("file", file, "")
+	cmd.Flags().String("format", "", "")
+	cmd.Flags().Int("jobs", feedback.DefaultWorkers, "")
+	cmd.Flags().Bool("json", jsonOut, "")
+
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(io.Discard)
+	cmd.SetIn(strings.NewReader(stdin))
+	return cmd, &stdout
+}
+
+func TestFeedbackApplySendsRowsAndReportsFailures(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]string{}
+	flaky := 0
+	server := httptest.NewServer(withCurrentFeedback(t, `{"vote":"upvote"}`, func(w http.ResponseWriter, r *http.Request) {
+		ref := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/project-papers/"), "/feedback")
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests[ref] = r.Method + " " + string(body)
+		if ref == "pp-flaky" {
+			flaky++
+		}
+		attempt := flaky
+		mu.Unlock()
+
+		switch {
+		case ref == "pp-missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"detail":"Not found"}`))
+		case ref == "pp-flaky" && attempt == 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			_, _ = w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+	feedbackRetrySleep = func(context.Context, time.Duration) error { return nil }
+	t.Cleanup(func() { feedbackRetrySleep = nil })
+
+	path := filepath.Join(t.TempDir(), "votes.csv")
+	if err := os.WriteFile(path, []byte("ref,vote,reason\npp-1,downvote,not_relevant\npp-2,clear\npp-flaky,star\npp-missing,upvote\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, stdout := newFeedbackApplyTestCommand(path, false, "")
+	err := feedbackApplyCmd.RunE(cmd, nil)
+	if err == nil || err.Error() != "1 of 4 feedback rows failed" {
+		t.Fatalf("err = %v", err)
+	}
+
+	output := stdout.String()
+	for _, want := range []string{
+		"Set      pp-1 downvote (not_relevant)\n",
+		"Cleared  pp-2\n",
+		"Set      pp-flaky star\n",
+		"Failed   pp-missing: HTTP 404: Not found\n",
+		"\n3 applied, 1 failed\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if requests["pp-1"] != `PUT {"downvote_reason":"not_relevant","vote":"downvote"}` || requests["pp-2"] != "DELETE " || flaky != 2 {
+		t.Fatalf("requests = %v, flaky attempts = %d", requests, flaky)
+	}
+}
+
+func TestFeedbackApplyValidatesBeforeSending(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+
+	stdin := "{\"ref\":\"pp-1\",\"vote\":\"upvote\"}\n{\"ref\":\"pp-2\",\"vote\":\"love\"}\n"
+	cmd, _ := newFeedbackApplyTestCommand("-", false, stdin)
+	err := feedbackApplyCmd.RunE(cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "nothing was sent") || !strings.Contains(err.Error(), `line 2: invalid vote "love"`) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestFeedbackApplyJSONReport(t *testing.T) {
+	server := httptest.NewServer(withCurrentFeedback(t, "", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"vote":"star"}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+
+	cmd, stdout := newFeedbackApplyTestCommand("-", true, `{"ref":"pp-1","vote":"star"}`)
+	if err := feedbackApplyCmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	var report []feedbackApplyResult
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("Unmarshal: %v\n%s", err, stdout.String())
+	}
+	if len(report) != 1 || report[0] != (feedbackApplyResult{Line: 1, Ref: "pp-1", Vote: "star", Status: "applied", Attempts: 1}) {
+		t.Fatalf("report = %+v", report)
+	}
+}
+
+func TestFeedbackApplyRetryKeepsTheFeedbackReadBeforeTheFirstAttempt(t *testing.T) {
+	var mu sync.Mutex
+	current := `{"vote":"upvote"}`
+	reads, writes := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodGet {
+			reads++
+			_, _ = w.Write([]byte(`{"id":"pp-1","paper_title":"Paper","feedback":` + current + `}`))
+			return
+		}
+		// The first write reaches the server but its response is lost.
+		writes++
+		body, _ := io.ReadAll(r.Body)
+		current = string(body)
+		if writes == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+	feedbackRetrySleep = func(context.Context, time.Duration) error { return nil }
+	t.Cleanup(func() { feedbackRetrySleep = nil })
+
+	cmd, stdout := newFeedbackApplyTestCommand("-", true, `{"ref":"pp-1","vote":"star"}`)
+	if err := feedbackApplyCmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	var report []feedbackApplyResult
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("Unmarshal: %v\n%s", err, stdout.String())
+	}
+	if len(report) != 1 || report[0].Attempts != 2 || report[0].Previous == nil || report[0].Previous.Vote != "upvote" {
+		t.Fatalf("report = %+v", report)
+	}
+	if reads != 1 || writes != 2 {
+		t.Fatalf("reads = %d, writes = %d", reads, writes)
+	}
+
+	entries, err := (&feedback.Log{Path: os.Getenv("PZ_FEEDBACK_LOG_PATH")}).Entries()
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Previous == nil || entries[0].Previous.Vote != "upvote" {
+		t.Fatalf("log = %+v", entries)
+	}
+}
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/spf13/cobra"
+)
+
+const (
+	feedbackListFeedPageSize   = 50
+	feedbackListSearchPageSize = 100
+)
+
+func init() {
+	feedbackListCmd.Flags().String("vote", "", "Only list this vote (star, upvote or downvote)")
+	feedbackListCmd.Flags().String("reason", "", "Only list downvotes with this reason (not_relevant or low_quality)")
+	feedbackListCmd.Flags().StringP("query", "q", "", "Only list papers matching this search query")
+	feedbackListCmd.Flags().IntP("limit", "n", 0, "Maximum number of papers (default: all)")
+	feedbackListCmd.Flags().Bool("jsonl", false, "Output one JSON object per paper")
+	feedbackListCmd.Flags().Bool("csv", false, "Output CSV (ref, vote, reason, title, ...)")
+	feedbackCmd.AddCommand(feedbackListCmd)
+}
+
+type feedbackListResponse struct {
+	ProjectID string             `json:"project_id"`
+	Vote      string             `json:"vote,omitempty"`
+	Reason    string             `json:"reason,omitempty"`
+	Query     string             `json:"query,omitempty"`
+	Items     []api.ProjectPaper `json:"items"`
+}
+
+var feedbackListCmd = &cobra.Command{
+	Use:   "list <project-id>",
+	Short: "List the papers you rated in a project",
+	Long: "List every paper in a project's feed that has feedback, optionally only one\n" +
+		"vote or downvote reason. With --query the search endpoint does the\n" +
+		"filtering; without one the whole feed is paged through.\n\n" +
+		"The CSV output starts with ref,vote,reason, so an edited export can be fed\n" +
+		"back to pz feedback apply.",
+	Example: `  pz feedback list <project-id> --vote star
+  pz feedback list <project-id> --reason low_quality --csv > low-quality.csv
+  pz feedback list <project-id> --vote upvote --query "graph transformers" --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vote, _ := cmd.Flags().GetString("vote")
+		reason, _ := cmd.Flags().GetString("reason")
+		query, _ := cmd.Flags().GetString("query")
+		limit, _ := cmd.Flags().GetInt("limit")
+		jsonOut, _ := cmd.Flags().GetBool("json")
+		jsonlOut, _ := cmd.Flags().GetBool("jsonl")
+		csvOut, _ := cmd.Flags().GetBool("csv")
+
+		vote = strings.ToLower(strings.TrimSpace(vote))
+		reason = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(reason)), "-", "_")
+		switch vote {
+		case "", "star", "upvote", "downvote":
+		default:
+			return fmt.Errorf("invalid feedback list request: --vote must be star, upvote or downvote")
+		}
+		switch reason {
+		case "":
+		case "not_relevant", "low_quality":
+			if vote != "" && vote != "downvote" {
+				return fmt.Errorf("invalid feedback list request: --reason is only allowed with downvote")
+			}
+			vote = "downvote"
+		default:
+			return fmt.Errorf("invalid feedback list request: --reason must be not_relevant or low_quality")
+		}
+		if limit < 0 {
+			return fmt.Errorf("invalid feedback list request: limit must be at least 0")
+		}
+		formats := 0
+		for _, set := range []bool{jsonOut, jsonlOut, csvOut} {
+			if set {
+				formats++
+			}
+		}
+		if formats > 1 {
+			return fmt.Errorf("use only one of --json, --jsonl and --csv")
+		}
+		if query != "" {
+			normalized, err := api.NormalizeFeedSearchQuery(query)
+			if err != nil {
+				return fmt.Errorf("invalid search request: %w", err)
+			}
+			query = normalized
+		}
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+		projectID := args[0]
+		items, err := fetchRatedPapers(&tokens, projectID, query, vote, reason, limit)
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		switch {
+		case jsonOut:
+			return writeJSON(out, feedbackListResponse{ProjectID: projectID, Vote: vote, Reason: reason, Query: query, Items: items})
+		case jsonlOut:
+			for _, item := range items {
+				data, err := json.Marshal(item)
+				if err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+				fmt.Fprintln(out, string(data))
+			}
+			return nil
+		case csvOut:
+			return writeFeedbackCSV(out, items)
+		}
+
+		project, err := withAuth(&tokens, func(at string) (api.Project, error) {
+			return api.FetchProject(at, projectID)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch project: %w", err)
+		}
+		return writePaged(cmd, func(out io.Writer) error {
+			fmt.Fprintf(out, "%s — %d rated papers\n\n", terminalSafeInline(project.Name), len(items))
+			if len(items) == 0 {
+				fmt.Fprintln(out, "No papers with matching feedback.")
+			}
+			writeProjectPaperFeedList(out, items, nil)
+			return nil
+		})
+	},
+}
+
+// fetchRatedPapers collects feed items with feedback matching vote and
+// reason ("" for any). A query goes through feed search with the matching
+// feedback_filter; otherwise the whole feed is paged. Items are filtered
+// locally either way, since "liked" may also cover starred papers.
+func fetchRatedPapers(tokens *config.Tokens, projectID, query, vote, reason string, limit int) ([]api.ProjectPaper, error) {
+	items := []api.ProjectPaper{}
+	keep := func(page []api.ProjectPaper) bool {
+		for _, item := range page {
+			if matchesFeedback(item.Feedback, vote, reason) {
+				items = append(items, item)
+				if limit > 0 && len(items) >= limit {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	if query != "" {
+		for offset := 0; ; {
+			search, err := withAuth(tokens, func(at string) (api.FeedSearchResponse, error) {
+				return api.FetchFeedSearch(at, projectID, api.FeedSearchOptions{
+					Query:          query,
+					FeedbackFilter: feedbackSearchFilter(vote, reason),
+					Limit:          feedbackListSearchPageSize,
+					Offset:         offset,
+				})
+			})
+			if err != nil {
+				return nil, wrapFeedSearchError(err)
+			}
+			offset += len(search.Items)
+			if !keep(search.Items) || !search.HasMore || len(search.Items) == 0 {
+				return items, nil
+			}
+		}
+	}
+
+	for offset := 0; ; {
+		page, err := withAuth(tokens, func(at string) (api.FeedResponse, error) {
+			return api.FetchFeed(at, projectID, api.FeedOptions{Limit: feedbackListFeedPageSize, Offset: offset})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch feed: %w", err)
+		}
+		offset += len(page.Items)
+		if !keep(page.Items) || len(page.Items) == 0 || offset >= page.Total {
+			return items, nil
+		}
+	}
+}
+
+func matchesFeedback(f *api.Feedback, vote, reason string) bool {
+	if f == nil || f.Vote == "" {
+		return false
+	}
+	if vote != "" && f.Vote != vote {
+		return false
+	}
+	return reason == "" || f.DownvoteReason == reason
+}
+
+// feedbackSearchFilter maps a vote and reason to a feed search
+// feedback_filter value.
+func feedbackSearchFilter(vote, reason string) string {
+	switch {
+	case reason == "not_relevant":
+		return "not-relevant"
+	case reason == "low_quality":
+		return "low-quality"
+	case vote == "star":
+		return "starred"
+	case vote == "upvote":
+		return "liked"
+	case vote == "downvote":
+		return "disliked"
+	default:
+		return "all"
+	}
+}
+
+func writeFeedbackCSV(out io.Writer, items []api.ProjectPaper) error {
+	w := csv.NewWriter(out)
+	_ = w.Write([]string{"ref", "vote", "reason", "title", "authors", "year", "relevance", "url", "doi", "rated_at"})
+	for _, item := range items {
+		title := item.PaperTitle
+		if strings.TrimSpace(title) == "" {
+			title = item.Paper.Title
+		}
+		authors := make([]string, len(item.Paper.Authors))
+		for i, author := range item.Paper.Authors {
+			authors[i] = author.Name
+		}
+		year := ""
+		if len(item.Paper.PublishedDate) >= 4 {
+			year = item.Paper.PublishedDate[:4]
+		}
+		_ = w.Write([]string{
+			item.ID,
+			item.Feedback.Vote,
+			item.Feedback.DownvoteReason,
+			title,
+			strings.Join(authors, "; "),
+			year,
+			strconv.Itoa(int(item.RelevanceScore * 100)),
+			item.Paper.URL,
+			item.Paper.DOI,
+			item.Feedback.UpdatedAt,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
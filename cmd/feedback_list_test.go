@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/paperzilla/pz/internal/feedback"
+	"github.com/spf13/cobra"
+)
+
+func newFeedbackListTestCommand(flags map[string]string) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("vote", "", "")
+	cmd.Flags().String("reason", "", "")
+	cmd.Flags().String("query", "", "")
+	cmd.Flags().Int("limit", 0, "")
+	cmd.Flags().Bool("json", false, "")
+	cmd.Flags().Bool("jsonl", false, "")
+	cmd.Flags().Bool("csv", false, "")
+	for name, value := range flags {
+		_ = cmd.Flags().Set(name, value)
+	}
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	return cmd, &stdout
+}
+
+// ratedFeedItem gives every third item a star, every third a low-quality
+// downvote and leaves the rest unrated.
+func ratedFeedItem(i int) string {
+	feedback := "null"
+	switch i % 3 {
+	case 0:
+		feedback = `{"vote":"star","updated_at":"2026-10-01T00:00:00Z"}`
+	case 1:
+		feedback = `{"vote":"downvote","downvote_reason":"low_quality"}`
+	}
+	return fmt.Sprintf(`{"id":"pp-%d","paper_title":"Paper, %d","relevance_score":0.5,"feedback":%s,"paper":{"authors":[{"name":"Ada Lovelace"}],"published_date":"2025-01-01"}}`, i, i, feedback)
+}
+
+func TestFeedbackListPagesTheFeedWithoutQuery(t *testing.T) {
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/projects/proj-1/feed" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		offsets = append(offsets, r.URL.Query().Get("offset"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var items []string
+		for i := offset; i < min(offset+feedbackListFeedPageSize, 60); i++ {
+			items = append(items, ratedFeedItem(i))
+		}
+		fmt.Fprintf(w, `{"items":[%s],"total":60}`, strings.Join(items, ","))
+	}))
+	defer server.Close()
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+
+	cmd, stdout := newFeedbackListTestCommand(map[string]string{"vote": "star", "csv": "true"})
+	if err := feedbackListCmd.RunE(cmd, []string{"proj-1"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	if strings.Join(offsets, ",") != ",50" {
+		t.Fatalf("offsets = %v", offsets)
+	}
+
+	output := stdout.String()
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 21 || lines[0] != "ref,vote,reason,title,authors,year,relevance,url,doi,rated_at" ||
+		lines[1] != `pp-0,star,,"Paper, 0",Ada Lovelace,2025,50,,,2026-10-01T00:00:00Z` {
+		t.Fatalf("csv = %q", output)
+	}
+
+	// An export can be edited and fed straight back to pz feedback apply.
+	rows, err := feedback.Parse(strings.NewReader(output), feedback.FormatCSV)
+	if err != nil || len(rows) != 20 || rows[0].Ref != "pp-0" || rows[0].Vote != "star" {
+		t.Fatalf("rows = %+v, err = %v", rows, err)
+	}
+}
+
+func TestFeedbackListUsesSearchFilterWithQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/projects/proj-1/feed/search":
+			if got := r.URL.Query().Get("feedback_filter"); got != "low-quality" {
+				t.Fatalf("feedback_filter = %q", got)
+			}
+			if got := r.URL.Query().Get("q"); got != "graph transformers" {
+				t.Fatalf("q = %q", got)
+			}
+			fmt.Fprintf(w, `{"items":[%s,%s],"has_more":false}`, ratedFeedItem(1), ratedFeedItem(3))
+		case "/api/projects/proj-1":
+			_, _ = w.Write([]byte(`{"id":"proj-1","name":"Test Project"}`))
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+
+	cmd, stdout := newFeedbackListTestCommand(map[string]string{"reason": "low-quality", "query": "graph transformers"})
+	if err := feedbackListCmd.RunE(cmd, []string{"proj-1"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	output := stdout.String()
+	if !strings.Contains(output, "Test Project — 1 rated papers") || !strings.Contains(output, "[↓]  Paper, 1") || strings.Contains(output, "Paper, 3") {
+		t.Fatalf("output = %q", output)
+	}
+
+	cmd, _ = newFeedbackListTestCommand(map[string]string{"vote": "star", "reason": "low_quality"})
+	if err := feedbackListCmd.RunE(cmd, []string{"proj-1"}); err == nil || !strings.Contains(err.Error(), "only allowed with downvote") {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/paperzilla/pz/internal/feedback"
+	"github.com/spf13/cobra"
+)
+
+const defaultFeedbackLogLimit = 20
+
+func init() {
+	feedbackLogCmd.Flags().IntP("limit", "n", defaultFeedbackLogLimit, "Number of changes to show (0 for all)")
+	feedbackUndoCmd.Flags().Int("last", 1, "Number of changes to undo")
+	feedbackUndoCmd.Flags().Bool("dry-run", false, "Show what would be restored without sending it")
+	feedbackUndoCmd.Flags().Bool("force", false, "Restore even where the feedback was changed again since")
+	feedbackCmd.AddCommand(feedbackLogCmd, feedbackUndoCmd)
+}
+
+var feedbackLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show feedback changes made with pz, newest first",
+	Long: "Show the feedback changes this account made with pz on this machine,\n" +
+		"newest first. Every change is kept in ~/.paperzilla/feedback-log.jsonl with\n" +
+		"the feedback before and after, so it can be reverted with pz feedback undo.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		jsonOut, _ := cmd.Flags().GetBool("json")
+		if limit < 0 {
+			return fmt.Errorf("invalid feedback log request: limit must be at least 0")
+		}
+
+		tokens, err := config.LoadTokens()
+		if err != nil {
+			return fmt.Errorf("not logged in; run pz login")
+		}
+		log := &feedback.Log{Path: config.FeedbackLogPath()}
+		entries, err := log.Entries()
+		if err != nil {
+			return err
+		}
+
+		profile := tokens.Account()
+		recent := []feedback.LogEntry{}
+		for i := len(entries) - 1; i >= 0 && (limit == 0 || len(recent) < limit); i-- {
+			if entries[i].Profile == profile {
+				recent = append(recent, entries[i])
+			}
+		}
+
+		out := cmd.OutOrStdout()
+		if jsonOut {
+			return writeJSON(out, recent)
+		}
+		if len(recent) == 0 {
+			fmt.Fprintln(out, "No feedback changes recorded yet.")
+			return nil
+		}
+		return writePaged(cmd, func(out io.Writer) error {
+			for _, e := range recent {
+				writeFeedbackLogEntry(out, e)
+			}
+			return nil
+		})
+	},
+}
+
+var feedbackUndoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Restore the feedback from before your most recent changes",
+	Long: "Restore the feedback that recommendations had before the most recent\n" +
+		"changes in pz feedback log, newest first. Undos are logged too, but are\n" +
+		"never undone themselves, so running undo again goes further back.\n\n" +
+		"A change whose feedback was changed again since, on the web or in\n" +
+		"another tool, is skipped and stays first in line, so undo fails until\n" +
+		"it is restored with --force.",
+	Example: `  pz feedback undo
+  pz feedback undo --last 20 --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		last, _ := cmd.Flags().GetInt("last")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		force, _ := cmd.Flags().GetBool("force")
+		jsonOut, _ := cmd.Flags().GetBool("json")
+		if last < 1 {
+			return fmt.Errorf("invalid undo request: --last must be at least 1")
+		}
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+		client := newFeedbackClient(tokenAuth(&tokens), tokens, cmd.ErrOrStderr())
+		client.dryRun = dryRun
+		entries, err := client.log.Entries()
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		undo := feedback.Undoable(entries, client.profile, last)
+		if len(undo) == 0 {
+			if jsonOut {
+				return writeJSON(out, []feedbackChange{})
+			}
+			fmt.Fprintln(out, "Nothing to undo.")
+			return nil
+		}
+
+		changes := []feedbackChange{}
+		failed, skipped := 0, 0
+		for _, e := range undo {
+			req := feedbackRequest{Ref: e.Ref, Vote: "clear", Undoes: e.ID}
+			if e.Previous != nil && e.Previous.Vote != "" {
+				req.Vote, req.Reason = e.Previous.Vote, e.Previous.DownvoteReason
+			}
+			change, err := currentFeedback(client, e.Ref)
+			if err == nil && !force && !sameFeedback(change.Previous, e.New) {
+				skipped++
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: skipped %s: its feedback is now %s, not %s\n",
+					terminalSafeInline(e.Ref), describeFeedback(change.Previous), describeFeedback(e.New))
+				continue
+			}
+			if err == nil {
+				change, err = sendFeedback(client, change, req)
+			}
+			if err != nil {
+				failed++
+				if !jsonOut {
+					fmt.Fprintf(out, "Failed    %s: %s\n", terminalSafeInline(e.Ref), terminalSafeInline(err.Error()))
+				}
+				continue
+			}
+			changes = append(changes, change)
+			if jsonOut {
+				continue
+			}
+			verb := "Restored "
+			if dryRun {
+				verb = "Would set"
+			}
+			fmt.Fprintf(out, "%s %s to %s (now %s)\n", verb, terminalSafeInline(change.Ref), describeFeedback(change.New), describeFeedback(change.Previous))
+		}
+
+		if jsonOut {
+			if err := writeJSON(out, changes); err != nil {
+				return err
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d undos failed", failed, len(undo))
+		}
+		if skipped > 0 {
+			return fmt.Errorf("%d of %d undos skipped because the feedback was changed since; pass --force to restore it anyway", skipped, len(undo))
+		}
+		return nil
+	},
+}
+
+func writeFeedbackLogEntry(out io.Writer, e feedback.LogEntry) {
+	line := fmt.Sprintf("%s  %s  %s → %s", e.Time.Local().Format("2006-01-02 15:04"), terminalSafeInline(e.Ref), describeFeedback(e.Previous), describeFeedback(e.New))
+	if e.Undoes != "" {
+		line += "  (undo)"
+	}
+	if e.Title != "" {
+		line += "  " + terminalSafeInline(e.Title)
+	}
+	fmt.Fprintln(out, line)
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// newFeedbackStateServer keeps feedback per project paper and records every
+// change request.
+func newFeedbackStateServer(t *testing.T, state map[string]string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		path := strings.TrimPrefix(r.URL.Path, "/api/project-papers/")
+		ref := strings.TrimSuffix(path, "/feedback")
+		switch {
+		case r.Method == http.MethodGet && ref == path:
+			current := "null"
+			if state[ref] != "" {
+				current = state[ref]
+			}
+			_, _ = w.Write([]byte(`{"id":"` + ref + `","paper_title":"Paper ` + ref + `","feedback":` + current + `}`))
+		case r.Method == http.MethodDelete:
+			calls = append(calls, "DELETE "+ref)
+			delete(state, ref)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPut:
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			calls = append(calls, strings.TrimSpace("PUT "+ref+" "+body["vote"]+" "+body["downvote_reason"]))
+			data, _ := json.Marshal(body)
+			state[ref] = string(data)
+			_, _ = w.Write(data)
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func runFeedbackCommand(t *testing.T, command *cobra.Command, args []string, flags map[string]string) string {
+	t.Helper()
+	cmd, stdout := newFeedbackLogTestCommand(t, flags)
+	if err := command.RunE(cmd, args); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	return stdout.String()
+}
+
+func newFeedbackLogTestCommand(t *testing.T, flags map[string]string) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.Flags().String("reason", "", "")
+	cmd.Flags().Bool("json", false, "")
+	cmd.Flags().Bool("dry-run", false, "")
+	cmd.Flags().Bool("force", false, "")
+	cmd.Flags().Int("last", 1, "")
+	cmd.Flags().Int("limit", defaultFeedbackLogLimit, "")
+	for name, value := range flags {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("Set %s: %v", name, err)
+		}
+	}
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(io.Discard)
+	return cmd, &stdout
+}
+
+func TestFeedbackLogAndUndoRestorePreviousState(t *testing.T) {
+	server, calls := newFeedbackStateServer(t, map[string]string{
+		"pp-1": `{"vote":"upvote"}`,
+		"pp-2": `{"vote":"star"}`,
+	})
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+
+	runFeedbackCommand(t, feedbackCmd, []string{"pp-1", "downvote"}, map[string]string{"reason": "low_quality"})
+	runFeedbackCommand(t, feedbackClearCmd, []string{"pp-2"}, nil)
+	runFeedbackCommand(t, feedbackCmd, []string{"pp-3", "star"}, map[string]string{"dry-run": "true"})
+
+	log := runFeedbackCommand(t, feedbackLogCmd, nil, nil)
+	lines := strings.Split(strings.TrimSpace(log), "\n")
+	if len(lines) != 2 ||
+		!strings.HasSuffix(lines[0], "  pp-2  star → none  Paper pp-2") ||
+		!strings.HasSuffix(lines[1], "  pp-1  upvote → downvote (low_quality)  Paper pp-1") {
+		t.Fatalf("log = %q", log)
+	}
+
+	dryRun := runFeedbackCommand(t, feedbackUndoCmd, nil, map[string]string{"last": "5", "dry-run": "true"})
+	if dryRun != "Would set pp-2 to star (now none)\nWould set pp-1 to upvote (now downvote (low_quality))\n" {
+		t.Fatalf("dry run = %q", dryRun)
+	}
+
+	undo := runFeedbackCommand(t, feedbackUndoCmd, nil, map[string]string{"last": "5"})
+	if undo != "Restored  pp-2 to star (now none)\nRestored  pp-1 to upvote (now downvote (low_quality))\n" {
+		t.Fatalf("undo = %q", undo)
+	}
+	want := []string{"PUT pp-1 downvote low_quality", "DELETE pp-2", "PUT pp-2 star", "PUT pp-1 upvote"}
+	if strings.Join(*calls, "|") != strings.Join(want, "|") {
+		t.Fatalf("calls = %q, want %q", *calls, want)
+	}
+
+	if again := runFeedbackCommand(t, feedbackUndoCmd, nil, nil); again != "Nothing to undo.\n" {
+		t.Fatalf("second undo = %q", again)
+	}
+	if log := runFeedbackCommand(t, feedbackLogCmd, nil, map[string]string{"limit": "1"}); !strings.Contains(log, "pp-1  downvote (low_quality) → upvote  (undo)") {
+		t.Fatalf("log after undo = %q", log)
+	}
+}
+
+func TestFeedbackUndoSkipsFeedbackChangedSince(t *testing.T) {
+	server, calls := newFeedbackStateServer(t, map[string]string{"pp-1": `{"vote":"upvote"}`})
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+
+	runFeedbackCommand(t, feedbackCmd, []string{"pp-1", "downvote"}, map[string]string{"reason": "low_quality"})
+	// The paper is starred on the web, outside pz.
+	req, _ := http.NewRequest(http.MethodPut, server.URL+"/api/project-papers/pp-1/feedback", strings.NewReader(`{"vote":"star"}`))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("PUT: %v", err)
+	}
+	resp.Body.Close()
+
+	// Undo keeps failing on the drifted change instead of quietly doing
+	// nothing, however often it is run.
+	for range 2 {
+		cmd, stdout := newFeedbackLogTestCommand(t, nil)
+		var stderr bytes.Buffer
+		cmd.SetErr(&stderr)
+		err := feedbackUndoCmd.RunE(cmd, nil)
+		if err == nil || err.Error() != "1 of 1 undos skipped because the feedback was changed since; pass --force to restore it anyway" {
+			t.Fatalf("err = %v", err)
+		}
+		if stdout.String() != "" || stderr.String() != "Warning: skipped pp-1: its feedback is now star, not downvote (low_quality)\n" {
+			t.Fatalf("stdout = %q, stderr = %q", stdout.String(), stderr.String())
+		}
+	}
+
+	if undo := runFeedbackCommand(t, feedbackUndoCmd, nil, map[string]string{"force": "true"}); undo != "Restored  pp-1 to upvote (now star)\n" {
+		t.Fatalf("forced undo = %q", undo)
+	}
+	want := []string{"PUT pp-1 downvote low_quality", "PUT pp-1 star", "PUT pp-1 upvote"}
+	if strings.Join(*calls, "|") != strings.Join(want, "|") {
+		t.Fatalf("calls = %q, want %q", *calls, want)
+	}
+}
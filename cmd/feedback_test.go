@@ -14,7 +14,7 @@ import (
 )
 
 func TestFeedbackCommandSetsFeedback(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	server := httptest.NewServer(withCurrentFeedback(t, "", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPut {
 			t.Fatalf("method = %s, want PUT", r.Method)
 		}
@@ -43,7 +43,7 @@ func TestFeedbackCommandSetsFeedback(t *testing.T) {
 }
 
 func TestFeedbackCommandClear(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	server := httptest.NewServer(withCurrentFeedback(t, "", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodDelete {
 			t.Fatalf("method = %s, want DELETE", r.Method)
 		}
@@ -80,7 +80,7 @@ func TestFeedbackCommandRejectsReasonForStar(t *testing.T) {
 }
 
 func TestFeedbackCommandJSON(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	server := httptest.NewServer(withCurrentFeedback(t, "", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPut {
 			t.Fatalf("method = %s, want PUT", r.Method)
 		}
@@ -109,7 +109,7 @@ func TestFeedbackCommandJSON(t *testing.T) {
 }
 
 func TestFeedbackClearCommandJSON(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	server := httptest.NewServer(withCurrentFeedback(t, "", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodDelete {
 			t.Fatalf("method = %s, want DELETE", r.Method)
 		}
@@ -151,3 +151,20 @@ func newFeedbackTestCommand(reason string, jsonOut bool) (*cobra.Command, *bytes
 	cmd.SetErr(&stderr)
 	return cmd, &stdout, &stderr
 }
+
+// withCurrentFeedback answers the project paper lookup that precedes every
+// feedback change with the given feedback JSON ("" for none).
+func withCurrentFeedback(t *testing.T, current string, next http.HandlerFunc) http.HandlerFunc {
+	t.Helper()
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && !strings.HasSuffix(r.URL.Path, "/feedback") {
+			id := strings.TrimPrefix(r.URL.Path, "/api/project-papers/")
+			if current == "" {
+				current = "null"
+			}
+			_, _ = w.Write([]byte(`{"id":"` + id + `","paper_title":"Paper ` + id + `","feedback":` + current + `}`))
+			return
+		}
+		next(w, r)
+	}
+}
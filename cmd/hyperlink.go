@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/config"
+)
+
+var hyperlinksEnabledFunc = hyperlinksEnabled
+
+// hyperlinksEnabled reports whether OSC 8 hyperlinks may be written to w.
+// They follow the color rules (no pipes, NO_COLOR or TERM=dumb) and can be
+// switched off with PZ_HYPERLINKS=0 for terminals that print them verbatim.
+func hyperlinksEnabled(w io.Writer) bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("PZ_HYPERLINKS"))) {
+	case "0", "false", "no", "off":
+		return false
+	}
+	file, ok := terminalFile(w)
+	return ok && supportsColor(file)
+}
+
+// linkerFor returns a function that wraps already terminal-safe text in a
+// hyperlink to target, or returns the text unchanged when links are off.
+func linkerFor(w io.Writer) func(text, target string) string {
+	if !hyperlinksEnabledFunc(w) {
+		return func(text, _ string) string { return text }
+	}
+	return hyperlink
+}
+
+// hyperlink emits an OSC 8 link. Targets that are not plain http(s) URLs
+// made of printable ASCII are dropped, so server-supplied values can never
+// terminate the sequence early or smuggle in controls of their own.
+func hyperlink(text, target string) string {
+	safe, ok := safeLinkTarget(target)
+	if !ok || text == "" {
+		return text
+	}
+	return "\x1b]8;;" + safe + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+func safeLinkTarget(raw string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", false
+	}
+	target := u.String()
+	for i := 0; i < len(target); i++ {
+		if target[i] <= ' ' || target[i] >= 0x7f {
+			return "", false
+		}
+	}
+	return target, true
+}
+
+// doiURL resolves a bare or prefixed DOI to its doi.org URL.
+func doiURL(doi string) string {
+	doi = strings.TrimSpace(doi)
+	for _, prefix := range []string{"https://doi.org/", "http://doi.org/", "https://dx.doi.org/", "doi:"} {
+		if len(doi) >= len(prefix) && strings.EqualFold(doi[:len(prefix)], prefix) {
+			doi = doi[len(prefix):]
+			break
+		}
+	}
+	if doi == "" {
+		return ""
+	}
+	return (&url.URL{Scheme: "https", Host: "doi.org", Path: doi}).String()
+}
+
+// paperWebURL points at the Paperzilla page of a canonical paper.
+func paperWebURL(paper api.Paper) string {
+	ref := strings.TrimSpace(paper.ShortID)
+	if ref == "" {
+		ref = strings.TrimSpace(paper.ID)
+	}
+	if ref == "" {
+		return ""
+	}
+	return strings.TrimRight(config.APIURL(), "/") + "/papers/" + url.PathEscape(ref)
+}
+
+// recommendationWebURL points at the Paperzilla web view of a recommendation.
+func recommendationWebURL(ref string) string {
+	if strings.TrimSpace(ref) == "" {
+		return ""
+	}
+	return strings.TrimRight(config.APIURL(), "/") + "/recommendations/" + url.PathEscape(ref)
+}
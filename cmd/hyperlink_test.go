@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/paperzilla/pz/internal/api"
+)
+
+func TestHyperlinkWrapsSafeTargets(t *testing.T) {
+	got := hyperlink("Paper", "https://arxiv.org/abs/2401.00001")
+	want := "\x1b]8;;https://arxiv.org/abs/2401.00001\x1b\\Paper\x1b]8;;\x1b\\"
+	if got != want {
+		t.Fatalf("hyperlink = %q, want %q", got, want)
+	}
+}
+
+func TestHyperlinkRejectsUnsafeTargets(t *testing.T) {
+	for _, target := range []string{
+		"",
+		"javascript:alert(1)",
+		"file:///etc/passwd",
+		"https://example.com/\x1b\\\x1b]0;pwned\a",
+		"//example.com/no-scheme",
+	} {
+		if got := hyperlink("text", target); got != "text" {
+			t.Errorf("hyperlink(%q) = %q, want plain text", target, got)
+		}
+	}
+}
+
+func TestHyperlinkPercentEncodesTargets(t *testing.T) {
+	got := hyperlink("text", "https://example.com/\u009b31m a")
+	want := "\x1b]8;;https://example.com/%C2%9B31m%20a\x1b\\text\x1b]8;;\x1b\\"
+	if got != want {
+		t.Fatalf("hyperlink = %q, want %q", got, want)
+	}
+}
+
+func TestDOIURL(t *testing.T) {
+	tests := map[string]string{
+		"10.1000/xyz123":                "https://doi.org/10.1000/xyz123",
+		"doi:10.1000/xyz123":            "https://doi.org/10.1000/xyz123",
+		"https://doi.org/10.1000/ab(1)": "https://doi.org/10.1000/ab%281%29",
+		"10.1000/a b":                   "https://doi.org/10.1000/a%20b",
+		"  ":                            "",
+	}
+	for input, want := range tests {
+		if got := doiURL(input); got != want {
+			t.Errorf("doiURL(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestHyperlinksDisabledForPipesAndOptOut(t *testing.T) {
+	if hyperlinksEnabled(&bytes.Buffer{}) {
+		t.Fatal("hyperlinks should be disabled for non-terminal output")
+	}
+	t.Setenv("PZ_HYPERLINKS", "0")
+	if hyperlinksEnabled(&bytes.Buffer{}) {
+		t.Fatal("PZ_HYPERLINKS=0 should disable hyperlinks")
+	}
+}
+
+func TestWriteProjectPaperLinksTitleDOIAndRecommendation(t *testing.T) {
+	t.Setenv("PZ_API_URL", "https://paperzilla.example")
+	stubHyperlinks(t, true)
+
+	var out bytes.Buffer
+	writeProjectPaper(&out, api.ProjectPaper{
+		ID:         "pp-1",
+		PaperTitle: "Title\x1b]8;;https://evil.example\x1b\\",
+		Paper: api.Paper{
+			URL:    "https://arxiv.org/abs/1",
+			PdfURL: "https://evil.example/\x1b]0;x\a",
+			DOI:    "10.1000/xyz",
+		},
+	})
+
+	got := out.String()
+	for _, want := range []string{
+		"\x1b]8;;https://arxiv.org/abs/1\x1b\\Title\\x1b]8;;https://evil.example\\x1b\\\x1b]8;;\x1b\\",
+		"\x1b]8;;https://paperzilla.example/recommendations/pp-1\x1b\\pp-1\x1b]8;;\x1b\\",
+		"\x1b]8;;https://doi.org/10.1000/xyz\x1b\\10.1000/xyz\x1b]8;;\x1b\\",
+		"PDF URL:         https://evil.example/\\x1b]0;x\\x07\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%q", want, got)
+		}
+	}
+}
+
+func TestWriteCanonicalPaperHasNoLinksWhenDisabled(t *testing.T) {
+	stubHyperlinks(t, false)
+
+	var out bytes.Buffer
+	writeCanonicalPaper(&out, api.Paper{Title: "Title", URL: "https://arxiv.org/abs/1", DOI: "10.1000/xyz"})
+	if strings.Contains(out.String(), "\x1b") {
+		t.Fatalf("output contains escape sequences: %q", out.String())
+	}
+}
+
+func stubHyperlinks(t *testing.T, enabled bool) {
+	t.Helper()
+	original := hyperlinksEnabledFunc
+	hyperlinksEnabledFunc = func(io.Writer) bool { return enabled }
+	t.Cleanup(func() { hyperlinksEnabledFunc = original })
+}
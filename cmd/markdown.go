@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/paperzilla/pz/internal/mdrender"
+	"github.com/spf13/cobra"
+)
+
+const defaultRenderWidth = 80
+
+// markdownRequested reports whether cmd should print paper markdown, either
+// via --markdown or an explicit --render.
+func markdownRequested(cmd *cobra.Command) bool {
+	markdownOut, _ := cmd.Flags().GetBool("markdown")
+	renderOut, _ := cmd.Flags().GetBool("render")
+	return markdownOut || (cmd.Flags().Changed("render") && renderOut)
+}
+
+// shouldRenderMarkdown renders by default only when stdout is a terminal, so
+// pipes and redirects keep receiving the raw markdown bytes.
+func shouldRenderMarkdown(cmd *cobra.Command) bool {
+	if cmd.Flags().Changed("render") {
+		renderOut, _ := cmd.Flags().GetBool("render")
+		return renderOut
+	}
+	_, ok := terminalFile(cmd.OutOrStdout())
+	return ok
+}
+
+func writePaperMarkdown(cmd *cobra.Command, markdown string) error {
+	if !shouldRenderMarkdown(cmd) {
+		fmt.Fprint(cmd.OutOrStdout(), markdown)
+		return nil
+	}
+
+	return writePaged(cmd, func(out io.Writer) error {
+		width := outputWidth(out)
+		if width <= 0 {
+			width = defaultRenderWidth
+		}
+		file, isTerminal := terminalFile(out)
+		_, err := io.WriteString(out, mdrender.Render(markdown, mdrender.Options{
+			Width:  width,
+			Color:  isTerminal && supportsColor(file),
+			Escape: terminalSafeInline,
+		}))
+		return err
+	})
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/paperzilla/pz/internal/notify"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	notifyCmd.AddCommand(notifyTestCmd)
+}
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Manage notifications for new papers",
+	Long: "Post new must-read papers to Slack, Mattermost or any JSON webhook.\n\n" +
+		"Sinks are configured under \"notify\" in ~/.paperzilla/config.json:\n\n" +
+		"  {\"notify\": [\n" +
+		"    {\"name\": \"team\", \"type\": \"slack\", \"url\": \"https://hooks.slack.com/services/...\"},\n" +
+		"    {\"name\": \"ci\", \"type\": \"webhook\", \"url\": \"https://example.com/hook\",\n" +
+		"     \"projects\": [\"<project-id>\"], \"include_related\": true}\n" +
+		"  ]}\n\n" +
+		"Run pz watch --notify to deliver new papers. Each paper is announced at\n" +
+		"most once per sink.",
+}
+
+var notifyTestCmd = &cobra.Command{
+	Use:   "test [sink-name...]",
+	Short: "Send a test message to notification sinks",
+	Example: `  pz notify test
+  pz notify test team`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sinks, err := loadNotifySinks()
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			if sinks, err = selectNotifySinks(sinks, args); err != nil {
+				return err
+			}
+		}
+
+		out := cmd.OutOrStdout()
+		n := newNotifier(sinks, nil, func(format string, args ...any) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s\n", terminalSafeInline(fmt.Sprintf(format, args...)))
+		})
+		failed := 0
+		for _, sink := range sinks {
+			label := fmt.Sprintf("%s (%s, %s)", sink.Name, sink.Type, sinkHost(sink.URL))
+			if err := n.Send(context.Background(), sink, testNotification()); err != nil {
+				failed++
+				fmt.Fprintf(out, "Failed     %s: %s\n", terminalSafeInline(label), terminalSafeInline(err.Error()))
+				continue
+			}
+			fmt.Fprintf(out, "Delivered  %s\n", terminalSafeInline(label))
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d sinks failed", failed, len(sinks))
+		}
+		return nil
+	},
+}
+
+func loadNotifySinks() ([]config.NotifySink, error) {
+	settings, err := loadSettingsFunc()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+	if len(settings.Notify) == 0 {
+		return nil, fmt.Errorf("no notification sinks configured; add them under \"notify\" in ~/.paperzilla/config.json (see pz notify --help)")
+	}
+	if err := notify.Validate(settings.Notify); err != nil {
+		return nil, fmt.Errorf("invalid settings: %w", err)
+	}
+	return settings.Notify, nil
+}
+
+func selectNotifySinks(sinks []config.NotifySink, names []string) ([]config.NotifySink, error) {
+	var selected []config.NotifySink
+	for _, name := range names {
+		found := false
+		for _, sink := range sinks {
+			if sink.Name == name {
+				selected = append(selected, sink)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("notification sink %q not found", name)
+		}
+	}
+	return selected, nil
+}
+
+func newNotifier(sinks []config.NotifySink, state *notify.State, logf func(string, ...any)) *notify.Notifier {
+	return &notify.Notifier{
+		Sinks:     sinks,
+		State:     state,
+		UserAgent: api.UserAgent(),
+		Logf:      logf,
+	}
+}
+
+// sinkHost identifies a sink in output without printing its URL, which
+// usually embeds a secret token.
+func sinkHost(raw string) string {
+	if u, err := url.Parse(raw); err == nil {
+		return u.Host
+	}
+	return ""
+}
+
+func notificationMessage(projectID, projectName string, item api.ProjectPaper) notify.Message {
+	links := notify.Links{}
+	if target, ok := safeLinkTarget(recommendationWebURL(item.ID)); ok {
+		links.Paperzilla = target
+	}
+	if target, ok := safeLinkTarget(item.Paper.URL); ok {
+		links.Paper = target
+	}
+	if target, ok := safeLinkTarget(item.Paper.PdfURL); ok {
+		links.PDF = target
+	}
+	return notify.Message{ProjectID: projectID, ProjectName: projectName, Item: item, Links: links}
+}
+
+func testNotification() notify.Message {
+	return notificationMessage("", "pz notify test", api.ProjectPaper{
+		PaperTitle:     "Paperzilla test notification",
+		Summary:        "If you can read this, new must-read papers will be posted here.",
+		RelevanceClass: 2,
+	})
+}
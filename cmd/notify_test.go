@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func stubNotifySinks(t *testing.T, sinks ...config.NotifySink) {
+	t.Helper()
+	stubSettings(t, config.Settings{Notify: sinks})
+}
+
+func TestNotifyTestReportsEachSink(t *testing.T) {
+	var texts []string
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			http.Error(w, "no_service", http.StatusNotFound)
+			return
+		}
+		var payload struct{ Text string }
+		json.NewDecoder(r.Body).Decode(&payload)
+		texts = append(texts, payload.Text)
+	}))
+	defer hook.Close()
+	stubNotifySinks(t,
+		config.NotifySink{Name: "team", Type: "slack", URL: hook.URL + "/team"},
+		config.NotifySink{Name: "old", Type: "mattermost", URL: hook.URL + "/broken"},
+	)
+
+	var stdout bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&stdout)
+	cmd.SetErr(io.Discard)
+	err := notifyTestCmd.RunE(cmd, nil)
+	if err == nil || err.Error() != "1 of 2 sinks failed" {
+		t.Fatalf("err = %v", err)
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "Delivered  team (slack, 127.0.0.1:") || !strings.Contains(out, "Failed     old (mattermost, 127.0.0.1:") ||
+		!strings.Contains(out, "HTTP 404: no_service") || strings.Contains(out, "/team") {
+		t.Fatalf("output:\n%s", out)
+	}
+	if len(texts) != 1 || !strings.Contains(texts[0], "Paperzilla test notification") {
+		t.Fatalf("texts = %q", texts)
+	}
+
+	if err := notifyTestCmd.RunE(cmd, []string{"missing"}); err == nil || !strings.Contains(err.Error(), `"missing" not found`) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestNotifyRequiresValidSinks(t *testing.T) {
+	stubNotifySinks(t)
+	if _, err := loadNotifySinks(); err == nil || !strings.Contains(err.Error(), "no notification sinks configured") {
+		t.Fatalf("err = %v", err)
+	}
+	stubNotifySinks(t, config.NotifySink{Name: "team", Type: "slack", URL: "ftp://example.com"})
+	if _, err := loadNotifySinks(); err == nil || !strings.Contains(err.Error(), "http(s) URL") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestWatchNotifyAnnouncesMustReadsOnce(t *testing.T) {
+	var posts []string
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Title string `json:"title"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		posts = append(posts, payload.Title)
+	}))
+	defer hook.Close()
+	stubNotifySinks(t, config.NotifySink{Name: "ci", Type: "webhook", URL: hook.URL})
+
+	feed := `{"items":[],"total":0}`
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/projects":
+			w.Write([]byte(`[{"id":"proj-1","name":"Graph Learning"}]`))
+		case "/api/projects/proj-1/feed":
+			w.Write([]byte(feed))
+		}
+	}))
+	defer api.Close()
+	t.Setenv("PZ_API_URL", api.URL)
+	t.Setenv("PZ_WATCH_STATE_PATH", filepath.Join(t.TempDir(), "watch.json"))
+	t.Setenv("PZ_NOTIFY_STATE_PATH", filepath.Join(t.TempDir(), "notified.json"))
+	writeTestTokens(t)
+
+	run := func() {
+		cmd := &cobra.Command{}
+		cmd.Flags().Duration("interval", defaultWatchInterval, "")
+		cmd.Flags().BoolP("must-read", "m", false, "")
+		cmd.Flags().Bool("jsonl", false, "")
+		cmd.Flags().String("exec", "", "")
+		cmd.Flags().Bool("notify", false, "")
+		cmd.Flags().Bool("once", false, "")
+		cmd.Flags().Parse([]string{"--notify", "--once"})
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		if err := watchCmd.RunE(cmd, nil); err != nil {
+			t.Fatalf("RunE: %v", err)
+		}
+	}
+
+	run()
+	feed = `{"items":[
+		{"id":"pp-1","paper_title":"Must read","relevance_class":2,"ready_at":"2026-01-02T00:00:00Z","paper":{"id":"paper-1"}},
+		{"id":"pp-2","paper_title":"Related","relevance_class":1,"ready_at":"2026-01-02T00:00:00Z","paper":{"id":"paper-2"}}
+	],"total":2}`
+	run()
+	run()
+	if len(posts) != 1 || posts[0] != "Must read" {
+		t.Fatalf("posts = %q", posts)
+	}
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	openURLFunc   = openURL
+	lookPathFunc  = exec.LookPath
+	runOpenerFunc = runOpener
+)
+
+var errNoURLOpener = errors.New("no browser opener found")
+
+var openTargetKinds = []string{"pdf", "doi", "web"}
+
+func init() {
+	openCmd.Flags().Bool("pdf", false, "Open the PDF")
+	openCmd.Flags().Bool("doi", false, "Open the DOI on doi.org")
+	openCmd.Flags().Bool("web", false, "Open the Paperzilla web page")
+	openCmd.Flags().Bool("print", false, "Print the URL instead of opening it")
+	openCmd.MarkFlagsMutuallyExclusive(openTargetKinds...)
+}
+
+var openCmd = &cobra.Command{
+	Use:   "open <paper-ref|project-paper-ref>",
+	Short: "Open a paper, its PDF or DOI, or a recommendation in the browser",
+	Long: "Open a paper, its PDF or DOI, or a recommendation in the browser.\n\n" +
+		"The browser is $BROWSER, then the \"browser\" config setting, then the\n" +
+		"platform default (xdg-open, open or rundll32). When none is available\n" +
+		"the URL is printed instead.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kind := ""
+		for _, name := range openTargetKinds {
+			if value, _ := cmd.Flags().GetBool(name); value {
+				kind = name
+			}
+		}
+		printOnly, _ := cmd.Flags().GetBool("print")
+
+		tokens, hasAuth, err := loadOptionalAuth()
+		if err != nil {
+			return err
+		}
+
+		var projectPaperID string
+		fetchProjectPaper := projectPaperLookup(&tokens, &hasAuth)
+		target, err := resolveOpenTarget(args[0], kind, func(ref string) (api.ProjectPaper, error) {
+			projectPaper, err := fetchProjectPaper(ref)
+			projectPaperID = projectPaper.ID
+			return projectPaper, err
+		})
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		if printOnly {
+			fmt.Fprintln(out, terminalSafeInline(target))
+			return nil
+		}
+		if err := openOrPrint(out, cmd.ErrOrStderr(), target); err != nil {
+			return err
+		}
+		if projectPaperID != "" {
+			loadSeenTracker(tokens, cmd.ErrOrStderr()).mark("", projectPaperID)
+		}
+		return nil
+	},
+}
+
+// resolveOpenTarget looks ref up with lookupPaperRef and picks the URL for
+// kind ("" for the paper's own page).
+func resolveOpenTarget(ref, kind string, fetchProjectPaper func(string) (api.ProjectPaper, error)) (string, error) {
+	paper, webURL, err := lookupPaperRef(ref, fetchProjectPaper)
+	if err != nil {
+		return "", err
+	}
+
+	var target, label string
+	switch kind {
+	case "pdf":
+		target, label = paper.PdfURL, "PDF URL"
+	case "doi":
+		target, label = doiURL(paper.DOI), "DOI"
+	case "web":
+		target, label = webURL, "web page"
+	default:
+		target, label = paper.URL, "URL"
+		if strings.TrimSpace(target) == "" {
+			target = webURL
+		}
+	}
+	if strings.TrimSpace(target) == "" {
+		return "", fmt.Errorf("paper %s has no %s", terminalSafeInline(ref), label)
+	}
+	return target, nil
+}
+
+// lookupPaperRef resolves ref as a canonical paper first and as a
+// recommendation when that 404s. It also returns the Paperzilla web URL for
+// whichever one was found.
+func lookupPaperRef(ref string, fetchProjectPaper func(string) (api.ProjectPaper, error)) (api.Paper, string, error) {
+	paper, err := fetchPublicPaperFunc(ref)
+	if err == nil {
+		return paper, paperWebURL(paper), nil
+	}
+
+	var apiErr *api.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 404 {
+		return api.Paper{}, "", fmt.Errorf("failed to fetch paper: %w", err)
+	}
+	projectPaper, err := fetchProjectPaper(ref)
+	if err != nil {
+		if errors.As(err, &apiErr) && apiErr.StatusCode == 404 {
+			return api.Paper{}, "", fmt.Errorf("no paper or recommendation found for %s", terminalSafeInline(ref))
+		}
+		return api.Paper{}, "", fmt.Errorf("failed to fetch recommendation: %w", err)
+	}
+	paper = projectPaper.Paper
+	if strings.TrimSpace(paper.Title) == "" {
+		paper.Title = projectPaper.PaperTitle
+	}
+	return paper, recommendationWebURL(projectPaper.ID), nil
+}
+
+// projectPaperLookup fetches recommendations for lookupPaperRef. Public
+// papers need no account, so the login is only required once a ref turns
+// out to be a recommendation.
+func projectPaperLookup(tokens *config.Tokens, hasAuth *bool) func(string) (api.ProjectPaper, error) {
+	return func(ref string) (api.ProjectPaper, error) {
+		if !*hasAuth {
+			loaded, err := loadRequiredAuth()
+			if err != nil {
+				return api.ProjectPaper{}, err
+			}
+			*tokens, *hasAuth = loaded, true
+		}
+		return withAuth(tokens, func(at string) (api.ProjectPaper, error) {
+			return api.FetchProjectPaper(at, ref)
+		})
+	}
+}
+
+// openOrPrint opens target in the browser, or prints it to out when no
+// opener is available so it can still be clicked or copied.
+func openOrPrint(out, errOut io.Writer, target string) error {
+	err := openURLFunc(target)
+	switch {
+	case err == nil:
+		fmt.Fprintf(errOut, "Opened %s\n", terminalSafeInline(target))
+		return nil
+	case errors.Is(err, errNoURLOpener):
+		fmt.Fprintln(out, terminalSafeInline(target))
+		return nil
+	default:
+		return fmt.Errorf("failed to open %s: %w", terminalSafeInline(target), err)
+	}
+}
+
+// openURL hands target to the configured opener. Only http(s) URLs are
+// opened, and they are passed as a single argument, never through a shell,
+// so server-supplied links cannot inject options or commands.
+func openURL(target string) error {
+	safe, ok := safeLinkTarget(target)
+	if !ok {
+		return fmt.Errorf("refusing to open non-http URL")
+	}
+
+	argv, err := openerCommand(safe)
+	if err != nil {
+		return err
+	}
+	return runOpenerFunc(argv)
+}
+
+// openerCommand builds the command line for $BROWSER, the "browser" config
+// setting or the platform default. A "%s" in the configured command marks
+// where the URL goes; otherwise it is appended. Like xdg-utils, $BROWSER may
+// list several commands separated by ":"; the first one found is used.
+func openerCommand(target string) ([]string, error) {
+	candidates := strings.Split(os.Getenv("BROWSER"), string(os.PathListSeparator))
+	if settings, err := loadSettingsFunc(); err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	} else if settings.Browser != "" {
+		candidates = append(candidates, settings.Browser)
+	}
+	candidates = append(candidates, defaultOpener())
+
+	for _, candidate := range candidates {
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 {
+			continue
+		}
+		if _, err := lookPathFunc(fields[0]); err != nil {
+			continue
+		}
+
+		placed := false
+		for i, field := range fields[1:] {
+			if strings.Contains(field, "%s") {
+				fields[i+1] = strings.ReplaceAll(field, "%s", target)
+				placed = true
+			}
+		}
+		if !placed {
+			fields = append(fields, target)
+		}
+		return fields, nil
+	}
+	return nil, errNoURLOpener
+}
+
+func defaultOpener() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "open"
+	case "windows":
+		return "rundll32 url.dll,FileProtocolHandler"
+	default:
+		return "xdg-open"
+	}
+}
+
+func runOpener(argv []string) error {
+	cmd := exec.Command(argv[0], argv[1:]...)
+	// Terminal browsers such as lynx need the terminal; GUI openers return
+	// immediately.
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
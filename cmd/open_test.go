@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func stubOpener(t *testing.T, available map[string]bool, settings config.Settings) *[][]string {
+	t.Helper()
+	var runs [][]string
+
+	originalLookPath := lookPathFunc
+	originalRun := runOpenerFunc
+	originalLoad := loadSettingsFunc
+	lookPathFunc = func(name string) (string, error) {
+		if available[name] {
+			return "/usr/bin/" + name, nil
+		}
+		return "", errors.New("not found")
+	}
+	runOpenerFunc = func(argv []string) error {
+		runs = append(runs, argv)
+		return nil
+	}
+	loadSettingsFunc = func() (config.Settings, error) { return settings, nil }
+	t.Cleanup(func() {
+		lookPathFunc = originalLookPath
+		runOpenerFunc = originalRun
+		loadSettingsFunc = originalLoad
+	})
+	return &runs
+}
+
+func TestOpenerCommandPrecedence(t *testing.T) {
+	tests := []struct {
+		name      string
+		browser   string
+		settings  config.Settings
+		available map[string]bool
+		want      string
+	}{
+		{name: "platform default", available: map[string]bool{"xdg-open": true, "open": true, "rundll32": true}, want: defaultOpener() + " URL"},
+		{name: "BROWSER list uses first found", browser: "missing:firefox --new-tab", available: map[string]bool{"firefox": true}, want: "firefox --new-tab URL"},
+		{name: "BROWSER placeholder", browser: "lynx -accept_all_cookies %s", available: map[string]bool{"lynx": true}, want: "lynx -accept_all_cookies URL"},
+		{name: "config when BROWSER unset", settings: config.Settings{Browser: "chromium --incognito"}, available: map[string]bool{"chromium": true, "xdg-open": true}, want: "chromium --incognito URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BROWSER", tt.browser)
+			stubOpener(t, tt.available, tt.settings)
+
+			argv, err := openerCommand("URL")
+			if err != nil {
+				t.Fatalf("openerCommand: %v", err)
+			}
+			if got := strings.Join(argv, " "); got != strings.Replace(tt.want, "rundll32", "rundll32 url.dll,FileProtocolHandler", 1) {
+				t.Fatalf("argv = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenURLPassesTargetAsSingleArgument(t *testing.T) {
+	t.Setenv("BROWSER", "firefox")
+	runs := stubOpener(t, map[string]bool{"firefox": true}, config.Settings{})
+
+	if err := openURL("https://example.com/a;rm -rf ~"); err != nil {
+		t.Fatalf("openURL: %v", err)
+	}
+	if len(*runs) != 1 || len((*runs)[0]) != 2 || (*runs)[0][1] != "https://example.com/a;rm%20-rf%20~" {
+		t.Fatalf("runs = %q", *runs)
+	}
+}
+
+func TestOpenURLRejectsNonHTTPTargets(t *testing.T) {
+	t.Setenv("BROWSER", "firefox")
+	runs := stubOpener(t, map[string]bool{"firefox": true}, config.Settings{})
+
+	for _, target := range []string{"file:///etc/passwd", "--help", "javascript:alert(1)"} {
+		if err := openURL(target); err == nil {
+			t.Errorf("openURL(%q) succeeded", target)
+		}
+	}
+	if len(*runs) != 0 {
+		t.Fatalf("runs = %q", *runs)
+	}
+}
+
+func TestOpenOrPrintFallsBackToPrinting(t *testing.T) {
+	t.Setenv("BROWSER", "")
+	stubOpener(t, map[string]bool{}, config.Settings{})
+
+	var stdout, stderr bytes.Buffer
+	if err := openOrPrint(&stdout, &stderr, "https://arxiv.org/abs/1"); err != nil {
+		t.Fatalf("openOrPrint: %v", err)
+	}
+	if stdout.String() != "https://arxiv.org/abs/1\n" || stderr.Len() != 0 {
+		t.Fatalf("stdout = %q, stderr = %q", stdout.String(), stderr.String())
+	}
+}
+
+func TestResolveOpenTargetForCanonicalPaper(t *testing.T) {
+	t.Setenv("PZ_API_URL", "https://paperzilla.example")
+	stubPublicPaper(t, api.Paper{ID: "paper-1", ShortID: "abcd1234", URL: "https://arxiv.org/abs/1", PdfURL: "https://arxiv.org/pdf/1", DOI: "doi:10.1/x"}, nil)
+
+	want := map[string]string{
+		"":    "https://arxiv.org/abs/1",
+		"pdf": "https://arxiv.org/pdf/1",
+		"doi": "https://doi.org/10.1/x",
+		"web": "https://paperzilla.example/papers/abcd1234",
+	}
+	for kind, url := range want {
+		got, err := resolveOpenTarget("abcd1234", kind, func(string) (api.ProjectPaper, error) {
+			t.Fatal("project paper should not be fetched")
+			return api.ProjectPaper{}, nil
+		})
+		if err != nil || got != url {
+			t.Errorf("resolveOpenTarget(%q) = %q, %v; want %q", kind, got, err, url)
+		}
+	}
+}
+
+func TestResolveOpenTargetFallsBackToRecommendation(t *testing.T) {
+	t.Setenv("PZ_API_URL", "https://paperzilla.example")
+	stubPublicPaper(t, api.Paper{}, &api.APIError{StatusCode: 404})
+
+	fetch := func(ref string) (api.ProjectPaper, error) {
+		return api.ProjectPaper{ID: "pp-1", Paper: api.Paper{URL: "https://arxiv.org/abs/2"}}, nil
+	}
+
+	if got, err := resolveOpenTarget("feedbeef", "web", fetch); err != nil || got != "https://paperzilla.example/recommendations/pp-1" {
+		t.Fatalf("web = %q, %v", got, err)
+	}
+	if _, err := resolveOpenTarget("feedbeef", "pdf", fetch); err == nil || !strings.Contains(err.Error(), "has no PDF URL") {
+		t.Fatalf("pdf err = %v", err)
+	}
+}
+
+func TestResolveOpenTargetReportsUnknownRef(t *testing.T) {
+	stubPublicPaper(t, api.Paper{}, &api.APIError{StatusCode: 404})
+
+	_, err := resolveOpenTarget("nope", "", func(string) (api.ProjectPaper, error) {
+		return api.ProjectPaper{}, &api.APIError{StatusCode: 404}
+	})
+	if err == nil || err.Error() != "no paper or recommendation found for nope" {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func stubPublicPaper(t *testing.T, paper api.Paper, err error) {
+	t.Helper()
+	original := fetchPublicPaperFunc
+	fetchPublicPaperFunc = func(string) (api.Paper, error) { return paper, err }
+	t.Cleanup(func() { fetchPublicPaperFunc = original })
+}
+
+func TestOpenCanonicalPaperWorksWhenLoggedOut(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PZ_TOKENS_PATH", filepath.Join(dir, "tokens.json"))
+	t.Setenv("PZ_SEEN_STATE_PATH", filepath.Join(dir, "seen.json"))
+	originalLogin := loginFunc
+	loginFunc = func() (config.Tokens, error) {
+		t.Fatal("a public paper should not ask for a login")
+		return config.Tokens{}, nil
+	}
+	t.Cleanup(func() { loginFunc = originalLogin })
+	stubPublicPaper(t, api.Paper{ID: "paper-1", URL: "https://example.org/paper-1"}, nil)
+
+	cmd := &cobra.Command{}
+	for _, name := range append(openTargetKinds, "print") {
+		cmd.Flags().Bool(name, false, "")
+	}
+	_ = cmd.Flags().Set("print", "true")
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	if err := openCmd.RunE(cmd, []string{"paper-1"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	if stdout.String() != "https://example.org/paper-1\n" {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/paperzilla/pz/internal/layout"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const defaultPager = "less -FRX"
+
+var (
+	runExternalPagerFunc = runExternalPager
+	runBuiltinPagerFunc  = runBuiltinPager
+	terminalSizeFunc     = func(file *os.File) (int, int, error) { return term.GetSize(int(file.Fd())) }
+	loadSettingsFunc     = config.LoadSettings
+)
+
+// pageBuffer collects output destined for a terminal so it can be measured
+// before paging. terminalFile sees through it, so width and color detection
+// behave as if the writes went straight to the terminal.
+type pageBuffer struct {
+	bytes.Buffer
+	terminal *os.File
+}
+
+// writePaged runs write and, git-style, sends the result through a pager
+// when stdout is an interactive terminal and the output is taller than the
+// screen. Everything write produces must already be terminal-safe (escaped
+// with terminalSafeInline/terminalSafeBlock): the pager receives the bytes
+// unchanged and only ever adds its own SGR passthrough.
+func writePaged(cmd *cobra.Command, write func(io.Writer) error) error {
+	out := cmd.OutOrStdout()
+	file, ok := terminalFile(out)
+	if !ok {
+		return write(out)
+	}
+	pager, err := pagerCommand(cmd)
+	if err != nil {
+		return err
+	}
+	if pager == "" {
+		return write(out)
+	}
+
+	buf := &pageBuffer{terminal: file}
+	if err := write(buf); err != nil {
+		_, _ = out.Write(buf.Bytes())
+		return err
+	}
+	return pageOutput(file, pager, buf.String())
+}
+
+// pagerCommand resolves the pager like git does: --no-pager, then
+// $PZ_PAGER, the "pager" config setting, $PAGER and finally less -FRX.
+// An empty result, or "cat", disables paging.
+func pagerCommand(cmd *cobra.Command) (string, error) {
+	if noPager, _ := cmd.Flags().GetBool("no-pager"); noPager {
+		return "", nil
+	}
+
+	pager, ok := os.LookupEnv("PZ_PAGER")
+	if !ok {
+		settings, err := loadSettingsFunc()
+		if err != nil {
+			return "", fmt.Errorf("failed to load config: %w", err)
+		}
+		pager = settings.Pager
+		if pager == "" {
+			pager = os.Getenv("PAGER")
+		}
+		if strings.TrimSpace(pager) == "" {
+			pager = defaultPager
+		}
+	}
+
+	pager = strings.TrimSpace(pager)
+	if pager == "cat" {
+		return "", nil
+	}
+	return pager, nil
+}
+
+// pageOutput writes content to the terminal, through pager when it does not
+// fit on one screen. If the pager cannot be started, a small built-in pager
+// takes over.
+func pageOutput(file *os.File, pager, content string) error {
+	width, height, err := terminalSizeFunc(file)
+	if err != nil || !exceedsScreen(content, width, height) {
+		_, err := io.WriteString(file, content)
+		return err
+	}
+
+	err = runExternalPagerFunc(pager, file, content)
+	if err == nil {
+		return nil
+	}
+	if !pagerNotFound(err) {
+		// The pager ran; a non-zero exit (e.g. interrupted) is not our error.
+		return nil
+	}
+	return runBuiltinPagerFunc(os.Stdin, file, content)
+}
+
+// escapePattern matches the escapes pz writes itself: SGR colors and OSC 8
+// hyperlinks. Neither takes up a cell.
+var escapePattern = regexp.MustCompile("\x1b\\[[0-9;]*m|\x1b\\]8;[^\x1b\a]*(?:\x1b\\\\|\a)")
+
+// exceedsScreen reports whether content, once wrapped at width, needs more
+// rows than the terminal has. One row is kept free for the shell prompt.
+func exceedsScreen(content string, width, height int) bool {
+	if height <= 0 {
+		return false
+	}
+	rows := 0
+	for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+		lineWidth := layout.Width(escapePattern.ReplaceAllString(line, ""))
+		if width > 0 && lineWidth > width {
+			rows += (lineWidth + width - 1) / width
+		} else {
+			rows++
+		}
+		if rows >= height {
+			return true
+		}
+	}
+	return false
+}
+
+func pagerNotFound(err error) bool {
+	var execErr *exec.Error
+	if errors.As(err, &execErr) {
+		return true
+	}
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == 127
+}
+
+func runExternalPager(pager string, out *os.File, content string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		fields := strings.Fields(pager)
+		cmd = exec.Command(fields[0], fields[1:]...)
+	} else {
+		cmd = exec.Command("sh", "-c", pager)
+	}
+	cmd.Stdin = strings.NewReader(content)
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	if os.Getenv("LESS") == "" {
+		// Like git: quit on one screen, pass colors through, keep the screen.
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+	return cmd.Run()
+}
+
+// runBuiltinPager shows content one screen at a time. Space or f advances a
+// page, Enter or j a line, b goes back a page and q quits. Without a
+// terminal on in, or when the content fits, it is written straight through.
+func runBuiltinPager(in *os.File, out *os.File, content string) error {
+	_, height, err := term.GetSize(int(out.Fd()))
+	if err != nil || height < 3 || !isInteractiveTerminal(in) {
+		_, err := io.WriteString(out, content)
+		return err
+	}
+
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	pageSize := height - 1
+	if len(lines) <= pageSize {
+		_, err := io.WriteString(out, content)
+		return err
+	}
+
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		_, err := io.WriteString(out, content)
+		return err
+	}
+	defer term.Restore(int(in.Fd()), state)
+
+	show := func(from, to int) {
+		for _, line := range lines[from:to] {
+			fmt.Fprint(out, line, "\r\n")
+		}
+	}
+
+	top, shown := 0, pageSize
+	show(top, shown)
+	key := make([]byte, 3)
+	for shown < len(lines) {
+		percent := shown * 100 / len(lines)
+		fmt.Fprintf(out, "\x1b[7m-- More (%d%%) -- space: page  enter: line  b: back  q: quit\x1b[0m", percent)
+		n, err := in.Read(key)
+		fmt.Fprint(out, "\r\x1b[K")
+		if err != nil || n == 0 {
+			return nil
+		}
+
+		switch key[0] {
+		case 'q', 'Q', 3:
+			return nil
+		case '\r', '\n', 'j':
+			show(shown, shown+1)
+			shown++
+			top++
+		case 'b':
+			top = max(top-pageSize, 0)
+			fmt.Fprint(out, "\x1b[2J\x1b[H")
+			shown = min(top+pageSize, len(lines))
+			show(top, shown)
+		default:
+			next := min(shown+pageSize, len(lines))
+			show(shown, next)
+			top += next - shown
+			shown = next
+		}
+	}
+	return nil
+}
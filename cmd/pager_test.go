@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func TestPagerCommandPrecedence(t *testing.T) {
+	tests := []struct {
+		name     string
+		pzPager  *string
+		settings config.Settings
+		pager    string
+		noPager  bool
+		want     string
+	}{
+		{name: "default", want: "less -FRX"},
+		{name: "PAGER", pager: "more", want: "more"},
+		{name: "config beats PAGER", settings: config.Settings{Pager: "most"}, pager: "more", want: "most"},
+		{name: "PZ_PAGER beats config", pzPager: stringPtr("bat --plain"), settings: config.Settings{Pager: "most"}, want: "bat --plain"},
+		{name: "empty PZ_PAGER disables", pzPager: stringPtr(""), pager: "more", want: ""},
+		{name: "cat disables", settings: config.Settings{Pager: "cat"}, want: ""},
+		{name: "flag disables", pzPager: stringPtr("less"), noPager: true, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PAGER", tt.pager)
+			if tt.pzPager != nil {
+				t.Setenv("PZ_PAGER", *tt.pzPager)
+			} else {
+				t.Setenv("PZ_PAGER", "")
+				os.Unsetenv("PZ_PAGER")
+			}
+			originalLoad := loadSettingsFunc
+			loadSettingsFunc = func() (config.Settings, error) { return tt.settings, nil }
+			t.Cleanup(func() { loadSettingsFunc = originalLoad })
+
+			cmd := &cobra.Command{}
+			cmd.Flags().Bool("no-pager", tt.noPager, "")
+
+			got, err := pagerCommand(cmd)
+			if err != nil {
+				t.Fatalf("pagerCommand: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("pagerCommand = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExceedsScreenCountsWrappedRows(t *testing.T) {
+	if exceedsScreen("a\nb\n", 80, 3) {
+		t.Fatal("two lines should fit in three rows")
+	}
+	if !exceedsScreen("a\nb\nc\n", 80, 3) {
+		t.Fatal("three lines should not fit above the prompt row")
+	}
+	if !exceedsScreen(strings.Repeat("x", 25)+"\n", 10, 3) {
+		t.Fatal("a line wrapping to three rows should not fit")
+	}
+	if exceedsScreen("\x1b[1m"+strings.Repeat("x", 10)+"\x1b[0m\n", 10, 2) {
+		t.Fatal("color codes should not count towards the width")
+	}
+	if exceedsScreen(hyperlink(strings.Repeat("x", 10), "https://paperzilla.ai/recommendations/pp-1")+"\n", 10, 2) {
+		t.Fatal("hyperlinks should not count towards the width")
+	}
+}
+
+func TestPageOutputSendsLongOutputToPagerUnchanged(t *testing.T) {
+	file := tempOutputFile(t)
+	stubTerminalSize(t, 80, 3)
+
+	var gotPager, gotContent string
+	originalExternal := runExternalPagerFunc
+	runExternalPagerFunc = func(pager string, out *os.File, content string) error {
+		gotPager, gotContent = pager, content
+		return nil
+	}
+	t.Cleanup(func() { runExternalPagerFunc = originalExternal })
+
+	content := "Title: " + terminalSafeInline("evil\x1b]52;c;payload\a") + "\nline 2\nline 3\n"
+	if err := pageOutput(file, "less -FRX", content); err != nil {
+		t.Fatalf("pageOutput: %v", err)
+	}
+
+	if gotPager != "less -FRX" {
+		t.Fatalf("pager = %q", gotPager)
+	}
+	if gotContent != content || strings.ContainsRune(gotContent, '\x1b') {
+		t.Fatalf("content = %q", gotContent)
+	}
+	if written := readOutputFile(t, file); written != "" {
+		t.Fatalf("file = %q, want empty", written)
+	}
+}
+
+func TestPageOutputWritesShortOutputDirectly(t *testing.T) {
+	file := tempOutputFile(t)
+	stubTerminalSize(t, 80, 24)
+
+	originalExternal := runExternalPagerFunc
+	runExternalPagerFunc = func(string, *os.File, string) error {
+		t.Fatal("pager should not run for short output")
+		return nil
+	}
+	t.Cleanup(func() { runExternalPagerFunc = originalExternal })
+
+	if err := pageOutput(file, "less -FRX", "short\n"); err != nil {
+		t.Fatalf("pageOutput: %v", err)
+	}
+	if written := readOutputFile(t, file); written != "short\n" {
+		t.Fatalf("file = %q", written)
+	}
+}
+
+func TestPageOutputFallsBackWhenPagerIsMissing(t *testing.T) {
+	file := tempOutputFile(t)
+	stubTerminalSize(t, 80, 2)
+
+	originalExternal := runExternalPagerFunc
+	originalBuiltin := runBuiltinPagerFunc
+	runExternalPagerFunc = func(string, *os.File, string) error {
+		return &exec.Error{Name: "less", Err: exec.ErrNotFound}
+	}
+	var builtinContent string
+	runBuiltinPagerFunc = func(in *os.File, out *os.File, content string) error {
+		builtinContent = content
+		return nil
+	}
+	t.Cleanup(func() {
+		runExternalPagerFunc = originalExternal
+		runBuiltinPagerFunc = originalBuiltin
+	})
+
+	if err := pageOutput(file, "less -FRX", "1\n2\n3\n"); err != nil {
+		t.Fatalf("pageOutput: %v", err)
+	}
+	if builtinContent != "1\n2\n3\n" {
+		t.Fatalf("builtin content = %q", builtinContent)
+	}
+}
+
+func TestWritePagedWritesDirectlyWhenNotATerminal(t *testing.T) {
+	originalLoad := loadSettingsFunc
+	loadSettingsFunc = func() (config.Settings, error) {
+		t.Fatal("settings should not be read for non-terminal output")
+		return config.Settings{}, nil
+	}
+	t.Cleanup(func() { loadSettingsFunc = originalLoad })
+
+	cmd := &cobra.Command{}
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+
+	err := writePaged(cmd, func(out io.Writer) error {
+		_, err := io.WriteString(out, "hello\n")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("writePaged: %v", err)
+	}
+	if stdout.String() != "hello\n" {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+}
+
+func stubTerminalSize(t *testing.T, width, height int) {
+	t.Helper()
+	original := terminalSizeFunc
+	terminalSizeFunc = func(*os.File) (int, int, error) { return width, height, nil }
+	t.Cleanup(func() { terminalSizeFunc = original })
+}
+
+func tempOutputFile(t *testing.T) *os.File {
+	t.Helper()
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	t.Cleanup(func() { _ = file.Close() })
+	return file
+}
+
+func readOutputFile(t *testing.T, file *os.File) string {
+	t.Helper()
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	return string(data)
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
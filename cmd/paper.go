@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/paperzilla/pz/internal/api"
 	"github.com/spf13/cobra"
@@ -17,6 +18,7 @@ var (
 func init() {
 	paperCmd.Flags().BoolP("json", "j", false, "Output as JSON")
 	paperCmd.Flags().Bool("markdown", false, "Print raw markdown")
+	paperCmd.Flags().Bool("render", false, "Render markdown for the terminal (default for --markdown when stdout is a terminal)")
 	paperCmd.Flags().String("project", "", "Resolve this paper inside one of your projects")
 }
 
@@ -26,7 +28,7 @@ var paperCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		jsonOut, _ := cmd.Flags().GetBool("json")
-		markdownOut, _ := cmd.Flags().GetBool("markdown")
+		markdownOut := markdownRequested(cmd)
 		projectID, _ := cmd.Flags().GetString("project")
 		if jsonOut && markdownOut {
 			return fmt.Errorf("--json and --markdown cannot be used together")
@@ -48,7 +50,6 @@ func runCanonicalPaper(cmd *cobra.Command, paperRef string, jsonOut bool) error
 		return err
 	}
 
-	out := cmd.OutOrStdout()
 	errOut := cmd.ErrOrStderr()
 
 	paper, err := fetchPublicPaperFunc(paperRef)
@@ -59,7 +60,7 @@ func runCanonicalPaper(cmd *cobra.Command, paperRef string, jsonOut bool) error
 			switch {
 			case legacyErr == nil && usedLegacy:
 				printLegacyPaperWarning(errOut, paperRef)
-				return printPaper(out, legacyPaper, jsonOut)
+				return printPaper(cmd, legacyPaper, jsonOut)
 			case legacyErr != nil:
 				var legacyAPIError *api.APIError
 				if errors.As(legacyErr, &legacyAPIError) && legacyAPIError.StatusCode != 404 {
@@ -71,7 +72,7 @@ func runCanonicalPaper(cmd *cobra.Command, paperRef string, jsonOut bool) error
 		return fmt.Errorf("failed to fetch paper: %w", err)
 	}
 
-	return printPaper(out, paper, jsonOut)
+	return printPaper(cmd, paper, jsonOut)
 }
 
 func runCanonicalPaperMarkdown(cmd *cobra.Command, paperRef string) error {
@@ -101,8 +102,7 @@ func runCanonicalPaperMarkdown(cmd *cobra.Command, paperRef string) error {
 				switch {
 				case legacyErr == nil && usedLegacy:
 					printLegacyPaperWarning(errOut, paperRef)
-					fmt.Fprint(out, markdown)
-					return nil
+					return writePaperMarkdown(cmd, markdown)
 				case legacyErr != nil:
 					var legacyPending *api.PaperMarkdownPendingError
 					if errors.As(legacyErr, &legacyPending) {
@@ -122,8 +122,7 @@ func runCanonicalPaperMarkdown(cmd *cobra.Command, paperRef string) error {
 		return fmt.Errorf("failed to fetch paper markdown: %w", err)
 	}
 
-	fmt.Fprint(out, markdown)
-	return nil
+	return writePaperMarkdown(cmd, markdown)
 }
 
 func runProjectScopedPaper(cmd *cobra.Command, paperRef, projectID string, jsonOut, markdownOut bool) error {
@@ -151,16 +150,17 @@ func runProjectScopedPaper(cmd *cobra.Command, paperRef, projectID string, jsonO
 			}
 			return fmt.Errorf("failed to fetch project paper markdown: %w", err)
 		}
-		fmt.Fprint(cmd.OutOrStdout(), markdown)
-		return nil
+		return writePaperMarkdown(cmd, markdown)
 	}
 
 	if jsonOut {
 		return printProjectPaperJSON(cmd.OutOrStdout(), projectPaper)
 	}
 
-	writeProjectPaper(cmd.OutOrStdout(), projectPaper)
-	return nil
+	return writePaged(cmd, func(out io.Writer) error {
+		writeProjectPaper(out, projectPaper)
+		return nil
+	})
 }
 
 func fetchLegacyPaperFallback(paperRef string) (api.Paper, bool, error) {
@@ -203,18 +203,20 @@ func recommendationHintError(ref, suffix string) error {
 	return fmt.Errorf("paper not found. If this is a recommendation ID, use `pz rec %s`", ref)
 }
 
-func printPaper(out interface{ Write([]byte) (int, error) }, paper api.Paper, jsonOut bool) error {
+func printPaper(cmd *cobra.Command, paper api.Paper, jsonOut bool) error {
 	if jsonOut {
 		data, err := json.MarshalIndent(paper, "", "  ")
 		if err != nil {
 			return fmt.Errorf("failed to marshal JSON: %w", err)
 		}
-		fmt.Fprintln(out, string(data))
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
 		return nil
 	}
 
-	writeCanonicalPaper(out, paper)
-	return nil
+	return writePaged(cmd, func(out io.Writer) error {
+		writeCanonicalPaper(out, paper)
+		return nil
+	})
 }
 
 func printProjectPaperJSON(out interface{ Write([]byte) (int, error) }, projectPaper api.ProjectPaper) error {
@@ -347,7 +347,10 @@ func writeTestTokens(t *testing.T) {
 
 func savePaperTestTokens(t *testing.T) {
 	t.Helper()
-	t.Setenv("PZ_TOKENS_PATH", filepath.Join(t.TempDir(), "tokens.json"))
+	dir := t.TempDir()
+	t.Setenv("PZ_TOKENS_PATH", filepath.Join(dir, "tokens.json"))
+	t.Setenv("PZ_SEEN_STATE_PATH", filepath.Join(dir, "seen.json"))
+	t.Setenv("PZ_FEEDBACK_LOG_PATH", filepath.Join(dir, "feedback-log.jsonl"))
 	if err := config.SaveTokens(config.Tokens{
 		AccessToken:  "access-1",
 		RefreshToken: "refresh-1",
@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/layout"
 	"github.com/spf13/cobra"
 )
 
@@ -42,29 +44,29 @@ var projectCmd = &cobra.Command{
 			return fmt.Errorf("failed to fetch project: %w", err)
 		}
 
-		out := cmd.OutOrStdout()
 		if jsonOut {
-			return writeJSON(out, p)
-		}
-
-		fmt.Fprintf(out, "Name:             %s\n", terminalSafeInline(p.Name))
-		fmt.Fprintf(out, "ID:               %s\n", terminalSafeInline(p.ID))
-		fmt.Fprintf(out, "Mode:             %s\n", terminalSafeInline(p.Mode))
-		fmt.Fprintf(out, "Visibility:       %s\n", terminalSafeInline(p.Visibility))
-		fmt.Fprintf(out, "Matching State:   %s\n", terminalSafeInline(p.MatchingState))
-		fmt.Fprintf(out, "Email Frequency:  %s\n", terminalSafeInline(p.EmailFrequency))
-		fmt.Fprintf(out, "Email Time:       %s\n", terminalSafeInline(p.EmailTime))
-		fmt.Fprintf(out, "Max Candidates:   %d\n", p.MaxCandidates)
-		fmt.Fprintf(out, "Max Papers/Digest:%d\n", p.MaxPapersPerDigests)
-		fmt.Fprintf(out, "Created:          %s\n", formatTime(p.CreatedAt))
-		fmt.Fprintf(out, "Activated:        %s\n", formatTime(p.ActivatedAt))
-		fmt.Fprintf(out, "Last Digest:      %s\n", formatTime(p.LastDigestSentAt))
-
-		if p.InterestDescription != "" {
-			fmt.Fprintf(out, "\nInterest:\n  %s\n", terminalSafeBlock(p.InterestDescription, "  "))
+			return writeJSON(cmd.OutOrStdout(), p)
 		}
 
-		return nil
+		return writePaged(cmd, func(out io.Writer) error {
+			fmt.Fprintf(out, "Name:             %s\n", terminalSafeInline(p.Name))
+			fmt.Fprintf(out, "ID:               %s\n", terminalSafeInline(p.ID))
+			fmt.Fprintf(out, "Mode:             %s\n", terminalSafeInline(p.Mode))
+			fmt.Fprintf(out, "Visibility:       %s\n", terminalSafeInline(p.Visibility))
+			fmt.Fprintf(out, "Matching State:   %s\n", terminalSafeInline(p.MatchingState))
+			fmt.Fprintf(out, "Email Frequency:  %s\n", terminalSafeInline(p.EmailFrequency))
+			fmt.Fprintf(out, "Email Time:       %s\n", terminalSafeInline(p.EmailTime))
+			fmt.Fprintf(out, "Max Candidates:   %d\n", p.MaxCandidates)
+			fmt.Fprintf(out, "Max Papers/Digest:%d\n", p.MaxPapersPerDigests)
+			fmt.Fprintf(out, "Created:          %s\n", formatTime(p.CreatedAt))
+			fmt.Fprintf(out, "Activated:        %s\n", formatTime(p.ActivatedAt))
+			fmt.Fprintf(out, "Last Digest:      %s\n", formatTime(p.LastDigestSentAt))
+
+			if p.InterestDescription != "" {
+				fmt.Fprintf(out, "\nInterest:\n  %s\n", terminalSafeBlock(p.InterestDescription, "  "))
+			}
+			return nil
+		})
 	},
 }
 
@@ -95,19 +97,36 @@ var projectListCmd = &cobra.Command{
 			return nil
 		}
 
-		fmt.Fprintf(out, "%-36s  %-25s  %-10s  %-10s  %s\n", "ID", "NAME", "MODE", "VISIBILITY", "CREATED")
-		for _, p := range projects {
-			name := terminalSafeInline(p.Name)
-			if len(name) > 25 {
-				name = name[:22] + "..."
-			}
-			fmt.Fprintf(out, "%-36s  %-25s  %-10s  %-10s  %s\n", terminalSafeInline(p.ID), name, terminalSafeInline(p.Mode), terminalSafeInline(p.Visibility), formatTime(p.CreatedAt))
-		}
-
-		return nil
+		return writePaged(cmd, func(out io.Writer) error {
+			return projectListTable(projects).Render(out, outputWidth(out))
+		})
 	},
 }
 
+// projectListTable keeps ID and NAME visible longest; on narrow terminals
+// NAME shrinks first, then VISIBILITY, MODE and CREATED are dropped.
+func projectListTable(projects []api.Project) layout.Table {
+	table := layout.Table{
+		Columns: []layout.Column{
+			{Header: "ID", Priority: 4},
+			{Header: "NAME", Priority: 5, Flexible: true, MinWidth: 12},
+			{Header: "MODE", Priority: 2},
+			{Header: "VISIBILITY", Priority: 1},
+			{Header: "CREATED", Priority: 3},
+		},
+	}
+	for _, p := range projects {
+		table.Rows = append(table.Rows, []string{
+			terminalSafeInline(p.ID),
+			terminalSafeInline(p.Name),
+			terminalSafeInline(p.Mode),
+			terminalSafeInline(p.Visibility),
+			formatTime(p.CreatedAt),
+		})
+	}
+	return table
+}
+
 func summarizeProjects(projects []api.Project) []projectListItem {
 	items := make([]projectListItem, 0, len(projects))
 	for _, project := range projects {
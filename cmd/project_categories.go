@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/paperzilla/pz/internal/layout"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	projectCategoriesCmd.Flags().Float64("weight", 1, "Weight for added categories, or the new weight for set-weight")
+	projectCmd.AddCommand(projectCategoriesCmd)
+}
+
+var categoryActions = []string{"list", "add", "remove", "set-weight"}
+
+// categoryRef is a category given on the command line by ID or code, with
+// an optional weight.
+type categoryRef struct {
+	Text   string
+	ID     int
+	Code   string
+	Weight *float64
+}
+
+var projectCategoriesCmd = &cobra.Command{
+	Use:   "categories <project-id> [list|add|remove|set-weight] [category[:weight]...]",
+	Short: "List or change the categories a project watches",
+	Long: "List or change the categories a project watches. Categories are given by ID\n" +
+		"or code (see pz categories list), optionally followed by :WEIGHT. Without a\n" +
+		"weight, add and set-weight use --weight; set-weight needs one of the two.",
+	Example: `  pz project categories <project-id>
+  pz project categories <project-id> add cs.LG q-bio.BM:0.5
+  pz project categories <project-id> set-weight cs.LG --weight 0.8
+  pz project categories <project-id> remove 15`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonOut, _ := cmd.Flags().GetBool("json")
+		defaultWeight, _ := cmd.Flags().GetFloat64("weight")
+		if defaultWeight < 0 {
+			return fmt.Errorf("--weight must not be negative")
+		}
+
+		action := "list"
+		var values []string
+		if len(args) > 1 {
+			action, values = args[1], args[2:]
+			if !slices.Contains(categoryActions, action) {
+				return fmt.Errorf("unknown categories action %q (expected list, add, remove, or set-weight)", action)
+			}
+		}
+		if action == "list" && len(values) > 0 {
+			return fmt.Errorf("list takes no categories")
+		}
+		if action != "list" && len(values) == 0 {
+			return fmt.Errorf("no categories to %s; pass category IDs or codes", action)
+		}
+		refs := make([]categoryRef, 0, len(values))
+		for _, value := range values {
+			ref, err := parseCategoryRef(value)
+			if err != nil {
+				return err
+			}
+			if action == "remove" && ref.Weight != nil {
+				return fmt.Errorf("invalid category %q: remove takes no weight", value)
+			}
+			if action == "set-weight" && ref.Weight == nil && !cmd.Flags().Changed("weight") {
+				return fmt.Errorf("invalid category %q: set-weight needs a weight, as %s:WEIGHT or with --weight", value, value)
+			}
+			refs = append(refs, ref)
+		}
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+		project, err := withAuth(&tokens, func(at string) (api.Project, error) {
+			return api.FetchProject(at, args[0])
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch project: %w", err)
+		}
+
+		out := cmd.OutOrStdout()
+		if action == "list" {
+			if jsonOut {
+				return writeJSON(out, project.Categories)
+			}
+			if len(project.Categories) == 0 {
+				fmt.Fprintf(out, "Project %s (%s) watches no categories.\n", terminalSafeInline(project.Name), terminalSafeInline(project.ID))
+				return nil
+			}
+			return writePaged(cmd, func(out io.Writer) error {
+				return projectCategoryTable(project.Categories).Render(out, outputWidth(out))
+			})
+		}
+
+		categories := slices.Clone(project.Categories)
+		var changes []string
+		errOut := cmd.ErrOrStderr()
+		for _, ref := range refs {
+			index := slices.IndexFunc(categories, ref.matches)
+			weight := defaultWeight
+			if ref.Weight != nil {
+				weight = *ref.Weight
+			}
+			switch action {
+			case "add":
+				if index >= 0 {
+					fmt.Fprintf(errOut, "Already watched: %s (use set-weight to change its weight)\n", categoryLabel(categories[index]))
+					continue
+				}
+				category, err := resolveCategory(&tokens, ref)
+				if err != nil {
+					return err
+				}
+				if i := slices.IndexFunc(categories, func(c api.ProjectCategory) bool { return c.ID == category.ID }); i >= 0 {
+					fmt.Fprintf(errOut, "Already watched: %s (use set-weight to change its weight)\n", categoryLabel(categories[i]))
+					continue
+				}
+				added := api.ProjectCategory{ID: category.ID, Code: category.Code, Name: category.Name,
+					SourceID: category.SourceID, SourceName: category.SourceName, Weight: weight}
+				categories = append(categories, added)
+				changes = append(changes, fmt.Sprintf("Added    %s, weight %g", categoryLabel(added), weight))
+			case "remove":
+				if index < 0 {
+					fmt.Fprintf(errOut, "Not watched: %s\n", terminalSafeInline(ref.Text))
+					continue
+				}
+				changes = append(changes, "Removed  "+categoryLabel(categories[index]))
+				categories = slices.Delete(categories, index, index+1)
+			case "set-weight":
+				if index < 0 {
+					return fmt.Errorf("project does not watch category %s; add it first", terminalSafeInline(ref.Text))
+				}
+				if categories[index].Weight == weight {
+					continue
+				}
+				changes = append(changes, fmt.Sprintf("Weight   %s: %g → %g", categoryLabel(categories[index]), categories[index].Weight, weight))
+				categories[index].Weight = weight
+			}
+		}
+
+		if len(changes) > 0 {
+			inputs := make([]api.ProjectCategoryInput, len(categories))
+			for i, category := range categories {
+				inputs[i] = api.ProjectCategoryInput{ID: category.ID, Weight: category.Weight}
+			}
+			project, err = withAuth(&tokens, func(at string) (api.Project, error) {
+				return api.UpdateProject(at, project.ID, api.ProjectInput{Categories: &inputs})
+			})
+			if err != nil {
+				return projectWriteError("update", err)
+			}
+		}
+
+		if jsonOut {
+			return writeJSON(out, project.Categories)
+		}
+		if len(changes) == 0 {
+			fmt.Fprintf(out, "No changes to the categories of %s (%s)\n", terminalSafeInline(project.Name), terminalSafeInline(project.ID))
+			return nil
+		}
+		for _, change := range changes {
+			fmt.Fprintln(out, change)
+		}
+		fmt.Fprintf(out, "\nProject %s (%s) now watches %d %s\n", terminalSafeInline(project.Name), terminalSafeInline(project.ID),
+			len(project.Categories), plural(len(project.Categories), "category", "categories"))
+		return nil
+	},
+}
+
+// parseCategoryRef reads ID, CODE, ID:WEIGHT or CODE:WEIGHT.
+func parseCategoryRef(value string) (categoryRef, error) {
+	text, weightText, hasWeight := strings.Cut(strings.TrimSpace(value), ":")
+	ref := categoryRef{Text: strings.TrimSpace(text)}
+	if ref.Text == "" {
+		return categoryRef{}, fmt.Errorf("invalid category %q: expected an ID or code, optionally followed by :WEIGHT", value)
+	}
+	if id, err := strconv.Atoi(ref.Text); err == nil {
+		if id < 1 {
+			return categoryRef{}, fmt.Errorf("invalid category %q: IDs start at 1", value)
+		}
+		ref.ID = id
+	} else {
+		ref.Code = ref.Text
+	}
+	if hasWeight {
+		weight, err := strconv.ParseFloat(strings.TrimSpace(weightText), 64)
+		if err != nil || weight < 0 {
+			return categoryRef{}, fmt.Errorf("invalid category %q: the weight must be a non-negative number", value)
+		}
+		ref.Weight = &weight
+	}
+	return ref, nil
+}
+
+func (r categoryRef) matches(category api.ProjectCategory) bool {
+	if r.ID > 0 {
+		return category.ID == r.ID
+	}
+	return strings.EqualFold(category.Code, r.Code)
+}
+
+// resolveCategory looks a category up in the catalog. Codes must name
+// exactly one category; IDs are checked against the catalog when it knows
+// them, so the output can show the code.
+func resolveCategory(tokens *config.Tokens, ref categoryRef) (api.Category, error) {
+	opts := api.CategoryOptions{Search: ref.Code}
+	categories, err := withAuth(tokens, func(at string) ([]api.Category, error) {
+		return api.FetchCategories(at, opts)
+	})
+	if err != nil {
+		return api.Category{}, fmt.Errorf("failed to fetch categories: %w", err)
+	}
+
+	var matches []api.Category
+	for _, category := range categories {
+		if (ref.ID > 0 && category.ID == ref.ID) || (ref.ID == 0 && strings.EqualFold(category.Code, ref.Code)) {
+			matches = append(matches, category)
+		}
+	}
+	switch {
+	case len(matches) == 1:
+		return matches[0], nil
+	case len(matches) == 0 && ref.ID > 0:
+		return api.Category{ID: ref.ID}, nil
+	case len(matches) == 0:
+		return api.Category{}, fmt.Errorf("unknown category %q (see pz categories list --search)", ref.Code)
+	}
+	options := make([]string, len(matches))
+	for i, match := range matches {
+		options[i] = fmt.Sprintf("%d (%s)", match.ID, terminalSafeInline(match.SourceName))
+	}
+	return api.Category{}, fmt.Errorf("category %q exists in several sources; use its ID: %s", ref.Code, strings.Join(options, ", "))
+}
+
+func categoryLabel(category api.ProjectCategory) string {
+	if category.Code == "" {
+		return strconv.Itoa(category.ID)
+	}
+	return fmt.Sprintf("%s (%d)", terminalSafeInline(category.Code), category.ID)
+}
+
+func projectCategoryTable(categories []api.ProjectCategory) layout.Table {
+	table := layout.Table{
+		Columns: []layout.Column{
+			{Header: "ID", Priority: 5},
+			{Header: "CODE", Priority: 4},
+			{Header: "NAME", Priority: 2, Flexible: true, MinWidth: 12},
+			{Header: "SOURCE", Priority: 1},
+			{Header: "WEIGHT", Priority: 3, AlignRight: true},
+		},
+	}
+	for _, category := range categories {
+		table.Rows = append(table.Rows, []string{
+			strconv.Itoa(category.ID),
+			terminalSafeInline(category.Code),
+			terminalSafeInline(category.Name),
+			terminalSafeInline(category.SourceName),
+			strconv.FormatFloat(category.Weight, 'g', -1, 64),
+		})
+	}
+	return table
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+const categoriesTestProject = `{"id":"proj-1","name":"Graph Learning","categories":[
+	{"id":15,"code":"stat.ML","name":"Machine Learning","source_id":1,"source_name":"arXiv","weight":1}]}`
+
+func projectCategoriesTestRoutes(patches *[]string) testRoutes {
+	return testRoutes{
+		"/api/categories":          respondWith(catalogTestCategories),
+		"GET /api/projects/proj-1": respondWith(categoriesTestProject),
+		"PATCH /api/projects/proj-1": func(w http.ResponseWriter, r *http.Request) {
+			var input struct {
+				Categories []map[string]any `json:"categories"`
+			}
+			data, _ := io.ReadAll(r.Body)
+			*patches = append(*patches, strings.TrimSpace(string(data)))
+			_ = json.Unmarshal(data, &input)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "proj-1", "name": "Graph Learning", "categories": input.Categories})
+		},
+	}
+}
+
+func projectCategoriesTestFlags(cmd *cobra.Command) {
+	cmd.Flags().Float64("weight", 1, "")
+	cmd.Flags().Bool("json", false, "")
+}
+
+func TestProjectCategoriesList(t *testing.T) {
+	var patches []string
+	newTestAPI(t, projectCategoriesTestRoutes(&patches))
+
+	cmd, stdout, _ := newTestCommand(t, projectCategoriesTestFlags)
+	if err := projectCategoriesCmd.RunE(cmd, []string{"proj-1"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	want := "ID  CODE     NAME              SOURCE  WEIGHT\n15  stat.ML  Machine Learning  arXiv        1\n"
+	if stdout.String() != want {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+}
+
+func TestProjectCategoriesAddResolvesCodes(t *testing.T) {
+	var patches []string
+	newTestAPI(t, projectCategoriesTestRoutes(&patches))
+
+	cmd, stdout, stderr := newTestCommand(t, projectCategoriesTestFlags, "--weight", "0.5")
+	if err := projectCategoriesCmd.RunE(cmd, []string{"proj-1", "add", "bioinformatics", "STAT.ML", "12:0.8"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	want := `{"categories":[{"id":15,"weight":1},{"id":30,"weight":0.5},{"id":12,"weight":0.8}]}`
+	if len(patches) != 1 || patches[0] != want {
+		t.Fatalf("patches = %v", patches)
+	}
+	wantOut := "Added    bioinformatics (30), weight 0.5\nAdded    cs.LG (12), weight 0.8\n\nProject Graph Learning (proj-1) now watches 3 categories\n"
+	if stdout.String() != wantOut {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+	if stderr.String() != "Already watched: stat.ML (15) (use set-weight to change its weight)\n" {
+		t.Fatalf("stderr = %q", stderr.String())
+	}
+
+	cmd, _, _ = newTestCommand(t, projectCategoriesTestFlags)
+	err := projectCategoriesCmd.RunE(cmd, []string{"proj-1", "add", "cs.LG"})
+	if err == nil || !strings.Contains(err.Error(), "several sources; use its ID: 12 (arXiv), 31 (Mirror)") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestProjectCategoriesSetWeightAndRemove(t *testing.T) {
+	var patches []string
+	newTestAPI(t, projectCategoriesTestRoutes(&patches))
+
+	cmd, stdout, _ := newTestCommand(t, projectCategoriesTestFlags, "--weight", "0.25")
+	if err := projectCategoriesCmd.RunE(cmd, []string{"proj-1", "set-weight", "stat.ml"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	if len(patches) != 1 || patches[0] != `{"categories":[{"id":15,"weight":0.25}]}` {
+		t.Fatalf("patches = %v", patches)
+	}
+	if !strings.HasPrefix(stdout.String(), "Weight   stat.ML (15): 1 → 0.25\n") {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+
+	// Without a weight, set-weight fails instead of resetting it to 1.
+	cmd, _, _ = newTestCommand(t, projectCategoriesTestFlags)
+	if err := projectCategoriesCmd.RunE(cmd, []string{"proj-1", "set-weight", "stat.ML"}); err == nil || !strings.Contains(err.Error(), "set-weight needs a weight") {
+		t.Fatalf("err = %v", err)
+	}
+	if len(patches) != 1 {
+		t.Fatalf("patches = %v", patches)
+	}
+
+	cmd, _, _ = newTestCommand(t, projectCategoriesTestFlags)
+	if err := projectCategoriesCmd.RunE(cmd, []string{"proj-1", "set-weight", "12:2"}); err == nil || !strings.Contains(err.Error(), "does not watch category 12") {
+		t.Fatalf("err = %v", err)
+	}
+
+	cmd, stdout, _ = newTestCommand(t, projectCategoriesTestFlags, "--json")
+	if err := projectCategoriesCmd.RunE(cmd, []string{"proj-1", "remove", "15"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	if len(patches) != 2 || patches[1] != `{"categories":[]}` || strings.TrimSpace(stdout.String()) != "[]" {
+		t.Fatalf("patches = %v, stdout = %q", patches, stdout.String())
+	}
+}
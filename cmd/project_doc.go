@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/projectdoc"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	projectApplyCmd.Flags().StringP("file", "f", "", "Project file in YAML or JSON (- for stdin)")
+	projectApplyCmd.Flags().Bool("plan", false, "Show the changes without applying them")
+	_ = projectApplyCmd.MarkFlagRequired("file")
+	projectCmd.AddCommand(projectExportCmd, projectApplyCmd)
+}
+
+// projectDocFields maps API field names to project file keys, for server
+// validation errors.
+var projectDocFields = map[string]string{
+	"email_frequency":        "email.frequency",
+	"email_time":             "email.time",
+	"max_papers_per_digests": "max_papers_per_digest",
+	"positive_keywords":      "keywords.positive",
+	"negative_keywords":      "keywords.negative",
+	"source_ids":             "sources",
+}
+
+func projectDocField(field string) string {
+	top, rest, nested := strings.Cut(field, ".")
+	if key, ok := projectDocFields[top]; ok {
+		top = key
+	}
+	if nested {
+		return top + "." + rest
+	}
+	return top
+}
+
+type projectApplyResult struct {
+	ID      string              `json:"id"`
+	Name    string              `json:"name"`
+	Changes []projectdoc.Change `json:"changes"`
+	Applied bool                `json:"applied"`
+}
+
+var projectExportCmd = &cobra.Command{
+	Use:   "export <project-id>",
+	Short: "Print a project's editable settings as YAML",
+	Long: "Print a project's editable settings as YAML, or JSON with --json. The output\n" +
+		"is stable, so it can be kept in version control and applied with pz project apply.",
+	Example: `  pz project export <project-id> > project.yaml
+  pz project export <project-id> --json > project.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonOut, _ := cmd.Flags().GetBool("json")
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+		project, err := withAuth(&tokens, func(at string) (api.Project, error) {
+			return api.FetchProject(at, args[0])
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch project: %w", err)
+		}
+
+		doc := projectdoc.FromProject(project)
+		if jsonOut {
+			return writeJSON(cmd.OutOrStdout(), doc)
+		}
+		return projectdoc.EncodeYAML(cmd.OutOrStdout(), doc)
+	},
+}
+
+var projectApplyCmd = &cobra.Command{
+	Use:   "apply -f <file> [project-id]",
+	Short: "Make a project match a YAML or JSON file",
+	Long: "Compare a project file with the project on the server, print the plan, and\n" +
+		"send only the fields that differ. The project is the file's id unless one is\n" +
+		"given as an argument. Keys left out of the file are not changed; lists in the\n" +
+		"file replace the whole list. Source names and category codes are ignored.",
+	Example: `  pz project apply -f project.yaml --plan
+  pz project apply -f project.yaml
+  pz project export <project-id> | pz project apply -f - <other-project-id>`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("file")
+		planOnly, _ := cmd.Flags().GetBool("plan")
+		jsonOut, _ := cmd.Flags().GetBool("json")
+
+		data, err := readProjectFile(cmd.InOrStdin(), path)
+		if err != nil {
+			return err
+		}
+		parsed, err := projectdoc.Decode(data, projectdoc.Document{})
+		if err != nil {
+			return err
+		}
+		id := strings.TrimSpace(parsed.ID)
+		if len(args) == 1 {
+			id = args[0]
+		}
+		if id == "" {
+			return fmt.Errorf("project file has no id; pass the project ID as an argument")
+		}
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+		project, err := withAuth(&tokens, func(at string) (api.Project, error) {
+			return api.FetchProject(at, id)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch project: %w", err)
+		}
+
+		current := projectdoc.FromProject(project)
+		desired, err := projectdoc.Decode(data, current)
+		if err != nil {
+			return err
+		}
+		desired.ID = current.ID
+		if err := projectdoc.Validate(desired); err != nil {
+			return fmt.Errorf("invalid project file:\n%s", indentLines(err.Error(), "  "))
+		}
+
+		changes := projectdoc.Plan(current, desired)
+		result := projectApplyResult{ID: project.ID, Name: project.Name, Changes: changes}
+		if result.Changes == nil {
+			result.Changes = []projectdoc.Change{}
+		}
+		out := cmd.OutOrStdout()
+		if !jsonOut {
+			writeProjectPlan(out, project, changes)
+		}
+
+		if !planOnly && len(changes) > 0 {
+			updated, err := withAuth(&tokens, func(at string) (api.Project, error) {
+				return api.UpdateProject(at, project.ID, projectdoc.Input(desired, changes))
+			})
+			if err != nil {
+				return projectFieldError("apply", err, projectDocField)
+			}
+			result.Name = updated.Name
+			result.Applied = true
+		}
+
+		if jsonOut {
+			return writeJSON(out, result)
+		}
+		switch {
+		case len(changes) == 0:
+		case planOnly:
+			fmt.Fprintln(out, "\nRun without --plan to apply these changes.")
+		default:
+			fmt.Fprintf(out, "\nApplied %d %s to project %s (%s)\n", len(changes), plural(len(changes), "change", "changes"),
+				terminalSafeInline(result.Name), terminalSafeInline(project.ID))
+		}
+		return nil
+	},
+}
+
+func readProjectFile(stdin io.Reader, path string) ([]byte, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project file: %w", err)
+	}
+	return data, nil
+}
+
+// writeProjectPlan prints one entry per changed field: scalars as
+// before → after, lists as added and removed items, and the interest
+// description line by line.
+func writeProjectPlan(out io.Writer, project api.Project, changes []projectdoc.Change) {
+	name := terminalSafeInline(project.Name)
+	id := terminalSafeInline(project.ID)
+	if len(changes) == 0 {
+		fmt.Fprintf(out, "Project %s (%s) is up to date.\n", name, id)
+		return
+	}
+	fmt.Fprintf(out, "Plan for project %s (%s):\n", name, id)
+	for _, change := range changes {
+		switch {
+		case change.Field == "interest_description":
+			fmt.Fprintf(out, "  ~ %s\n", change.Field)
+			for _, line := range strings.Split(change.Before, "\n") {
+				fmt.Fprintf(out, "      - %s\n", terminalSafeInline(line))
+			}
+			for _, line := range strings.Split(change.After, "\n") {
+				fmt.Fprintf(out, "      + %s\n", terminalSafeInline(line))
+			}
+		case change.Added != nil || change.Removed != nil:
+			fmt.Fprintf(out, "  ~ %s\n", change.Field)
+			for _, item := range change.Removed {
+				fmt.Fprintf(out, "      - %s\n", terminalSafeInline(item))
+			}
+			for _, item := range change.Added {
+				fmt.Fprintf(out, "      + %s\n", terminalSafeInline(item))
+			}
+		default:
+			fmt.Fprintf(out, "  ~ %s: %q → %q\n", change.Field, terminalSafeInline(change.Before), terminalSafeInline(change.After))
+		}
+	}
+}
+
+func indentLines(text, indent string) string {
+	return indent + strings.ReplaceAll(text, "\n", "\n"+indent)
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+const projectDocTestProject = `{"id":"proj-1","name":"Graph Learning","mode":"auto","visibility":"private",
+	"interest_description":"Graph neural networks","email_frequency":"weekly","email_time":"07:00",
+	"max_candidates":200,"max_papers_per_digests":10,"positive_keywords":["GNN"],"negative_keywords":[],
+	"sources":[{"id":1,"name":"arXiv"}],"categories":[{"id":12,"code":"cs.LG","weight":1}]}`
+
+func projectDocTestRoutes(patches *[]string) testRoutes {
+	return testRoutes{
+		"GET /api/projects/proj-1": respondWith(projectDocTestProject),
+		"PATCH /api/projects/proj-1": func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			*patches = append(*patches, string(data))
+			_, _ = w.Write([]byte(projectDocTestProject))
+		},
+	}
+}
+
+func projectApplyTestFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("file", "f", "", "")
+	cmd.Flags().Bool("plan", false, "")
+	cmd.Flags().Bool("json", false, "")
+}
+
+func TestProjectExportThenApplyIsANoOp(t *testing.T) {
+	var patches []string
+	newTestAPI(t, projectDocTestRoutes(&patches))
+
+	cmd, stdout, _ := newTestCommand(t, projectApplyTestFlags)
+	if err := projectExportCmd.RunE(cmd, []string{"proj-1"}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if !strings.HasPrefix(stdout.String(), "id: proj-1\nname: Graph Learning\n") {
+		t.Fatalf("export = %q", stdout.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "project.yaml")
+	if err := os.WriteFile(path, stdout.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cmd, stdout, _ = newTestCommand(t, projectApplyTestFlags, "-f", path)
+	if err := projectApplyCmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if stdout.String() != "Project Graph Learning (proj-1) is up to date.\n" || len(patches) != 0 {
+		t.Fatalf("stdout = %q, patches = %v", stdout.String(), patches)
+	}
+}
+
+func TestProjectApplyPlansAndSendsOnlyChanges(t *testing.T) {
+	var patches []string
+	newTestAPI(t, projectDocTestRoutes(&patches))
+
+	path := filepath.Join(t.TempDir(), "project.yaml")
+	file := "id: proj-1\nemail:\n  time: \"07:30\"\nkeywords:\n  positive: [GNN, equivariance]\n"
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd, stdout, _ := newTestCommand(t, projectApplyTestFlags, "-f", path, "--plan")
+	if err := projectApplyCmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	plan := "Plan for project Graph Learning (proj-1):\n" +
+		"  ~ email.time: \"07:00\" → \"07:30\"\n" +
+		"  ~ keywords.positive\n" +
+		"      + equivariance\n"
+	if stdout.String() != plan+"\nRun without --plan to apply these changes.\n" || len(patches) != 0 {
+		t.Fatalf("stdout = %q, patches = %v", stdout.String(), patches)
+	}
+
+	cmd, stdout, _ = newTestCommand(t, projectApplyTestFlags, "-f", path)
+	if err := projectApplyCmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if stdout.String() != plan+"\nApplied 2 changes to project Graph Learning (proj-1)\n" {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+	want := `{"email_time":"07:30","positive_keywords":["GNN","equivariance"]}`
+	if len(patches) != 1 || strings.TrimSpace(patches[0]) != want {
+		t.Fatalf("patches = %v", patches)
+	}
+
+	cmd, stdout, _ = newTestCommand(t, projectApplyTestFlags, "-f", path, "--plan", "--json")
+	if err := projectApplyCmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("plan --json: %v", err)
+	}
+	var result projectApplyResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil || result.Applied || len(result.Changes) != 2 {
+		t.Fatalf("result = %+v, err = %v", result, err)
+	}
+}
+
+func TestProjectApplyRejectsInvalidFiles(t *testing.T) {
+	var patches []string
+	newTestAPI(t, projectDocTestRoutes(&patches))
+	dir := t.TempDir()
+
+	cases := map[string]string{
+		"name: Graph\n":                            "project file has no id",
+		"id: proj-1\nmax_candidate: 3\n":           "field max_candidate not found",
+		"id: proj-1\nsources: [{id: 0}]\n":         "invalid project file:\n  sources[0]: id must be a source ID",
+		"id: proj-1\ninterest_description: \"\"\n": "interest_description is required",
+	}
+	for file, want := range cases {
+		path := filepath.Join(dir, "project.yaml")
+		if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		cmd, _, _ := newTestCommand(t, projectApplyTestFlags, "-f", path)
+		if err := projectApplyCmd.RunE(cmd, nil); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%q: err = %v", file, err)
+		}
+	}
+	if len(patches) != 0 {
+		t.Fatalf("patches = %v", patches)
+	}
+}
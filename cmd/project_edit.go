@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/projectdoc"
+	"github.com/spf13/cobra"
+)
+
+var runEditorFunc = runEditor
+
+func init() {
+	projectEditCmd.Flags().BoolP("yes", "y", false, "Apply the changes without asking for confirmation")
+	projectCmd.AddCommand(projectEditCmd)
+}
+
+var projectEditCmd = &cobra.Command{
+	Use:   "edit <project-id>",
+	Short: "Edit a project's interest, keywords and email schedule in $EDITOR",
+	Long: "Open the project's interest description, keywords and email schedule in\n" +
+		"$VISUAL or $EDITOR as annotated YAML. Problems are written into the file as\n" +
+		"comments and the editor is opened again. The changes are shown before they\n" +
+		"are applied; an unchanged or empty file aborts the edit.",
+	Example: `  pz project edit <project-id>
+  EDITOR="code --wait" pz project edit <project-id>`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		yes, _ := cmd.Flags().GetBool("yes")
+		jsonOut, _ := cmd.Flags().GetBool("json")
+		out := cmd.OutOrStdout()
+		errOut := cmd.ErrOrStderr()
+		// Keep stdout for the updated project with --json.
+		info := out
+		if jsonOut {
+			info = errOut
+		}
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+		project, err := withAuth(&tokens, func(at string) (api.Project, error) {
+			return api.FetchProject(at, args[0])
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch project: %w", err)
+		}
+
+		current := projectdoc.FromProject(project)
+		text, err := projectdoc.EditTemplate(current)
+		if err != nil {
+			return err
+		}
+		original := text
+		// fixing is set when the editor was reopened with problems, so saving
+		// without fixing them aborts instead of looping.
+		fixing := false
+		stdin := bufio.NewReader(cmd.InOrStdin())
+		for {
+			edited, err := editProjectFile(text)
+			if err != nil {
+				return err
+			}
+			if bytes.Equal(edited, original) || (fixing && bytes.Equal(edited, text)) || projectdoc.Blank(edited) {
+				fmt.Fprintln(info, "No changes; project was not updated.")
+				return nil
+			}
+
+			desired, err := projectdoc.DecodeEdit(edited, current)
+			if err == nil {
+				desired.ID = current.ID
+				err = projectdoc.Validate(desired)
+			}
+			if err != nil {
+				fmt.Fprintf(errOut, "%s\nReopening the editor.\n", err)
+				text, fixing = projectdoc.Annotate(edited, projectProblems(err)), true
+				continue
+			}
+
+			changes := projectdoc.Plan(current, desired)
+			if len(changes) == 0 {
+				fmt.Fprintln(info, "No changes; project was not updated.")
+				return nil
+			}
+			writeProjectPlan(info, project, changes)
+			if !yes {
+				fmt.Fprint(errOut, "\nApply these changes? [Y/n/e(dit)] ")
+				answer, _ := stdin.ReadString('\n')
+				switch strings.ToLower(strings.TrimSpace(answer)) {
+				case "", "y", "yes":
+				case "e", "edit":
+					text, fixing = edited, false
+					continue
+				default:
+					return fmt.Errorf("aborted; project was not updated")
+				}
+			}
+
+			updated, err := withAuth(&tokens, func(at string) (api.Project, error) {
+				return api.UpdateProject(at, project.ID, projectdoc.Input(desired, changes))
+			})
+			var apiErr *api.APIError
+			if errors.As(err, &apiErr) && len(apiErr.FieldErrors) > 0 {
+				fmt.Fprintf(errOut, "%s\nReopening the editor.\n", projectFieldError("update", err, projectDocField))
+				text, fixing = projectdoc.Annotate(edited, projectProblems(err)), true
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to update project: %w", err)
+			}
+
+			if jsonOut {
+				return writeJSON(out, updated)
+			}
+			fmt.Fprintf(out, "\nUpdated project %s (%s)\n", terminalSafeInline(updated.Name), terminalSafeInline(updated.ID))
+			return nil
+		}
+	},
+}
+
+// projectProblems turns local or server validation errors into problems
+// that can be placed next to their field in the edited file.
+func projectProblems(err error) []projectdoc.Problem {
+	var validationErr *projectdoc.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Problems
+	}
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) && len(apiErr.FieldErrors) > 0 {
+		problems := make([]projectdoc.Problem, len(apiErr.FieldErrors))
+		for i, fieldErr := range apiErr.FieldErrors {
+			field := projectDocField(fieldErr.Field)
+			problems[i] = projectdoc.Problem{Field: field, Message: field + ": " + fieldErr.Message}
+		}
+		return problems
+	}
+	return []projectdoc.Problem{{Message: err.Error()}}
+}
+
+// editProjectFile writes content to a temporary file, opens it in the
+// editor and returns what was saved.
+func editProjectFile(content []byte) ([]byte, error) {
+	file, err := os.CreateTemp("", "pz-project-*.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create project file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write project file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write project file: %w", err)
+	}
+
+	if err := runEditorFunc(file.Name()); err != nil {
+		return nil, fmt.Errorf("editor failed: %w", err)
+	}
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project file: %w", err)
+	}
+	return data, nil
+}
+
+func editorCommand() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(name)); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// runEditor runs the editor on path with the terminal attached. Like git, the
+// editor setting may include arguments.
+func runEditor(path string) error {
+	editor := editorCommand()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		fields := strings.Fields(editor)
+		cmd = exec.Command(fields[0], append(fields[1:], path)...)
+	} else {
+		cmd = exec.Command("sh", "-c", editor+` "$@"`, editor, path)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// stubEditor replaces the editor with edits applied in turn; each gets the
+// file content and returns what is saved. It returns what each edit saw.
+func stubEditor(t *testing.T, edits ...func(string) string) *[]string {
+	t.Helper()
+	var seen []string
+	original := runEditorFunc
+	t.Cleanup(func() { runEditorFunc = original })
+	runEditorFunc = func(path string) error {
+		if len(seen) == len(edits) {
+			t.Fatalf("editor opened %d times", len(seen)+1)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		seen = append(seen, string(data))
+		return os.WriteFile(path, []byte(edits[len(seen)-1](string(data))), 0o600)
+	}
+	return &seen
+}
+
+func projectEditTestFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolP("yes", "y", false, "")
+	cmd.Flags().Bool("json", false, "")
+}
+
+func TestProjectEditReopensOnProblemsAndSendsChanges(t *testing.T) {
+	var patches []string
+	newTestAPI(t, projectDocTestRoutes(&patches))
+
+	seen := stubEditor(t,
+		func(s string) string {
+			return strings.Replace(s, "interest_description: Graph neural networks", `interest_description: ""`, 1)
+		},
+		func(s string) string {
+			s = strings.Replace(s, `interest_description: ""`, "interest_description: |\n  Equivariant graph networks\n  for molecules", 1)
+			return strings.Replace(s, "- GNN", "- GNN\n    - equivariance", 1)
+		},
+	)
+
+	cmd, stdout, _ := newTestCommand(t, projectEditTestFlags)
+	cmd.SetIn(strings.NewReader("\n"))
+	if err := projectEditCmd.RunE(cmd, []string{"proj-1"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	if !strings.Contains((*seen)[0], "# Editing project Graph Learning (proj-1).") {
+		t.Fatalf("template = %s", (*seen)[0])
+	}
+	if !strings.Contains((*seen)[1], "# ERROR: interest_description is required\n# What this project is about") {
+		t.Fatalf("reopened = %s", (*seen)[1])
+	}
+
+	want := `{"interest_description":"Equivariant graph networks\nfor molecules","positive_keywords":["GNN","equivariance"]}`
+	if len(patches) != 1 || strings.TrimSpace(patches[0]) != want {
+		t.Fatalf("patches = %v", patches)
+	}
+	wantOut := "Plan for project Graph Learning (proj-1):\n" +
+		"  ~ interest_description\n" +
+		"      - Graph neural networks\n" +
+		"      + Equivariant graph networks\n" +
+		"      + for molecules\n" +
+		"  ~ keywords.positive\n" +
+		"      + equivariance\n" +
+		"\nUpdated project Graph Learning (proj-1)\n"
+	if stdout.String() != wantOut {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+}
+
+func TestProjectEditAbortsWhenUnchanged(t *testing.T) {
+	var patches []string
+	newTestAPI(t, projectDocTestRoutes(&patches))
+	stubEditor(t, func(s string) string { return s })
+
+	cmd, stdout, _ := newTestCommand(t, projectEditTestFlags)
+	cmd.SetIn(strings.NewReader(""))
+	if err := projectEditCmd.RunE(cmd, []string{"proj-1"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	if stdout.String() != "No changes; project was not updated.\n" || len(patches) != 0 {
+		t.Fatalf("stdout = %q, patches = %v", stdout.String(), patches)
+	}
+
+	stubEditor(t, func(s string) string { return strings.Replace(s, "07:00", "08:00", 1) })
+	cmd, _, _ = newTestCommand(t, projectEditTestFlags)
+	cmd.SetIn(strings.NewReader("n\n"))
+	if err := projectEditCmd.RunE(cmd, []string{"proj-1"}); err == nil || !strings.Contains(err.Error(), "aborted") || len(patches) != 0 {
+		t.Fatalf("err = %v, patches = %v", err, patches)
+	}
+}
+
+func TestProjectEditAnnotatesServerErrors(t *testing.T) {
+	newTestAPI(t, testRoutes{
+		"GET /api/projects/proj-1": respondWith(projectDocTestProject),
+		"PATCH /api/projects/proj-1": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"detail":[{"loc":["body","email_time"],"msg":"must be HH:MM"}]}`))
+		},
+	})
+
+	seen := stubEditor(t,
+		func(s string) string { return strings.Replace(s, `"07:00"`, "7am", 1) },
+		func(s string) string { return s },
+	)
+	cmd, stdout, _ := newTestCommand(t, projectEditTestFlags)
+	cmd.SetIn(strings.NewReader(""))
+	if err := projectEditCmd.RunE(cmd, []string{"proj-1"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	if !strings.Contains((*seen)[1], "  # ERROR: email.time: must be HH:MM\n  time: 7am\n") {
+		t.Fatalf("reopened = %s", (*seen)[1])
+	}
+	if !strings.HasSuffix(stdout.String(), "No changes; project was not updated.\n") {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+}
@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/projectdoc"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	projectKeywordsCmd.Flags().Bool("negative", false, "Work on the negative keywords instead of the positive ones")
+	projectKeywordsCmd.Flags().StringP("file", "f", "", "Read keywords from a file, one per line (- for stdin)")
+	projectCmd.AddCommand(projectKeywordsCmd)
+}
+
+var keywordActions = []string{"list", "add", "remove", "replace"}
+
+type projectKeywordsResult struct {
+	ID       string   `json:"id"`
+	Positive []string `json:"positive"`
+	Negative []string `json:"negative"`
+	Added    []string `json:"added,omitempty"`
+	Removed  []string `json:"removed,omitempty"`
+}
+
+var projectKeywordsCmd = &cobra.Command{
+	Use:   "keywords <project-id> [list|add|remove|replace] [keyword...]",
+	Short: "List or change a project's matching keywords",
+	Long: "List or change a project's positive keywords, or negative ones with --negative.\n" +
+		"Keywords you pass are lowercased, whitespace is collapsed and duplicates are\n" +
+		"dropped. add and remove match existing keywords case-insensitively and leave\n" +
+		"the others as they are; replace writes the normalized list.\n" +
+		"A keyword in one list cannot be added to the other; keywords that are\n" +
+		"already in both are only warned about.\n" +
+		"With --file, keywords are read one per line, and # starts a comment line.",
+	Example: `  pz project keywords <project-id>
+  pz project keywords <project-id> add "graph transformers" equivariance
+  pz project keywords <project-id> add --negative survey "position paper"
+  pz project keywords <project-id> remove --negative -f noisy.txt
+  pz project keywords <project-id> replace -f keywords.txt`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		negative, _ := cmd.Flags().GetBool("negative")
+		path, _ := cmd.Flags().GetString("file")
+		jsonOut, _ := cmd.Flags().GetBool("json")
+
+		action := "list"
+		keywords := args[1:]
+		if len(args) > 1 {
+			action, keywords = args[1], args[2:]
+			if !slices.Contains(keywordActions, action) {
+				return fmt.Errorf("unknown keywords action %q (expected list, add, remove, or replace)", action)
+			}
+		}
+		if path != "" {
+			if action == "list" {
+				return fmt.Errorf("--file can only be used with add, remove, or replace")
+			}
+			fromFile, err := readKeywordsFile(cmd.InOrStdin(), path)
+			if err != nil {
+				return err
+			}
+			keywords = append(keywords, fromFile...)
+		}
+		if action == "list" && len(keywords) > 0 {
+			return fmt.Errorf("list takes no keywords")
+		}
+		if (action == "add" || action == "remove") && len(projectdoc.NormalizeKeywords(keywords)) == 0 {
+			return fmt.Errorf("no keywords to %s; pass them as arguments or with --file", action)
+		}
+		if action == "replace" && len(keywords) == 0 && path == "" {
+			return fmt.Errorf("no keywords to replace with; pass them as arguments or with --file (an empty file clears the list)")
+		}
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+		project, err := withAuth(&tokens, func(at string) (api.Project, error) {
+			return api.FetchProject(at, args[0])
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch project: %w", err)
+		}
+
+		out := cmd.OutOrStdout()
+		if action == "list" {
+			result := projectKeywordsResult{ID: project.ID, Positive: project.PositiveKeywords, Negative: project.NegativeKeywords}
+			if jsonOut {
+				return writeJSON(out, result)
+			}
+			writeProjectKeywords(out, project, negative)
+			return nil
+		}
+
+		current, other := project.PositiveKeywords, project.NegativeKeywords
+		kind, otherKind := "positive", "negative"
+		if negative {
+			current, other = other, current
+			kind, otherKind = otherKind, kind
+		}
+		updated, added, removed, missing := editKeywords(action, current, keywords)
+		// Only the keywords this command adds are checked, so a conflict
+		// from before does not block unrelated changes.
+		if conflicts := projectdoc.KeywordConflicts(added, other); len(conflicts) > 0 {
+			return fmt.Errorf("already %s keywords: %s (a keyword cannot be both positive and negative)",
+				otherKind, terminalSafeInline(strings.Join(conflicts, ", ")))
+		}
+		if conflicts := projectdoc.KeywordConflicts(updated, other); len(conflicts) > 0 {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: also %s keywords: %s\n", otherKind, terminalSafeInline(strings.Join(conflicts, ", ")))
+		}
+		for _, keyword := range missing {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Not a %s keyword: %s\n", kind, terminalSafeInline(keyword))
+		}
+
+		changed := !slices.Equal(updated, current)
+		if changed {
+			var input api.ProjectInput
+			if negative {
+				input.NegativeKeywords = &updated
+			} else {
+				input.PositiveKeywords = &updated
+			}
+			project, err = withAuth(&tokens, func(at string) (api.Project, error) {
+				return api.UpdateProject(at, project.ID, input)
+			})
+			if err != nil {
+				return projectWriteError("update", err)
+			}
+		}
+
+		if jsonOut {
+			return writeJSON(out, projectKeywordsResult{
+				ID:       project.ID,
+				Positive: project.PositiveKeywords,
+				Negative: project.NegativeKeywords,
+				Added:    added,
+				Removed:  removed,
+			})
+		}
+		if len(added) == 0 && len(removed) == 0 {
+			message := "No changes to"
+			if changed {
+				message = "Normalized"
+			}
+			fmt.Fprintf(out, "%s the %s keywords of %s (%s)\n", message, kind, terminalSafeInline(project.Name), terminalSafeInline(project.ID))
+			return nil
+		}
+		for _, keyword := range added {
+			fmt.Fprintf(out, "Added    %s\n", terminalSafeInline(keyword))
+		}
+		for _, keyword := range removed {
+			fmt.Fprintf(out, "Removed  %s\n", terminalSafeInline(keyword))
+		}
+		fmt.Fprintf(out, "\nProject %s (%s) now has %d %s %s\n", terminalSafeInline(project.Name), terminalSafeInline(project.ID),
+			len(updated), kind, plural(len(updated), "keyword", "keywords"))
+		return nil
+	},
+}
+
+// editKeywords applies an add, remove or replace to current. Only the given
+// keywords are normalized: existing ones are matched case-insensitively and
+// kept as they are, except that replace writes the given list. added and
+// removed list what actually changed, and missing lists keywords that could
+// not be removed.
+func editKeywords(action string, current, keywords []string) (updated, added, removed, missing []string) {
+	keywords = projectdoc.NormalizeKeywords(keywords)
+	has := func(list []string, keyword string) bool {
+		return slices.ContainsFunc(list, func(k string) bool { return projectdoc.NormalizeKeyword(k) == keyword })
+	}
+	switch action {
+	case "add":
+		updated = slices.Clone(current)
+		for _, keyword := range keywords {
+			if !has(current, keyword) {
+				updated = append(updated, keyword)
+				added = append(added, keyword)
+			}
+		}
+	case "remove":
+		updated = []string{}
+		for _, keyword := range current {
+			if slices.Contains(keywords, projectdoc.NormalizeKeyword(keyword)) {
+				removed = append(removed, keyword)
+			} else {
+				updated = append(updated, keyword)
+			}
+		}
+		for _, keyword := range keywords {
+			if !has(current, keyword) {
+				missing = append(missing, keyword)
+			}
+		}
+	case "replace":
+		updated = keywords
+		for _, keyword := range keywords {
+			if !has(current, keyword) {
+				added = append(added, keyword)
+			}
+		}
+		for _, keyword := range current {
+			if !slices.Contains(keywords, projectdoc.NormalizeKeyword(keyword)) {
+				removed = append(removed, keyword)
+			}
+		}
+	}
+	if updated == nil {
+		updated = []string{}
+	}
+	return updated, added, removed, missing
+}
+
+func readKeywordsFile(stdin io.Reader, path string) ([]string, error) {
+	if path == "-" {
+		return projectdoc.ReadKeywords(stdin)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keywords: %w", err)
+	}
+	defer file.Close()
+	return projectdoc.ReadKeywords(file)
+}
+
+// writeProjectKeywords lists both keyword lists, or only the negative one,
+// and warns about keywords that are in both.
+func writeProjectKeywords(out io.Writer, project api.Project, negativeOnly bool) {
+	lists := []struct {
+		kind     string
+		keywords []string
+	}{{"Positive", project.PositiveKeywords}, {"Negative", project.NegativeKeywords}}
+	if negativeOnly {
+		lists = lists[1:]
+	}
+	for i, list := range lists {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "%s keywords (%d):\n", list.kind, len(list.keywords))
+		if len(list.keywords) == 0 {
+			fmt.Fprintln(out, "  (none)")
+		}
+		for _, keyword := range list.keywords {
+			fmt.Fprintf(out, "  %s\n", terminalSafeInline(keyword))
+		}
+	}
+	if conflicts := projectdoc.KeywordConflicts(project.PositiveKeywords, project.NegativeKeywords); len(conflicts) > 0 {
+		fmt.Fprintf(out, "\nIn both lists: %s\n", terminalSafeInline(strings.Join(conflicts, ", ")))
+	}
+}
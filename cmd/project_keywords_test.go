@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+const keywordsTestProject = `{"id":"proj-1","name":"Graph Learning","positive_keywords":["GNN","message passing"],"negative_keywords":["survey"]}`
+
+func projectKeywordsTestRoutes(project string, patches *[]string) testRoutes {
+	return testRoutes{
+		"GET /api/projects/proj-1": respondWith(project),
+		"PATCH /api/projects/proj-1": func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			*patches = append(*patches, strings.TrimSpace(string(data)))
+			var input map[string]any
+			_ = json.Unmarshal(data, &input)
+			var current map[string]any
+			_ = json.Unmarshal([]byte(project), &current)
+			for key, value := range input {
+				current[key] = value
+			}
+			_ = json.NewEncoder(w).Encode(current)
+		},
+	}
+}
+
+func projectKeywordsTestFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("negative", false, "")
+	cmd.Flags().StringP("file", "f", "", "")
+	cmd.Flags().Bool("json", false, "")
+}
+
+func TestProjectKeywordsList(t *testing.T) {
+	var patches []string
+	newTestAPI(t, projectKeywordsTestRoutes(`{"id":"proj-1","name":"Graph Learning","positive_keywords":["GNN","Survey"],"negative_keywords":["survey"]}`, &patches))
+
+	cmd, stdout, _ := newTestCommand(t, projectKeywordsTestFlags)
+	if err := projectKeywordsCmd.RunE(cmd, []string{"proj-1"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	want := "Positive keywords (2):\n  GNN\n  Survey\n\nNegative keywords (1):\n  survey\n\nIn both lists: survey\n"
+	if stdout.String() != want {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+
+	cmd, _, _ = newTestCommand(t, projectKeywordsTestFlags)
+	if err := projectKeywordsCmd.RunE(cmd, []string{"proj-1", "rename"}); err == nil || !strings.Contains(err.Error(), "unknown keywords action") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestProjectKeywordsAddNormalizesAndDeduplicates(t *testing.T) {
+	var patches []string
+	newTestAPI(t, projectKeywordsTestRoutes(keywordsTestProject, &patches))
+
+	cmd, stdout, _ := newTestCommand(t, projectKeywordsTestFlags, "-f", "-")
+	cmd.SetIn(strings.NewReader("# from review\nEquivariance\n gnn \n"))
+	if err := projectKeywordsCmd.RunE(cmd, []string{"proj-1", "add", "Graph  Transformers", "equivariance"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	want := `{"positive_keywords":["GNN","message passing","graph transformers","equivariance"]}`
+	if len(patches) != 1 || patches[0] != want {
+		t.Fatalf("patches = %v", patches)
+	}
+	wantOut := "Added    graph transformers\nAdded    equivariance\n\nProject Graph Learning (proj-1) now has 4 positive keywords\n"
+	if stdout.String() != wantOut {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+}
+
+func TestProjectKeywordsRejectsConflicts(t *testing.T) {
+	var patches []string
+	newTestAPI(t, projectKeywordsTestRoutes(keywordsTestProject, &patches))
+
+	cmd, _, _ := newTestCommand(t, projectKeywordsTestFlags)
+	err := projectKeywordsCmd.RunE(cmd, []string{"proj-1", "add", "Survey", "benchmarks"})
+	if err == nil || err.Error() != "already negative keywords: survey (a keyword cannot be both positive and negative)" {
+		t.Fatalf("err = %v", err)
+	}
+	cmd, _, _ = newTestCommand(t, projectKeywordsTestFlags, "--negative")
+	err = projectKeywordsCmd.RunE(cmd, []string{"proj-1", "replace", "GNN"})
+	if err == nil || !strings.Contains(err.Error(), "already positive keywords: gnn") {
+		t.Fatalf("err = %v", err)
+	}
+	if len(patches) != 0 {
+		t.Fatalf("patches = %v", patches)
+	}
+}
+
+func TestProjectKeywordsOnlyRejectsNewConflicts(t *testing.T) {
+	var patches []string
+	newTestAPI(t, projectKeywordsTestRoutes(`{"id":"proj-1","name":"Graph Learning","positive_keywords":["gnn","survey"],"negative_keywords":["survey"]}`, &patches))
+
+	cmd, stdout, stderr := newTestCommand(t, projectKeywordsTestFlags)
+	if err := projectKeywordsCmd.RunE(cmd, []string{"proj-1", "add", "equivariance"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	if len(patches) != 1 || patches[0] != `{"positive_keywords":["gnn","survey","equivariance"]}` {
+		t.Fatalf("patches = %v", patches)
+	}
+	if !strings.HasPrefix(stdout.String(), "Added    equivariance\n") {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+	if stderr.String() != "Warning: also negative keywords: survey\n" {
+		t.Fatalf("stderr = %q", stderr.String())
+	}
+}
+
+func TestProjectKeywordsRemoveAndReplace(t *testing.T) {
+	var patches []string
+	newTestAPI(t, projectKeywordsTestRoutes(keywordsTestProject, &patches))
+
+	cmd, stdout, stderr := newTestCommand(t, projectKeywordsTestFlags, "--negative", "--json")
+	if err := projectKeywordsCmd.RunE(cmd, []string{"proj-1", "remove", "SURVEY", "review"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	if len(patches) != 1 || patches[0] != `{"negative_keywords":[]}` {
+		t.Fatalf("patches = %v", patches)
+	}
+	var result projectKeywordsResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil || len(result.Negative) != 0 || len(result.Removed) != 1 {
+		t.Fatalf("result = %+v, err = %v", result, err)
+	}
+	if stderr.String() != "Not a negative keyword: review\n" {
+		t.Fatalf("stderr = %q", stderr.String())
+	}
+
+	cmd, stdout, _ = newTestCommand(t, projectKeywordsTestFlags)
+	if err := projectKeywordsCmd.RunE(cmd, []string{"proj-1", "replace", "gnn", "message passing"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	if len(patches) != 2 || patches[1] != `{"positive_keywords":["gnn","message passing"]}` {
+		t.Fatalf("patches = %v", patches)
+	}
+	if stdout.String() != "Normalized the positive keywords of Graph Learning (proj-1)\n" {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+}
@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	for _, c := range []*cobra.Command{projectCreateCmd, projectUpdateCmd} {
+		addProjectFieldFlags(c)
+	}
+	projectDeleteCmd.Flags().BoolP("yes", "y", false, "Delete without asking for confirmation")
+	projectCmd.AddCommand(projectCreateCmd, projectUpdateCmd, projectDeleteCmd)
+}
+
+// projectFieldFlags maps API field names to the flags that set them, so
+// validation errors can point at the flag to fix.
+var projectFieldFlags = map[string]string{
+	"name":                   "name",
+	"interest_description":   "interest",
+	"mode":                   "mode",
+	"visibility":             "visibility",
+	"email_frequency":        "email-frequency",
+	"email_time":             "email-time",
+	"max_candidates":         "max-candidates",
+	"max_papers_per_digests": "max-papers-per-digest",
+	"positive_keywords":      "positive-keyword",
+	"negative_keywords":      "negative-keyword",
+	"source_ids":             "source",
+	"categories":             "category",
+}
+
+type projectDeleteResponse struct {
+	ID      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+}
+
+func addProjectFieldFlags(c *cobra.Command) {
+	c.Flags().String("name", "", "Project name")
+	c.Flags().String("interest", "", "Interest description used for matching")
+	c.Flags().String("interest-file", "", "Read the interest description from a file (- for stdin)")
+	c.Flags().String("mode", "", "Project mode")
+	c.Flags().String("visibility", "", "Project visibility")
+	c.Flags().String("email-frequency", "", "Digest email frequency (e.g. daily or weekly)")
+	c.Flags().String("email-time", "", "Digest email time (HH:MM)")
+	c.Flags().Int("max-candidates", 0, "Maximum candidate papers considered per run")
+	c.Flags().Int("max-papers-per-digest", 0, "Maximum papers per digest email")
+	c.Flags().StringArray("positive-keyword", nil, "Positive keyword (repeatable; replaces the list)")
+	c.Flags().StringArray("negative-keyword", nil, "Negative keyword (repeatable; replaces the list)")
+	c.Flags().StringArray("source", nil, "Source ID to watch (repeatable; replaces the list)")
+	c.Flags().StringArray("category", nil, "Category ID to watch, optionally with a weight as ID:WEIGHT (repeatable; replaces the list)")
+}
+
+var projectCreateCmd = &cobra.Command{
+	Use:   "create --name <name> --interest <text>",
+	Short: "Create a project",
+	Example: `  pz project create --name "Graph Learning" --interest "Graph neural networks for molecules"
+  pz project create --name "LLM Evals" --interest-file interest.md --email-frequency weekly --positive-keyword benchmark`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		input, err := projectInputFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		if input.Name == nil || strings.TrimSpace(*input.Name) == "" {
+			return fmt.Errorf("invalid project: --name is required")
+		}
+		if input.InterestDescription == nil || strings.TrimSpace(*input.InterestDescription) == "" {
+			return fmt.Errorf("invalid project: --interest or --interest-file is required")
+		}
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+		project, err := withAuth(&tokens, func(at string) (api.Project, error) {
+			return api.CreateProject(at, input)
+		})
+		if err != nil {
+			return projectWriteError("create", err)
+		}
+
+		if jsonOut, _ := cmd.Flags().GetBool("json"); jsonOut {
+			return writeJSON(cmd.OutOrStdout(), project)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Created project %s (%s)\n", terminalSafeInline(project.Name), terminalSafeInline(project.ID))
+		return nil
+	},
+}
+
+var projectUpdateCmd = &cobra.Command{
+	Use:   "update <project-id>",
+	Short: "Change project settings",
+	Long: "Change project settings. Only the fields whose flags are given are sent.\n" +
+		"List flags (keywords, sources, categories) replace the whole list; pass the\n" +
+		"flag once with an empty value to clear it.",
+	Example: `  pz project update <project-id> --email-frequency daily --email-time 07:30
+  pz project update <project-id> --interest-file interest.md
+  pz project update <project-id> --category 12:1.0 --category 15:0.5`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		input, err := projectInputFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		if input == (api.ProjectInput{}) {
+			return fmt.Errorf("nothing to update; pass at least one field flag (see pz project update --help)")
+		}
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+		project, err := withAuth(&tokens, func(at string) (api.Project, error) {
+			return api.UpdateProject(at, args[0], input)
+		})
+		if err != nil {
+			return projectWriteError("update", err)
+		}
+
+		if jsonOut, _ := cmd.Flags().GetBool("json"); jsonOut {
+			return writeJSON(cmd.OutOrStdout(), project)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Updated project %s (%s)\n", terminalSafeInline(project.Name), terminalSafeInline(project.ID))
+		return nil
+	},
+}
+
+var projectDeleteCmd = &cobra.Command{
+	Use:   "delete <project-id>",
+	Short: "Delete a project and its recommendations",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		yes, _ := cmd.Flags().GetBool("yes")
+		jsonOut, _ := cmd.Flags().GetBool("json")
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+		project, err := withAuth(&tokens, func(at string) (api.Project, error) {
+			return api.FetchProject(at, args[0])
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch project: %w", err)
+		}
+
+		if !yes {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Delete project %s (%s) and all its recommendations? This cannot be undone. [y/N] ",
+				terminalSafeInline(project.Name), terminalSafeInline(project.ID))
+			answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "y", "yes":
+			default:
+				return fmt.Errorf("aborted; nothing was deleted (pass --yes to skip this prompt)")
+			}
+		}
+
+		if _, err := withAuth(&tokens, func(at string) (struct{}, error) {
+			return struct{}{}, api.DeleteProject(at, project.ID)
+		}); err != nil {
+			return projectWriteError("delete", err)
+		}
+
+		if jsonOut {
+			return writeJSON(cmd.OutOrStdout(), projectDeleteResponse{ID: project.ID, Deleted: true})
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Deleted project %s (%s)\n", terminalSafeInline(project.Name), terminalSafeInline(project.ID))
+		return nil
+	},
+}
+
+// projectInputFromFlags builds a ProjectInput from the field flags that
+// were set on the command line.
+func projectInputFromFlags(cmd *cobra.Command) (api.ProjectInput, error) {
+	var input api.ProjectInput
+	flags := cmd.Flags()
+	stringField := func(name string) *string {
+		if !flags.Changed(name) {
+			return nil
+		}
+		value, _ := flags.GetString(name)
+		return &value
+	}
+	intField := func(name string) *int {
+		if !flags.Changed(name) {
+			return nil
+		}
+		value, _ := flags.GetInt(name)
+		return &value
+	}
+	listField := func(name string) *[]string {
+		if !flags.Changed(name) {
+			return nil
+		}
+		values, _ := flags.GetStringArray(name)
+		list := []string{}
+		for _, value := range values {
+			if value = strings.TrimSpace(value); value != "" {
+				list = append(list, value)
+			}
+		}
+		return &list
+	}
+
+	input.Name = stringField("name")
+	input.InterestDescription = stringField("interest")
+	input.Mode = stringField("mode")
+	input.Visibility = stringField("visibility")
+	input.EmailFrequency = stringField("email-frequency")
+	input.EmailTime = stringField("email-time")
+	input.MaxCandidates = intField("max-candidates")
+	input.MaxPapersPerDigests = intField("max-papers-per-digest")
+	input.PositiveKeywords = listField("positive-keyword")
+	input.NegativeKeywords = listField("negative-keyword")
+
+	if flags.Changed("interest-file") {
+		if input.InterestDescription != nil {
+			return api.ProjectInput{}, fmt.Errorf("invalid project: use either --interest or --interest-file")
+		}
+		path, _ := flags.GetString("interest-file")
+		text, err := readInterestFile(cmd.InOrStdin(), path)
+		if err != nil {
+			return api.ProjectInput{}, err
+		}
+		input.InterestDescription = &text
+	}
+	if values := listField("source"); values != nil {
+		ids := make([]int, 0, len(*values))
+		for _, value := range *values {
+			id, err := strconv.Atoi(value)
+			if err != nil || id < 1 {
+				return api.ProjectInput{}, fmt.Errorf("invalid project: --source %q must be a source ID", value)
+			}
+			ids = append(ids, id)
+		}
+		input.SourceIDs = &ids
+	}
+	if values := listField("category"); values != nil {
+		categories := make([]api.ProjectCategoryInput, 0, len(*values))
+		for _, value := range *values {
+			category, err := parseCategoryFlag(value)
+			if err != nil {
+				return api.ProjectInput{}, err
+			}
+			categories = append(categories, category)
+		}
+		input.Categories = &categories
+	}
+	return input, nil
+}
+
+func readInterestFile(stdin io.Reader, path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read interest description: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// parseCategoryFlag reads ID or ID:WEIGHT. The weight defaults to 1.
+func parseCategoryFlag(value string) (api.ProjectCategoryInput, error) {
+	idText, weightText, hasWeight := strings.Cut(value, ":")
+	id, err := strconv.Atoi(strings.TrimSpace(idText))
+	if err != nil || id < 1 {
+		return api.ProjectCategoryInput{}, fmt.Errorf("invalid project: --category %q must be a category ID, optionally followed by :WEIGHT", value)
+	}
+	category := api.ProjectCategoryInput{ID: id, Weight: 1}
+	if hasWeight {
+		weight, err := strconv.ParseFloat(strings.TrimSpace(weightText), 64)
+		if err != nil || weight < 0 {
+			return api.ProjectCategoryInput{}, fmt.Errorf("invalid project: --category %q has an invalid weight", value)
+		}
+		category.Weight = weight
+	}
+	return category, nil
+}
+
+// projectWriteError renders server validation errors one field per line,
+// naming the flag that sets each field.
+func projectWriteError(action string, err error) error {
+	return projectFieldError(action, err, func(field string) string {
+		top, _, _ := strings.Cut(field, ".")
+		if flag, ok := projectFieldFlags[top]; ok {
+			return field + " (--" + flag + ")"
+		}
+		return field
+	})
+}
+
+// projectFieldError renders server validation errors one field per line,
+// with each field name passed through label.
+func projectFieldError(action string, err error, label func(field string) string) error {
+	var apiErr *api.APIError
+	if !errors.As(err, &apiErr) || len(apiErr.FieldErrors) == 0 {
+		return fmt.Errorf("failed to %s project: %w", action, err)
+	}
+	lines := make([]string, len(apiErr.FieldErrors))
+	for i, fieldErr := range apiErr.FieldErrors {
+		lines[i] = "  " + terminalSafeInline(label(fieldErr.Field)) + ": " + terminalSafeInline(fieldErr.Message)
+	}
+	return fmt.Errorf("failed to %s project:\n%s", action, strings.Join(lines, "\n"))
+}
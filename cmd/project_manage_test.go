@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newProjectManageTestCommand(t *testing.T, args []string, stdin string) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	addProjectFieldFlags(cmd)
+	cmd.Flags().Bool("json", false, "")
+	cmd.Flags().BoolP("yes", "y", false, "")
+	if err := cmd.Flags().Parse(args); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(io.Discard)
+	cmd.SetIn(strings.NewReader(stdin))
+	return cmd, &stdout
+}
+
+func TestProjectUpdateSendsOnlyChangedFields(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/api/projects/proj-1" {
+			t.Fatalf("request = %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"id":"proj-1","name":"Graph Learning"}`))
+	}))
+	defer server.Close()
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+
+	cmd, stdout := newProjectManageTestCommand(t, []string{
+		"--email-time", "07:30",
+		"--max-candidates", "0",
+		"--negative-keyword", "",
+		"--category", "12", "--category", "15:0.5",
+		"--interest-file", "-",
+	}, "  Graph neural networks\n")
+	if err := projectUpdateCmd.RunE(cmd, []string{"proj-1"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+
+	data, _ := json.Marshal(body)
+	want := `{"categories":[{"id":12,"weight":1},{"id":15,"weight":0.5}],"email_time":"07:30","interest_description":"Graph neural networks","max_candidates":0,"negative_keywords":[]}`
+	if string(data) != want {
+		t.Fatalf("body = %s\nwant   %s", data, want)
+	}
+	if stdout.String() != "Updated project Graph Learning (proj-1)\n" {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+
+	cmd, _ = newProjectManageTestCommand(t, nil, "")
+	if err := projectUpdateCmd.RunE(cmd, []string{"proj-1"}); err == nil || !strings.Contains(err.Error(), "nothing to update") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestProjectCreateRendersFieldErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/projects" {
+			t.Fatalf("request = %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"detail":[
+			{"loc":["body","email_time"],"msg":"must be HH:MM","type":"value_error"},
+			{"loc":["body","categories",0,"weight"],"msg":"must be at most 1","type":"value_error"}
+		]}`))
+	}))
+	defer server.Close()
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+
+	cmd, _ := newProjectManageTestCommand(t, []string{"--name", "Graph", "--interest", "GNNs", "--email-time", "7am", "--category", "3:2"}, "")
+	err := projectCreateCmd.RunE(cmd, nil)
+	want := "failed to create project:\n  email_time (--email-time): must be HH:MM\n  categories.0.weight (--category): must be at most 1"
+	if err == nil || err.Error() != want {
+		t.Fatalf("err = %v", err)
+	}
+
+	cmd, _ = newProjectManageTestCommand(t, []string{"--name", "Graph"}, "")
+	if err := projectCreateCmd.RunE(cmd, nil); err == nil || !strings.Contains(err.Error(), "--interest or --interest-file is required") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestProjectDeleteRequiresConfirmation(t *testing.T) {
+	var deleted int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"id":"proj-1","name":"Old Project"}`))
+		case http.MethodDelete:
+			deleted++
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected %s", r.Method)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+
+	cmd, _ := newProjectManageTestCommand(t, nil, "n\n")
+	if err := projectDeleteCmd.RunE(cmd, []string{"proj-1"}); err == nil || !strings.Contains(err.Error(), "aborted") || deleted != 0 {
+		t.Fatalf("err = %v, deleted = %d", err, deleted)
+	}
+
+	cmd, stdout := newProjectManageTestCommand(t, nil, "y\n")
+	if err := projectDeleteCmd.RunE(cmd, []string{"proj-1"}); err != nil || deleted != 1 {
+		t.Fatalf("err = %v, deleted = %d", err, deleted)
+	}
+	if stdout.String() != "Deleted project Old Project (proj-1)\n" {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+
+	cmd, _ = newProjectManageTestCommand(t, []string{"--yes"}, "")
+	if err := projectDeleteCmd.RunE(cmd, []string{"proj-1"}); err != nil || deleted != 2 {
+		t.Fatalf("err = %v, deleted = %d", err, deleted)
+	}
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/layout"
+	"github.com/paperzilla/pz/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+const (
+	projectStatsBarWidth = 24
+	// projectStatsMaxWeeks bounds the sparkline when the output width is
+	// unknown.
+	projectStatsMaxWeeks = 52
+)
+
+var statsNow = time.Now
+
+func init() {
+	projectStatsCmd.Flags().StringP("since", "s", "", "Only papers ready after this date")
+	projectCmd.AddCommand(projectStatsCmd)
+}
+
+type projectStatsResult struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Since string `json:"since,omitempty"`
+	stats.Report
+}
+
+var projectStatsCmd = &cobra.Command{
+	Use:   "stats <project-id>",
+	Short: "Show how healthy a project's matching is",
+	Long: "Page through a project's feed and summarize it: items per week, the must-read\n" +
+		"ratio, relevance scores, feedback, top venues, sources and first authors, and\n" +
+		"how long papers take from publication to the feed.",
+	Example: `  pz project stats <project-id>
+  pz project stats <project-id> --since 2025-06-01
+  pz project stats <project-id> --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		since, _ := cmd.Flags().GetString("since")
+		jsonOut, _ := cmd.Flags().GetBool("json")
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+		project, err := withAuth(&tokens, func(at string) (api.Project, error) {
+			return api.FetchProject(at, args[0])
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch project: %w", err)
+		}
+		items, err := fetchWholeFeed(tokenAuth(&tokens), project.ID, api.FeedOptions{Since: since})
+		if err != nil {
+			return fmt.Errorf("failed to fetch feed: %w", err)
+		}
+
+		result := projectStatsResult{ID: project.ID, Name: project.Name, Since: since, Report: stats.Compute(items, statsNow())}
+		if jsonOut {
+			return writeJSON(cmd.OutOrStdout(), result)
+		}
+		return writePaged(cmd, func(out io.Writer) error {
+			writeProjectStats(out, result, outputWidth(out))
+			return nil
+		})
+	},
+}
+
+func writeProjectStats(out io.Writer, result projectStatsResult, width int) {
+	report := result.Report
+	header := fmt.Sprintf("%s (%s) — %d %s", terminalSafeInline(result.Name), terminalSafeInline(result.ID),
+		report.Items, plural(report.Items, "item", "items"))
+	if result.Since != "" {
+		header += " since " + terminalSafeInline(result.Since)
+	}
+	fmt.Fprintln(out, header)
+	if report.Items == 0 {
+		fmt.Fprintln(out, "\nNo recommendations to summarize yet.")
+		return
+	}
+
+	weeks := report.Weeks
+	maxWeeks := projectStatsMaxWeeks
+	if width > 0 {
+		maxWeeks = max(width-20, 8)
+	}
+	if len(weeks) > maxWeeks {
+		weeks = weeks[len(weeks)-maxWeeks:]
+	}
+	if len(weeks) > 0 {
+		counts := make([]int, len(weeks))
+		total := 0
+		for i, week := range weeks {
+			counts[i] = week.Count
+			total += week.Count
+		}
+		fmt.Fprintf(out, "\nItems per week   %s\n", sparkline(counts))
+		fmt.Fprintf(out, "                 %d weeks from %s, %.1f a week, %d this week\n",
+			len(weeks), weeks[0].Start, float64(total)/float64(len(weeks)), counts[len(counts)-1])
+	}
+	fmt.Fprintf(out, "Must-read        %d of %d (%.0f%%), %d related\n",
+		report.MustRead, report.Items, report.MustReadRatio*100, report.Related)
+	if report.Latency != nil {
+		fmt.Fprintf(out, "Median latency   %.1f days from publication to feed (%d %s)\n",
+			report.Latency.MedianDays, report.Latency.Samples, plural(report.Latency.Samples, "paper", "papers"))
+	}
+
+	relevance := make([]stats.Count, len(report.Relevance))
+	for i, bucket := range report.Relevance {
+		relevance[len(relevance)-1-i] = stats.Count{Name: fmt.Sprintf("%.1f–%.1f", bucket.Min, bucket.Max), Count: bucket.Count}
+	}
+	writeStatsBars(out, "Relevance", relevance)
+
+	feedback := []stats.Count{
+		{Name: "upvote", Count: report.Feedback.Upvote},
+		{Name: "star", Count: report.Feedback.Star},
+		{Name: "downvote", Count: report.Feedback.Downvote},
+	}
+	for _, reason := range report.Feedback.Reasons {
+		feedback = append(feedback, stats.Count{Name: "  " + reason.Name, Count: reason.Count})
+	}
+	feedback = append(feedback, stats.Count{Name: "none", Count: report.Feedback.None})
+	writeStatsBars(out, "Feedback", feedback)
+
+	writeStatsBars(out, "Top venues", report.TopVenues)
+	writeStatsBars(out, "Top sources", report.TopSources)
+	writeStatsBars(out, "Top first authors", report.TopAuthors)
+}
+
+// writeStatsBars prints a titled bar chart, scaled to the largest count.
+// Sections without entries are skipped.
+func writeStatsBars(out io.Writer, title string, counts []stats.Count) {
+	if len(counts) == 0 {
+		return
+	}
+	labelWidth, largest := 0, 0
+	for _, count := range counts {
+		labelWidth = max(labelWidth, layout.Width(terminalSafeInline(count.Name)))
+		largest = max(largest, count.Count)
+	}
+	labelWidth = min(labelWidth, 28)
+
+	fmt.Fprintf(out, "\n%s\n", title)
+	for _, count := range counts {
+		label := layout.PadRight(layout.Truncate(terminalSafeInline(count.Name), labelWidth), labelWidth)
+		bar := ""
+		if largest > 0 && count.Count > 0 {
+			bar = strings.Repeat("█", max(1, count.Count*projectStatsBarWidth/largest))
+		}
+		fmt.Fprintf(out, "  %s  %s %d\n", label, layout.PadRight(bar, projectStatsBarWidth), count.Count)
+	}
+}
+
+// sparkline draws one block per value, scaled to the largest; zero is the
+// lowest block so gaps stay visible.
+func sparkline(values []int) string {
+	levels := []rune("▁▂▃▄▅▆▇█")
+	largest := 0
+	for _, value := range values {
+		largest = max(largest, value)
+	}
+	var line strings.Builder
+	for _, value := range values {
+		level := 0
+		if largest > 0 && value > 0 {
+			level = max(1, value*(len(levels)-1)/largest)
+		}
+		line.WriteRune(levels[level])
+	}
+	return line.String()
+}
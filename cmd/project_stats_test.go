@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newProjectStatsTestAPI(t *testing.T, offsets *[]string) {
+	t.Helper()
+	newTestAPI(t, testRoutes{
+		"/api/projects/proj-1": respondWith(projectDocTestProject),
+		"/api/projects/proj-1/feed": func(w http.ResponseWriter, r *http.Request) {
+			*offsets = append(*offsets, r.URL.Query().Get("offset"))
+			if r.URL.Query().Get("since") != "2025-09-01" {
+				t.Errorf("since = %q", r.URL.Query().Get("since"))
+			}
+			item := `{"id":"pp-%d","relevance_class":%d,"relevance_score":%g,"ready_at":"2025-09-%02dT10:00:00Z",
+				"feedback":%s,"paper":{"published_date":"2025-09-01","venue_name":"NeurIPS","authors":[{"name":"Ada Lovelace"}]}}`
+			if r.URL.Query().Get("offset") == "" {
+				fmt.Fprintf(w, `{"items":[%s,%s],"total":3}`,
+					fmt.Sprintf(item, 1, 2, 0.9, 2, `{"vote":"star"}`),
+					fmt.Sprintf(item, 2, 1, 0.3, 4, `{"vote":"downvote","downvote_reason":"not_relevant"}`))
+				return
+			}
+			fmt.Fprintf(w, `{"items":[%s],"total":3}`, fmt.Sprintf(item, 3, 2, 0.95, 16, "null"))
+		},
+	})
+
+	previous := statsNow
+	statsNow = func() time.Time { return time.Date(2025, 9, 24, 0, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { statsNow = previous })
+}
+
+func projectStatsTestFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("since", "s", "", "")
+	cmd.Flags().Bool("json", false, "")
+}
+
+func TestProjectStatsPagesThroughTheFeed(t *testing.T) {
+	var offsets []string
+	newProjectStatsTestAPI(t, &offsets)
+
+	cmd, stdout, _ := newTestCommand(t, projectStatsTestFlags, "--since", "2025-09-01", "--json")
+	if err := projectStatsCmd.RunE(cmd, []string{"proj-1"}); err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if strings.Join(offsets, ",") != ",2" {
+		t.Fatalf("offsets = %q", offsets)
+	}
+	var result struct {
+		ID       string  `json:"id"`
+		Since    string  `json:"since"`
+		Items    int     `json:"items"`
+		MustRead int     `json:"must_read"`
+		Ratio    float64 `json:"must_read_ratio"`
+		Weeks    []struct {
+			Start string `json:"start"`
+			Count int    `json:"count"`
+		} `json:"weeks"`
+		Feedback struct {
+			Downvote int `json:"downvote"`
+			Reasons  []struct {
+				Name string `json:"name"`
+			} `json:"downvote_reasons"`
+		} `json:"feedback"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("json: %v\n%s", err, stdout.String())
+	}
+	if result.ID != "proj-1" || result.Since != "2025-09-01" || result.Items != 3 || result.MustRead != 2 || len(result.Weeks) != 4 {
+		t.Fatalf("result = %+v", result)
+	}
+	if result.Feedback.Downvote != 1 || len(result.Feedback.Reasons) != 1 || result.Feedback.Reasons[0].Name != "not_relevant" {
+		t.Fatalf("feedback = %+v", result.Feedback)
+	}
+}
+
+func TestProjectStatsRendersCharts(t *testing.T) {
+	var offsets []string
+	newProjectStatsTestAPI(t, &offsets)
+
+	cmd, stdout, _ := newTestCommand(t, projectStatsTestFlags, "--since", "2025-09-01")
+	if err := projectStatsCmd.RunE(cmd, []string{"proj-1"}); err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	for _, want := range []string{
+		"Graph Learning (proj-1) — 3 items since 2025-09-01\n",
+		"Items per week   █▁▄▁\n",
+		"Must-read        2 of 3 (67%), 1 related\n",
+		"Median latency   3.4 days from publication to feed (3 papers)\n",
+		"  0.9–1.0  ████████████████████████ 2\n",
+		"  downvote        ████████████████████████ 1\n",
+		"    not_relevant  ████████████████████████ 1\n  none            ████████████████████████ 1\n",
+		"Top first authors\n  Ada Lovelace  ████████████████████████ 3\n",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("missing %q in:\n%s", want, stdout.String())
+		}
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := sparkline([]int{0, 1, 7, 3}); got != "▁▂█▄" {
+		t.Fatalf("sparkline = %q", got)
+	}
+	if got := sparkline([]int{0, 0}); got != "▁▁" {
+		t.Fatalf("sparkline = %q", got)
+	}
+}
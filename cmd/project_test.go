@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/layout"
 	"github.com/spf13/cobra"
 )
 
@@ -83,6 +86,47 @@ func TestProjectListCommandJSON(t *testing.T) {
 	}
 }
 
+func TestProjectListFitsMultiByteNamesToTerminalWidth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"id":"proj-1","name":"Αλγόριθμοι μηχανικής μάθησης για ανάκτηση","mode":"auto","visibility":"private","created_at":"2025-01-01T00:00:00Z"},
+			{"id":"proj-2","name":"日本語の論文プロジェクト","mode":"manual","visibility":"public","created_at":"2025-02-01T00:00:00Z"}
+		]`))
+	}))
+	defer server.Close()
+
+	t.Setenv("PZ_API_URL", server.URL)
+	t.Setenv("COLUMNS", "60")
+	t.Setenv("PZ_PAGER", "cat")
+	writeTestTokens(t)
+
+	// A page buffer without a terminal stands in for a terminal whose size
+	// cannot be read, so the width comes from $COLUMNS.
+	cmd, _, _ := newProjectTestCommand(false)
+	stdout := &pageBuffer{}
+	cmd.SetOut(stdout)
+	if err := projectListCmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+
+	output := stdout.String()
+	if !utf8.ValidString(output) {
+		t.Fatalf("output is not valid UTF-8: %q", output)
+	}
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "ID") {
+		t.Fatalf("output = %q", output)
+	}
+	for _, line := range lines {
+		if width := layout.Width(line); width > 60 {
+			t.Fatalf("line %q is %d cells wide, want <= 60", line, width)
+		}
+	}
+	if !strings.Contains(output, "Αλγόριθμοι") || !strings.Contains(output, "...") {
+		t.Fatalf("long name was not truncated on a grapheme boundary: %q", output)
+	}
+}
+
 func TestProjectCommandJSON(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/projects/proj-1" {
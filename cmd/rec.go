@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/paperzilla/pz/internal/api"
 	"github.com/spf13/cobra"
@@ -12,6 +13,7 @@ import (
 func init() {
 	recCmd.Flags().BoolP("json", "j", false, "Output as JSON")
 	recCmd.Flags().Bool("markdown", false, "Print raw markdown")
+	recCmd.Flags().Bool("render", false, "Render markdown for the terminal (default for --markdown when stdout is a terminal)")
 }
 
 var recCmd = &cobra.Command{
@@ -25,7 +27,7 @@ var recCmd = &cobra.Command{
 		}
 
 		jsonOut, _ := cmd.Flags().GetBool("json")
-		markdownOut, _ := cmd.Flags().GetBool("markdown")
+		markdownOut := markdownRequested(cmd)
 		if jsonOut && markdownOut {
 			return fmt.Errorf("--json and --markdown cannot be used together")
 		}
@@ -43,8 +45,7 @@ var recCmd = &cobra.Command{
 				}
 				return fmt.Errorf("failed to fetch recommendation markdown: %w", err)
 			}
-			fmt.Fprint(cmd.OutOrStdout(), markdown)
-			return nil
+			return writePaperMarkdown(cmd, markdown)
 		}
 
 		projectPaper, err := withAuth(&tokens, func(at string) (api.ProjectPaper, error) {
@@ -63,7 +64,14 @@ var recCmd = &cobra.Command{
 			return nil
 		}
 
-		writeProjectPaper(cmd.OutOrStdout(), projectPaper)
+		err = writePaged(cmd, func(out io.Writer) error {
+			writeProjectPaper(out, projectPaper)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		loadSeenTracker(tokens, cmd.ErrOrStderr()).markShown(cmd.OutOrStdout(), "", projectPaper.ID)
 		return nil
 	},
 }
@@ -68,6 +68,51 @@ func TestRecCommandMarkdownQueued(t *testing.T) {
 	}
 }
 
+func TestRecCommandMarkdownStaysRawWhenNotATerminal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("# Title\n\nSome **bold** text.\n"))
+	}))
+	defer server.Close()
+
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+
+	cmd, stdout, _ := newRecTestCommand(false, true)
+	cmd.Flags().Bool("render", false, "")
+	if err := recCmd.RunE(cmd, []string{"feedbeef"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+
+	if stdout.String() != "# Title\n\nSome **bold** text.\n" {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+}
+
+func TestRecCommandRenderImpliesMarkdownAndEscapesControls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/project-papers/feedbeef/markdown" {
+			t.Fatalf("path = %s, want /api/project-papers/feedbeef/markdown", r.URL.Path)
+		}
+		_, _ = w.Write([]byte("# Title\n\nSome **bold** text with $\\alpha$ and \x1b]52;c;payload\a.\n"))
+	}))
+	defer server.Close()
+
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+
+	cmd, stdout, _ := newRecTestCommand(false, false)
+	cmd.Flags().Bool("render", false, "")
+	_ = cmd.Flags().Set("render", "true")
+	if err := recCmd.RunE(cmd, []string{"feedbeef"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+
+	want := "Title\n=====\n\nSome bold text with α and \\x1b]52;c;payload\\x07.\n"
+	if stdout.String() != want {
+		t.Fatalf("stdout = %q, want %q", stdout.String(), want)
+	}
+}
+
 func newRecTestCommand(jsonOut, markdown bool) (*cobra.Command, *bytes.Buffer, *bytes.Buffer) {
 	cmd := &cobra.Command{}
 	cmd.Flags().Bool("json", jsonOut, "")
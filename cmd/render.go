@@ -6,10 +6,12 @@ import (
 	"strings"
 
 	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/authors"
 )
 
 func writeCanonicalPaper(w io.Writer, paper api.Paper) {
-	fmt.Fprintf(w, "Title:           %s\n", displayValue(paper.Title))
+	link := linkerFor(w)
+	fmt.Fprintf(w, "Title:           %s\n", link(displayValue(paper.Title), paper.URL))
 	fmt.Fprintf(w, "ID:              %s\n", displayValue(paper.ID))
 	fmt.Fprintf(w, "Short ID:        %s\n", displayValue(paper.ShortID))
 	fmt.Fprintf(w, "Slug:            %s\n", displayValue(paper.Slug))
@@ -18,9 +20,9 @@ func writeCanonicalPaper(w io.Writer, paper api.Paper) {
 	}
 	fmt.Fprintf(w, "Published:       %s\n", formatTime(paper.PublishedDate))
 	fmt.Fprintf(w, "Authors:         %s\n", displayValue(authorNames(paper.Authors)))
-	fmt.Fprintf(w, "URL:             %s\n", displayValue(paper.URL))
-	fmt.Fprintf(w, "PDF URL:         %s\n", displayValue(paper.PdfURL))
-	fmt.Fprintf(w, "DOI:             %s\n", displayValue(paper.DOI))
+	fmt.Fprintf(w, "URL:             %s\n", link(displayValue(paper.URL), paper.URL))
+	fmt.Fprintf(w, "PDF URL:         %s\n", link(displayValue(paper.PdfURL), paper.PdfURL))
+	fmt.Fprintf(w, "DOI:             %s\n", link(displayValue(paper.DOI), doiURL(paper.DOI)))
 
 	if strings.TrimSpace(paper.Abstract) != "" {
 		fmt.Fprintf(w, "\nAbstract:\n  %s\n", terminalSafeBlock(paper.Abstract, "  "))
@@ -28,8 +30,9 @@ func writeCanonicalPaper(w io.Writer, paper api.Paper) {
 }
 
 func writeProjectPaper(w io.Writer, projectPaper api.ProjectPaper) {
-	fmt.Fprintf(w, "Title:             %s\n", displayValue(projectPaper.PaperTitle))
-	fmt.Fprintf(w, "Recommendation ID: %s\n", displayValue(projectPaper.ID))
+	link := linkerFor(w)
+	fmt.Fprintf(w, "Title:             %s\n", link(displayValue(projectPaper.PaperTitle), projectPaper.Paper.URL))
+	fmt.Fprintf(w, "Recommendation ID: %s\n", link(displayValue(projectPaper.ID), recommendationWebURL(projectPaper.ID)))
 	fmt.Fprintf(w, "Short ID:          %s\n", displayValue(projectPaper.ShortID))
 	fmt.Fprintf(w, "Relevance:         %s\n", formatRelevance(projectPaper.RelevanceClass, projectPaper.RelevanceScore))
 	fmt.Fprintf(w, "Feedback:          %s\n", terminalSafeInline(formatFeedback(projectPaper.Feedback)))
@@ -51,9 +54,9 @@ func writeProjectPaper(w io.Writer, projectPaper api.ProjectPaper) {
 	}
 	fmt.Fprintf(w, "  Published:       %s\n", formatTime(paper.PublishedDate))
 	fmt.Fprintf(w, "  Authors:         %s\n", displayValue(authorNames(paper.Authors)))
-	fmt.Fprintf(w, "  URL:             %s\n", displayValue(paper.URL))
-	fmt.Fprintf(w, "  PDF URL:         %s\n", displayValue(paper.PdfURL))
-	fmt.Fprintf(w, "  DOI:             %s\n", displayValue(paper.DOI))
+	fmt.Fprintf(w, "  URL:             %s\n", link(displayValue(paper.URL), paper.URL))
+	fmt.Fprintf(w, "  PDF URL:         %s\n", link(displayValue(paper.PdfURL), paper.PdfURL))
+	fmt.Fprintf(w, "  DOI:             %s\n", link(displayValue(paper.DOI), doiURL(paper.DOI)))
 
 	if strings.TrimSpace(paper.Abstract) != "" {
 		fmt.Fprintf(w, "\nAbstract:\n  %s\n", terminalSafeBlock(paper.Abstract, "  "))
@@ -142,23 +145,17 @@ func formatRelevance(class int, score float64) string {
 	return fmt.Sprintf("%s (%d%%)", label, int(score*100))
 }
 
-func firstAuthorSurname(authors []api.Author) string {
-	if len(authors) == 0 {
-		return "Unknown"
-	}
-
-	name := strings.TrimSpace(authors[0].Name)
-	if name == "" {
+func firstAuthorSurname(authorList []api.Author) string {
+	if len(authorList) == 0 {
 		return "Unknown"
 	}
 
-	parts := strings.Fields(name)
-	if len(parts) == 0 {
+	surname := authors.Parse(authorList[0].Name).Surname()
+	if surname == "" {
 		return "Unknown"
 	}
 
-	surname := parts[len(parts)-1]
-	if len(authors) > 1 {
+	if len(authorList) > 1 {
 		return surname + " et al."
 	}
 	return surname
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/layout"
 )
 
 func TestWriteCanonicalPaperUsesDOIReferenceLabelForImportedPaper(t *testing.T) {
@@ -97,3 +99,44 @@ func assertNoUnsafeSourceNamespaces(t *testing.T, output string) {
 		}
 	}
 }
+
+func TestFirstAuthorSurnameUsesParsedFamilyName(t *testing.T) {
+	tests := []struct {
+		authors []api.Author
+		want    string
+	}{
+		{authors: nil, want: "Unknown"},
+		{authors: []api.Author{{Name: "  "}}, want: "Unknown"},
+		{authors: []api.Author{{Name: "Ludwig van Beethoven Jr."}}, want: "van Beethoven"},
+		{authors: []api.Author{{Name: "Doe, Jane"}, {Name: "John Roe"}}, want: "Doe et al."},
+		{authors: []api.Author{{Name: "Li Fei-Fei"}}, want: "Li"},
+		{authors: []api.Author{{Name: "山田 太郎"}}, want: "山田"},
+	}
+
+	for _, tt := range tests {
+		if got := firstAuthorSurname(tt.authors); got != tt.want {
+			t.Errorf("firstAuthorSurname(%v) = %q, want %q", tt.authors, got, tt.want)
+		}
+	}
+}
+
+func TestWriteProjectPaperFeedListTruncatesOnGraphemeBoundaries(t *testing.T) {
+	t.Setenv("COLUMNS", "40")
+
+	var out pageBuffer
+	writeProjectPaperFeedList(&out, []api.ProjectPaper{{
+		PaperTitle:     "Ελληνικά και 日本語 στον τίτλο μιας πολύ μεγάλης εργασίας",
+		RelevanceClass: 2,
+	}}, nil)
+
+	titleLine := strings.SplitN(out.String(), "\n", 2)[0]
+	if !utf8.ValidString(titleLine) {
+		t.Fatalf("title line is not valid UTF-8: %q", titleLine)
+	}
+	if width := layout.Width(titleLine); width > 40 {
+		t.Fatalf("title line %q is %d cells wide, want <= 40", titleLine, width)
+	}
+	if !strings.HasSuffix(titleLine, "...") {
+		t.Fatalf("title line was not truncated: %q", titleLine)
+	}
+}
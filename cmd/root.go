@@ -27,22 +27,47 @@ var rootCmd = &cobra.Command{
   pz project list --json
   pz project <id>
   pz project <id> --json
+  pz project create --name "Graph Learning" --interest "Graph neural networks"
+  pz project update <id> --email-frequency daily
+  pz project export <id> > project.yaml
+  pz project apply -f project.yaml --plan
+  pz project edit <id>
+  pz project keywords <id> add --negative survey
+  pz categories list --source arxiv --search cs.LG
+  pz project categories <id> add cs.LG:0.5
+  pz project stats <id> --since 2025-06-01
   pz paper <paper-id>
   pz paper <paper-id> --project <project-id>
   pz rec <project-paper-id>
+  pz open <project-paper-id> --pdf
   pz feedback <project-paper-id> upvote
   pz feedback <project-paper-id> upvote --json
+  pz feedback apply -f votes.jsonl
+  pz feedback undo --last 5
+  pz feedback list <project-id> --vote star --csv
   pz feed <id>
   pz feed <id> --must-read --limit 5 --offset 20
   pz feed search --project-id <id> --query "latent retrieval"
   pz feed <id> --json
-  pz feed <id> --atom`,
+  pz feed <id> --atom
+  pz feed <id> --new
+  pz feed --all-projects --new
+  pz feed download <id> --dir papers/
+  pz download <paper-id>...
+  pz sync
+  pz search --local "graph transformers"
+  pz watch --must-read
+  pz notify test
+  pz digest send <project-id> --to team@lab.example
+  pz tui <project-id>
+  pz triage <project-id> --must-read`,
 }
 
 func init() {
 	api.SetClientVersion(Version)
 	cobra.EnableCommandSorting = false
-	rootCmd.AddCommand(loginCmd, updateCmd, projectCmd, paperCmd, recCmd, feedbackCmd, feedCmd)
+	rootCmd.PersistentFlags().Bool("no-pager", false, "Do not pipe long output into a pager")
+	rootCmd.AddCommand(loginCmd, updateCmd, projectCmd, paperCmd, recCmd, feedbackCmd, feedCmd, tuiCmd, triageCmd, openCmd, downloadCmd, syncCmd, searchCmd, watchCmd, notifyCmd, digestCmd, sourcesCmd, categoriesCmd)
 }
 
 func Execute() {
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/paperzilla/pz/internal/layout"
+	"github.com/paperzilla/pz/internal/library"
+	"github.com/paperzilla/pz/internal/search"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultLocalSearchLimit = 20
+	defaultSnippetWidth     = 160
+)
+
+func init() {
+	searchCmd.Flags().Bool("local", false, "Search the library written by pz sync instead of the server")
+	searchCmd.Flags().String("project", "", "Only search this project")
+	searchCmd.Flags().IntP("limit", "n", defaultLocalSearchLimit, "Limit number of results")
+	searchCmd.Flags().BoolP("json", "j", false, "Output as JSON")
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search your synced papers offline",
+	Long: "Search titles, summaries, notes, abstracts and synced markdown across all\n" +
+		"projects in the local library (see pz sync). Results are ranked with BM25.\n\n" +
+		"Every word must match. Use \"quotes\" for phrases, and narrow results with\n" +
+		"author:name, venue:name and year:2024 or year:2020-2023.\n\n" +
+		"For server-side search of one project's full feed, use pz feed search.",
+	Example: `  pz search --local "graph transformers"
+  pz search --local '"message passing" author:hinton year:2020-'
+  pz search --local diffusion --project <project-id> --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		local, _ := cmd.Flags().GetBool("local")
+		projectID, _ := cmd.Flags().GetString("project")
+		limit, _ := cmd.Flags().GetInt("limit")
+		jsonOut, _ := cmd.Flags().GetBool("json")
+
+		if !local {
+			return fmt.Errorf("server-side search needs a project: use pz feed search --project-id <id> --query <text>, or pass --local to search your synced library")
+		}
+		if limit < 1 {
+			return fmt.Errorf("invalid search request: limit must be at least 1")
+		}
+
+		query, err := search.ParseQuery(strings.Join(args, " "))
+		if err != nil {
+			return fmt.Errorf("invalid search request: %w", err)
+		}
+		if query.Empty() {
+			return fmt.Errorf("invalid search request: query is empty")
+		}
+
+		lib, err := library.Open(config.LibraryPath())
+		if err != nil {
+			return err
+		}
+		defer lib.Close()
+
+		if states, err := lib.Projects(); err != nil {
+			return err
+		} else if len(states) == 0 {
+			return fmt.Errorf("the local library is empty; run pz sync first")
+		}
+
+		hits, err := lib.Search(query, library.SearchOptions{ProjectID: projectID, Limit: limit})
+		if err != nil {
+			return err
+		}
+
+		if jsonOut {
+			return writeLocalSearchJSON(cmd.OutOrStdout(), query, hits)
+		}
+		return writePaged(cmd, func(out io.Writer) error {
+			writeLocalSearchResults(out, query, hits)
+			return nil
+		})
+	},
+}
+
+func writeLocalSearchResults(w io.Writer, query search.Query, hits []library.Hit) {
+	if len(hits) == 0 {
+		fmt.Fprintln(w, "No matching papers.")
+		return
+	}
+
+	width := outputWidth(w)
+	link := linkerFor(w)
+	file, ok := terminalFile(w)
+	color := ok && supportsColor(file)
+	snippetWidth := defaultSnippetWidth
+	if width > 0 {
+		snippetWidth = max(width-4, 40)
+	}
+
+	for _, hit := range hits {
+		p := hit.Item
+		prefix := "○ Related"
+		if p.RelevanceClass == 2 {
+			prefix = "★ Must Read"
+		}
+		if marker := feedbackMarker(p.Feedback); marker != "" {
+			prefix += " " + marker
+		}
+		titleWidth := defaultFeedTitleWidth
+		if width > 0 {
+			titleWidth = max(width-layout.Width(prefix)-2, 20)
+		}
+		fmt.Fprintf(w, "%s  %s\n", prefix, link(layout.Truncate(terminalSafeInline(p.PaperTitle), titleWidth), p.Paper.URL))
+		// The linked ID is added after joinDisplayParts, which would escape
+		// the link's control sequences.
+		meta := joinDisplayParts(
+			firstAuthorSurname(p.Paper.Authors),
+			paperListLabel(p.Paper),
+			formatTime(p.Paper.PublishedDate),
+			hit.ProjectName,
+		)
+		fmt.Fprintf(w, "  %s · %s\n", meta, link(terminalSafeInline(p.ID), recommendationWebURL(p.ID)))
+		if snippet := localSearchSnippet(hit, query, snippetWidth); snippet != nil {
+			fmt.Fprintf(w, "  %s\n", renderSnippet(snippet, color))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// localSearchSnippet picks the first text field that contains a query term.
+func localSearchSnippet(hit library.Hit, query search.Query, width int) []search.Segment {
+	terms := query.AllTerms()
+	if len(terms) == 0 {
+		return nil
+	}
+	for _, text := range []string{hit.Item.Summary, hit.Item.PersonalizedNote, hit.Item.Paper.Abstract, hit.Markdown} {
+		if snippet := search.Snippet(text, terms, width); snippet != nil {
+			return snippet
+		}
+	}
+	return nil
+}
+
+func renderSnippet(segments []search.Segment, color bool) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		text := terminalSafeInline(segment.Text)
+		switch {
+		case segment.Match && color:
+			b.WriteString(ansiBold + ansiYellow + text + ansiReset)
+		case segment.Match:
+			b.WriteString("*" + text + "*")
+		default:
+			b.WriteString(text)
+		}
+	}
+	return b.String()
+}
+
+type localSearchHitJSON struct {
+	ProjectID   string           `json:"project_id"`
+	ProjectName string           `json:"project_name"`
+	Score       float64          `json:"score"`
+	Snippet     string           `json:"snippet,omitempty"`
+	Item        api.ProjectPaper `json:"item"`
+}
+
+func writeLocalSearchJSON(out io.Writer, query search.Query, hits []library.Hit) error {
+	results := make([]localSearchHitJSON, 0, len(hits))
+	for _, hit := range hits {
+		var snippet strings.Builder
+		for _, segment := range localSearchSnippet(hit, query, defaultSnippetWidth) {
+			snippet.WriteString(segment.Text)
+		}
+		results = append(results, localSearchHitJSON{
+			ProjectID:   hit.ProjectID,
+			ProjectName: hit.ProjectName,
+			Score:       hit.Score,
+			Snippet:     snippet.String(),
+			Item:        hit.Item,
+		})
+	}
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Fprintln(out, string(data))
+	return nil
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/paperzilla/pz/internal/library"
+	"github.com/spf13/cobra"
+)
+
+type staticLibrarySource struct {
+	items []api.ProjectPaper
+}
+
+func (s staticLibrarySource) Projects() ([]api.Project, error) {
+	return []api.Project{{ID: "proj-1", Name: "Graph Learning"}}, nil
+}
+
+func (s staticLibrarySource) Project(id string) (api.Project, error) {
+	return api.Project{ID: id, Name: "Graph Learning"}, nil
+}
+
+func (s staticLibrarySource) Feed(string, api.FeedOptions) (api.FeedResponse, error) {
+	return api.FeedResponse{Items: s.items, Total: len(s.items)}, nil
+}
+
+func (s staticLibrarySource) Markdown(string) (string, error) {
+	return "", &api.PaperMarkdownPendingError{}
+}
+
+func seedTestLibrary(t *testing.T, items ...api.ProjectPaper) {
+	t.Helper()
+	t.Setenv("PZ_LIBRARY_PATH", filepath.Join(t.TempDir(), "library.db"))
+	lib, err := library.Open(config.LibraryPath())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer lib.Close()
+	if _, err := lib.Sync(staticLibrarySource{items: items}, library.SyncOptions{}); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+}
+
+func runLocalSearch(t *testing.T, args []string, flags ...string) (string, error) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("local", false, "")
+	cmd.Flags().String("project", "", "")
+	cmd.Flags().IntP("limit", "n", defaultLocalSearchLimit, "")
+	cmd.Flags().BoolP("json", "j", false, "")
+	if err := cmd.Flags().Parse(flags); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	err := searchCmd.RunE(cmd, args)
+	return stdout.String(), err
+}
+
+func TestLocalSearchPrintsRankedResultsWithSnippets(t *testing.T) {
+	seedTestLibrary(t,
+		api.ProjectPaper{ID: "pp-1", PaperTitle: "Message passing at scale", RelevanceClass: 2,
+			Summary: "A study of message passing \x1b[31mnetworks on large graphs.",
+			Paper:   api.Paper{Authors: []api.Author{{Name: "Ada Lovelace"}}}},
+		api.ProjectPaper{ID: "pp-2", PaperTitle: "Unrelated", Summary: "Nothing here."},
+	)
+
+	out, err := runLocalSearch(t, []string{`"message passing"`, "graphs"}, "--local")
+	if err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	for _, want := range []string{
+		"★ Must Read  Message passing at scale",
+		"Lovelace",
+		"Graph Learning",
+		"A study of *message* *passing* \\x1b[31mnetworks on large *graphs*.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Unrelated") {
+		t.Fatalf("non-matching paper listed:\n%s", out)
+	}
+}
+
+func TestLocalSearchJSON(t *testing.T) {
+	seedTestLibrary(t, api.ProjectPaper{ID: "pp-1", PaperTitle: "Diffusion models", Paper: api.Paper{PublishedDate: "2024-01-01"}})
+
+	out, err := runLocalSearch(t, []string{"year:2024"}, "--local", "--json")
+	if err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	var hits []localSearchHitJSON
+	if err := json.Unmarshal([]byte(out), &hits); err != nil || len(hits) != 1 || hits[0].Item.ID != "pp-1" || hits[0].ProjectID != "proj-1" {
+		t.Fatalf("hits = %+v, %v\n%s", hits, err, out)
+	}
+}
+
+func TestSearchRequiresLocalAndSyncedLibrary(t *testing.T) {
+	if _, err := runLocalSearch(t, []string{"graphs"}); err == nil || !strings.Contains(err.Error(), "pz feed search") {
+		t.Fatalf("err = %v", err)
+	}
+
+	t.Setenv("PZ_LIBRARY_PATH", filepath.Join(t.TempDir(), "library.db"))
+	if _, err := runLocalSearch(t, []string{"graphs"}, "--local"); err == nil || !strings.Contains(err.Error(), "run pz sync first") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestLocalSearchLinksRecommendationIDs(t *testing.T) {
+	stubHyperlinks(t, true)
+	seedTestLibrary(t, api.ProjectPaper{ID: "pp-1", PaperTitle: "Message passing", Summary: "message passing",
+		Paper: api.Paper{Authors: []api.Author{{Name: "Ada Lovelace"}}}})
+
+	out, err := runLocalSearch(t, []string{"message"}, "--local")
+	if err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	want := "  Lovelace · — · Graph Learning · \x1b]8;;" + recommendationWebURL("pp-1") + "\x1b\\pp-1\x1b]8;;\x1b\\\n"
+	if !strings.Contains(out, want) {
+		t.Fatalf("output missing %q:\n%q", want, out)
+	}
+	if strings.Contains(out, `\x1b`) {
+		t.Fatalf("output contains escaped escape sequences:\n%q", out)
+	}
+}
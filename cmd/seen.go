@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/paperzilla/pz/internal/seen"
+	"github.com/spf13/cobra"
+)
+
+// seenTracker is the seen state of the logged-in account. A tracker whose
+// state could not be read treats everything as seen and never saves, so a
+// damaged file is neither shown as all-new nor overwritten.
+type seenTracker struct {
+	store   *seen.Store
+	account string
+	errOut  io.Writer
+}
+
+func loadSeenTracker(tokens config.Tokens, errOut io.Writer) *seenTracker {
+	store, err := seen.Load(config.SeenStatePath())
+	if err != nil {
+		fmt.Fprintf(errOut, "Warning: %s\n", terminalSafeInline(err.Error()))
+	}
+	return &seenTracker{store: store, account: tokens.Account(), errOut: errOut}
+}
+
+func (t *seenTracker) isNew(id string) bool {
+	return t.store != nil && !t.store.Seen(t.account, id)
+}
+
+// mark records ids as seen. Failing to save only warns, since the
+// command's real work already succeeded.
+func (t *seenTracker) mark(projectID string, ids ...string) int {
+	if t.store == nil {
+		return 0
+	}
+	added := t.store.Mark(t.account, projectID, ids, time.Now())
+	if added > 0 {
+		if err := t.store.Save(); err != nil {
+			fmt.Fprintf(t.errOut, "Warning: %s\n", terminalSafeInline(err.Error()))
+		}
+	}
+	return added
+}
+
+// markShown marks ids as seen when they were printed to a terminal. Output
+// piped to another program or redirected to a file was not necessarily
+// read, so it leaves the seen state alone.
+func (t *seenTracker) markShown(out io.Writer, projectID string, ids ...string) {
+	if _, ok := terminalFile(out); !ok {
+		return
+	}
+	t.mark(projectID, ids...)
+}
+
+const (
+	defaultNewLimit = 50
+	feedPageSize    = 50
+)
+
+// fetchUnseenFeed pages through the feed, newest first, keeping only items
+// the tracker has not seen until opts.Limit are found.
+func fetchUnseenFeed(auth requestAuth, projectID string, opts api.FeedOptions, tracker *seenTracker) (api.FeedResponse, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultNewLimit
+	}
+	feed := api.FeedResponse{Items: []api.ProjectPaper{}}
+	for offset := 0; len(feed.Items) < limit; {
+		page, err := fetchFeedPage(auth, projectID, api.FeedOptions{
+			MustReadOnly: opts.MustReadOnly,
+			Since:        opts.Since,
+			Limit:        feedPageSize,
+			Offset:       offset,
+		})
+		if err != nil {
+			return api.FeedResponse{}, err
+		}
+		for _, item := range page.Items {
+			if tracker.isNew(item.ID) && len(feed.Items) < limit {
+				feed.Items = append(feed.Items, item)
+			}
+		}
+		offset += len(page.Items)
+		if len(page.Items) == 0 || offset >= page.Total {
+			break
+		}
+	}
+	feed.Total = len(feed.Items)
+	return feed, nil
+}
+
+func fetchFeedPage(auth requestAuth, projectID string, opts api.FeedOptions) (api.FeedResponse, error) {
+	var feed api.FeedResponse
+	err := auth(func(at string) error {
+		var err error
+		feed, err = api.FetchFeed(at, projectID, opts)
+		return err
+	})
+	return feed, err
+}
+
+// fetchWholeFeed pages through a project's feed from the start. Only the
+// filters of opts are used.
+func fetchWholeFeed(auth requestAuth, projectID string, opts api.FeedOptions) ([]api.ProjectPaper, error) {
+	items := []api.ProjectPaper{}
+	for offset := 0; ; {
+		page, err := fetchFeedPage(auth, projectID, api.FeedOptions{
+			MustReadOnly: opts.MustReadOnly,
+			Since:        opts.Since,
+			Limit:        feedPageSize,
+			Offset:       offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page.Items...)
+		offset += len(page.Items)
+		if len(page.Items) == 0 || offset >= page.Total {
+			return items, nil
+		}
+	}
+}
+
+func projectPaperIDs(items []api.ProjectPaper) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+func init() {
+	feedMarkSeenCmd.Flags().Bool("all", false, "Mark every paper in the feed as seen")
+}
+
+var feedMarkSeenCmd = &cobra.Command{
+	Use:   "mark-seen <project-id> [project-paper-id...]",
+	Short: "Mark feed papers as seen so they drop out of pz feed --new",
+	Example: `  pz feed mark-seen <project-id> --all
+  pz feed mark-seen <project-id> <project-paper-id> <project-paper-id>`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		projectID, ids := args[0], args[1:]
+		if all == (len(ids) > 0) {
+			return fmt.Errorf("invalid mark-seen request: pass either --all or one or more project paper IDs")
+		}
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+		store, err := seen.Load(config.SeenStatePath())
+		if err != nil {
+			return err
+		}
+
+		if all {
+			for offset := 0; ; {
+				page, err := withAuth(&tokens, func(at string) (api.FeedResponse, error) {
+					return api.FetchFeed(at, projectID, api.FeedOptions{Limit: feedPageSize, Offset: offset})
+				})
+				if err != nil {
+					return fmt.Errorf("failed to fetch feed: %w", err)
+				}
+				ids = append(ids, projectPaperIDs(page.Items)...)
+				offset += len(page.Items)
+				if len(page.Items) == 0 || offset >= page.Total {
+					break
+				}
+			}
+		}
+
+		added := store.Mark(tokens.Account(), projectID, ids, time.Now())
+		if err := store.Save(); err != nil {
+			return err
+		}
+		papers := "papers"
+		if added == 1 {
+			papers = "paper"
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Marked %d %s as seen.\n", added, papers)
+		return nil
+	},
+}
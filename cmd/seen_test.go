@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func seenTestServer(t *testing.T, total int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/projects/proj-1/feed":
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			if limit == 0 {
+				limit = 20
+			}
+			var items []string
+			for i := offset; i < min(offset+limit, total); i++ {
+				items = append(items, fmt.Sprintf(`{"id":"pp-%d","paper_title":"Paper %d","relevance_class":1,"paper":{}}`, i+1, i+1))
+			}
+			fmt.Fprintf(w, `{"items":[%s],"total":%d,"limit":%d,"offset":%d}`, strings.Join(items, ","), total, limit, offset)
+		case "/api/projects/proj-1":
+			_, _ = w.Write([]byte(`{"id":"proj-1","name":"Test Project"}`))
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+}
+
+// runSeenFeed runs pz feed proj-1 as if on a terminal. A page buffer
+// without a terminal counts as one, and PZ_PAGER=cat keeps it unpaged.
+func runSeenFeed(t *testing.T, args ...string) string {
+	t.Helper()
+	t.Setenv("PZ_PAGER", "cat")
+	stdout := &pageBuffer{}
+	runSeenFeedTo(t, stdout, args...)
+	return stdout.String()
+}
+
+func runSeenFeedTo(t *testing.T, stdout io.Writer, args ...string) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.Flags().BoolP("json", "j", false, "")
+	cmd.Flags().Bool("must-read", false, "")
+	cmd.Flags().String("since", "", "")
+	cmd.Flags().Int("limit", 0, "")
+	cmd.Flags().Int("offset", 0, "")
+	cmd.Flags().Bool("atom", false, "")
+	cmd.Flags().Bool("new", false, "")
+	if err := cmd.Flags().Parse(args); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	cmd.SetOut(stdout)
+	if err := feedCmd.RunE(cmd, []string{"proj-1"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+}
+
+func TestFeedMarksShownPapersAndNewSkipsThem(t *testing.T) {
+	server := seenTestServer(t, 3)
+	defer server.Close()
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+
+	if output := runSeenFeed(t, "--limit", "2"); !strings.Contains(output, "○ Related NEW  Paper 1") || !strings.Contains(output, "○ Related NEW  Paper 2") {
+		t.Fatalf("first run = %q", output)
+	}
+	if output := runSeenFeed(t, "--limit", "2"); strings.Contains(output, "NEW") {
+		t.Fatalf("second run should not mark papers as new: %q", output)
+	}
+
+	output := runSeenFeed(t, "--new")
+	if !strings.Contains(output, "Test Project — 1 new papers") || !strings.Contains(output, "Paper 3") || strings.Contains(output, "Paper 1") {
+		t.Fatalf("--new = %q", output)
+	}
+	if output := runSeenFeed(t, "--new"); !strings.Contains(output, "Nothing new since last time.") {
+		t.Fatalf("--new after seeing everything = %q", output)
+	}
+}
+
+func TestFeedJSONDoesNotMarkPapersSeen(t *testing.T) {
+	server := seenTestServer(t, 1)
+	defer server.Close()
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+
+	runSeenFeed(t, "--json")
+	if output := runSeenFeed(t); !strings.Contains(output, "NEW  Paper 1") {
+		t.Fatalf("output = %q", output)
+	}
+}
+
+func TestPipedFeedDoesNotMarkPapersSeen(t *testing.T) {
+	server := seenTestServer(t, 1)
+	defer server.Close()
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+
+	var piped bytes.Buffer
+	runSeenFeedTo(t, &piped)
+	if !strings.Contains(piped.String(), "NEW  Paper 1") {
+		t.Fatalf("piped output = %q", piped.String())
+	}
+	if output := runSeenFeed(t, "--new"); !strings.Contains(output, "Paper 1") {
+		t.Fatalf("--new after piping = %q", output)
+	}
+}
+
+func TestFeedMarkSeen(t *testing.T) {
+	server := seenTestServer(t, 120)
+	defer server.Close()
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+
+	markSeen := func(all bool, args ...string) (string, error) {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("all", all, "")
+		var stdout bytes.Buffer
+		cmd.SetOut(&stdout)
+		err := feedMarkSeenCmd.RunE(cmd, append([]string{"proj-1"}, args...))
+		return stdout.String(), err
+	}
+
+	if _, err := markSeen(false); err == nil {
+		t.Fatal("expected an error without --all or IDs")
+	}
+	if _, err := markSeen(true, "pp-1"); err == nil {
+		t.Fatal("expected an error for --all with IDs")
+	}
+	if output, err := markSeen(false, "pp-1", "pp-2"); err != nil || output != "Marked 2 papers as seen.\n" {
+		t.Fatalf("output = %q, err = %v", output, err)
+	}
+	if output, err := markSeen(true); err != nil || output != "Marked 118 papers as seen.\n" {
+		t.Fatalf("output = %q, err = %v", output, err)
+	}
+	if output := runSeenFeed(t, "--new"); !strings.Contains(output, "Nothing new since last time.") {
+		t.Fatalf("--new = %q", output)
+	}
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/paperzilla/pz/internal/library"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	syncCmd.Flags().Bool("full", false, "Refetch every feed item, refreshing feedback and dropping removed items")
+	syncCmd.Flags().Bool("markdown", false, "Also store full-text markdown so offline search covers it")
+	syncCmd.Flags().BoolP("json", "j", false, "Output as JSON")
+}
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [project-id...]",
+	Short: "Mirror projects, feeds and feedback into a local library",
+	Long: "Mirror projects, feeds and feedback into a local SQLite library.\n\n" +
+		"Syncs are incremental: only papers ready since the last sync of each\n" +
+		"project are fetched. Use --full to refetch everything, which also picks\n" +
+		"up feedback changed on the website and drops papers that left a feed.\n\n" +
+		"The library is stored at ~/.paperzilla/library.db (or $PZ_LIBRARY_PATH).",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		full, _ := cmd.Flags().GetBool("full")
+		markdown, _ := cmd.Flags().GetBool("markdown")
+		jsonOut, _ := cmd.Flags().GetBool("json")
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+
+		lib, err := library.Open(config.LibraryPath())
+		if err != nil {
+			return err
+		}
+		defer lib.Close()
+
+		out := cmd.OutOrStdout()
+		opts := library.SyncOptions{ProjectIDs: args, Full: full, Markdown: markdown}
+		if !jsonOut {
+			opts.Progress = func(result library.ProjectResult) {
+				writeSyncResult(out, result)
+			}
+		}
+
+		results, err := lib.Sync(&librarySyncSource{tokens: &tokens}, opts)
+		if err != nil {
+			return err
+		}
+
+		if jsonOut {
+			return writeSyncJSON(out, lib.Path(), results)
+		}
+		if len(results) == 0 {
+			fmt.Fprintln(out, "No projects to sync.")
+		}
+		fmt.Fprintf(out, "Library: %s\n", terminalSafeInline(lib.Path()))
+		return nil
+	},
+}
+
+// librarySyncSource feeds the library sync from the Paperzilla API.
+type librarySyncSource struct {
+	tokens *config.Tokens
+}
+
+func (s *librarySyncSource) Projects() ([]api.Project, error) {
+	return withAuth(s.tokens, func(at string) ([]api.Project, error) {
+		return api.FetchProjects(at)
+	})
+}
+
+func (s *librarySyncSource) Project(id string) (api.Project, error) {
+	return withAuth(s.tokens, func(at string) (api.Project, error) {
+		return api.FetchProject(at, id)
+	})
+}
+
+func (s *librarySyncSource) Feed(projectID string, opts api.FeedOptions) (api.FeedResponse, error) {
+	return withAuth(s.tokens, func(at string) (api.FeedResponse, error) {
+		return api.FetchFeed(at, projectID, opts)
+	})
+}
+
+func (s *librarySyncSource) Markdown(projectPaperID string) (string, error) {
+	return withAuth(s.tokens, func(at string) (string, error) {
+		return api.FetchProjectPaperMarkdown(at, projectPaperID)
+	})
+}
+
+func writeSyncResult(out io.Writer, result library.ProjectResult) {
+	kind := "incremental"
+	if result.Full {
+		kind = "full"
+	}
+	fmt.Fprintf(out, "%s: %d new, %d updated", terminalSafeInline(result.Project.Name), result.New, result.Updated)
+	if result.Removed > 0 {
+		fmt.Fprintf(out, ", %d removed", result.Removed)
+	}
+	if result.Markdown > 0 {
+		fmt.Fprintf(out, ", %d markdown", result.Markdown)
+	}
+	fmt.Fprintf(out, " (%s, %d papers)\n", kind, result.Total)
+}
+
+type syncProjectJSON struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Full     bool   `json:"full"`
+	New      int    `json:"new"`
+	Updated  int    `json:"updated"`
+	Removed  int    `json:"removed"`
+	Markdown int    `json:"markdown"`
+	Total    int    `json:"total"`
+}
+
+func writeSyncJSON(out io.Writer, path string, results []library.ProjectResult) error {
+	projects := make([]syncProjectJSON, 0, len(results))
+	for _, result := range results {
+		projects = append(projects, syncProjectJSON{
+			ID:       result.Project.ID,
+			Name:     result.Project.Name,
+			Full:     result.Full,
+			New:      result.New,
+			Updated:  result.Updated,
+			Removed:  result.Removed,
+			Markdown: result.Markdown,
+			Total:    result.Total,
+		})
+	}
+	data, err := json.MarshalIndent(struct {
+		Library  string            `json:"library"`
+		Projects []syncProjectJSON `json:"projects"`
+	}{path, projects}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Fprintln(out, string(data))
+	return nil
+}
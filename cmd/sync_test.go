@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/paperzilla/pz/internal/library"
+	"github.com/spf13/cobra"
+)
+
+func TestSyncWritesLibraryAndPrintsSummary(t *testing.T) {
+	var feedQueries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/projects":
+			w.Write([]byte(`[{"id":"proj-1","name":"Graph Learning"}]`))
+		case "/api/projects/proj-1":
+			w.Write([]byte(`{"id":"proj-1","name":"Graph Learning"}`))
+		case "/api/projects/proj-1/feed":
+			feedQueries = append(feedQueries, r.URL.Query().Get("since"))
+			w.Write([]byte(`{"items":[{"id":"pp-1","paper_title":"First","ready_at":"2026-01-02T00:00:00Z","paper":{"id":"paper-1"}}],"total":1}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("PZ_API_URL", server.URL)
+	t.Setenv("PZ_LIBRARY_PATH", filepath.Join(t.TempDir(), "library.db"))
+	writeTestTokens(t)
+
+	run := func(flags ...string) string {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("full", false, "")
+		cmd.Flags().BoolP("json", "j", false, "")
+		cmd.Flags().Parse(flags)
+		var stdout bytes.Buffer
+		cmd.SetOut(&stdout)
+		if err := syncCmd.RunE(cmd, nil); err != nil {
+			t.Fatalf("RunE: %v", err)
+		}
+		return stdout.String()
+	}
+
+	if out := run(); !strings.Contains(out, "Graph Learning: 1 new, 0 updated (full, 1 papers)") {
+		t.Fatalf("first sync output:\n%s", out)
+	}
+	if out := run("--json"); !strings.Contains(out, `"updated": 1`) || !strings.Contains(out, `"full": false`) {
+		t.Fatalf("second sync output:\n%s", out)
+	}
+	if feedQueries[1] != "2026-01-02T00:00:00Z" {
+		t.Fatalf("since = %q", feedQueries)
+	}
+
+	lib, err := library.Open(config.LibraryPath())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer lib.Close()
+	if items, err := lib.ProjectPapers("proj-1"); err != nil || len(items) != 1 || items[0].PaperTitle != "First" {
+		t.Fatalf("ProjectPapers = %+v, %v", items, err)
+	}
+}
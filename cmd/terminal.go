@@ -2,8 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"strconv"
 	"strings"
 	"unicode"
+
+	"golang.org/x/term"
 )
 
 // terminalSafeInline preserves printable text while rendering terminal control
@@ -52,3 +57,34 @@ func terminalSafeBlock(value, continuationIndent string) string {
 	}
 	return strings.Join(lines, "\n"+continuationIndent)
 }
+
+// outputWidth reports how many terminal cells human-readable output written
+// to w may use. Zero means w is not a terminal (pipes, files, buffers) and
+// output should not be fitted to a width. $COLUMNS is used only when the
+// terminal's size cannot be read.
+func outputWidth(w io.Writer) int {
+	file, ok := terminalFile(w)
+	if !ok {
+		return 0
+	}
+	if width, _, err := term.GetSize(int(file.Fd())); err == nil && width > 0 {
+		return width
+	}
+	if columns, err := strconv.Atoi(strings.TrimSpace(os.Getenv("COLUMNS"))); err == nil && columns > 0 {
+		return columns
+	}
+	return 0
+}
+
+// terminalFile returns w as a file when it is an interactive terminal.
+// Output buffered for the pager reports the terminal it will end up on.
+func terminalFile(w io.Writer) (*os.File, bool) {
+	if buf, ok := w.(*pageBuffer); ok {
+		return buf.terminal, true
+	}
+	file, ok := w.(*os.File)
+	if !ok || !isInteractiveTerminal(file) {
+		return nil, false
+	}
+	return file, true
+}
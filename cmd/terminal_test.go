@@ -73,3 +73,13 @@ func TestAtomFeedURLEncodesProjectAndToken(t *testing.T) {
 		t.Fatalf("decoded token = %q, want %q", gotToken, token)
 	}
 }
+
+func TestOutputWidthIgnoresColumnsWhenNotATerminal(t *testing.T) {
+	t.Setenv("COLUMNS", "40")
+	if width := outputWidth(&bytes.Buffer{}); width != 0 {
+		t.Fatalf("outputWidth(buffer) = %d, want 0", width)
+	}
+	if width := outputWidth(&pageBuffer{}); width != 40 {
+		t.Fatalf("outputWidth(terminal without a size) = %d, want 40", width)
+	}
+}
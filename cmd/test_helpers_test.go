@@ -3,8 +3,13 @@ package cmd
 import (
 	"bytes"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
+
+	"github.com/spf13/cobra"
 )
 
 func captureStdout(t *testing.T, fn func()) string {
@@ -33,3 +38,65 @@ func captureStdout(t *testing.T, fn func()) string {
 	os.Stdout = origStdout
 	return <-outputCh
 }
+
+// testRoutes maps "METHOD /path", or just "/path" for any method, to the
+// handler that serves it.
+type testRoutes map[string]http.HandlerFunc
+
+// newTestAPI serves routes as the pz API for the rest of the test, with test
+// tokens written. Handlers run one at a time, so they can record requests
+// without locking. A request no route matches fails the test.
+func newTestAPI(t *testing.T, routes testRoutes) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		handler, ok := routes[r.Method+" "+r.URL.Path]
+		if !ok {
+			handler, ok = routes[r.URL.Path]
+		}
+		if !ok {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+	return server
+}
+
+// respondWith serves body to every request.
+func respondWith(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+// newTestCommand returns a command with the flags defineFlags sets up, parsed
+// from args. Its output goes to the returned stdout and stderr buffers.
+func newTestCommand(t *testing.T, defineFlags func(*cobra.Command), args ...string) (*cobra.Command, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	defineFlags(cmd)
+	if err := cmd.Flags().Parse(args); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	return cmd, &stdout, &stderr
+}
+
+// asTerminal points the output of cmd at a page buffer, which stands in for a
+// terminal so shown papers are marked seen. PZ_PAGER=cat keeps it unpaged.
+func asTerminal(t *testing.T, cmd *cobra.Command) *bytes.Buffer {
+	t.Helper()
+	t.Setenv("PZ_PAGER", "cat")
+	stdout := &pageBuffer{}
+	cmd.SetOut(stdout)
+	return &stdout.Buffer
+}
@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/spf13/cobra"
+)
+
+const triagePageSize = 50
+
+func init() {
+	triageCmd.Flags().BoolP("must-read", "m", false, "Only triage must-read papers")
+	triageCmd.Flags().StringP("since", "s", "", "Only papers ready after this date")
+	triageCmd.Flags().IntP("limit", "n", 0, "Stop after this many papers (0 for all)")
+}
+
+var triageCmd = &cobra.Command{
+	Use:   "triage <project-id>",
+	Short: "Rate unrated recommendations one at a time",
+	Long: "Rate unrated recommendations one at a time.\n\n" +
+		"Each paper waits for one line of input:\n" +
+		"  u  upvote          d  downvote (asks for a reason)\n" +
+		"  s  star            enter or n  skip\n" +
+		"  o  open in browser z  undo the last rating\n" +
+		"  q  quit and print the session summary",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mustRead, _ := cmd.Flags().GetBool("must-read")
+		since, _ := cmd.Flags().GetString("since")
+		limit, _ := cmd.Flags().GetInt("limit")
+		if limit < 0 {
+			return fmt.Errorf("invalid triage request: limit must be at least 0")
+		}
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+
+		projectID := args[0]
+		client := newFeedbackClient(tokenAuth(&tokens), tokens, cmd.ErrOrStderr())
+		session := &triageSession{
+			in:    bufio.NewScanner(cmd.InOrStdin()),
+			out:   cmd.OutOrStdout(),
+			limit: limit,
+			fetch: func(offset int) (api.FeedResponse, error) {
+				return withAuth(&tokens, func(at string) (api.FeedResponse, error) {
+					return api.FetchFeed(at, projectID, api.FeedOptions{
+						MustReadOnly: mustRead,
+						Since:        since,
+						Limit:        triagePageSize,
+						Offset:       offset,
+					})
+				})
+			},
+			setFeedback: func(ref, vote, reason string) (*api.Feedback, error) {
+				change, err := applyFeedback(client, feedbackRequest{Ref: ref, Vote: vote, Reason: reason})
+				return change.New, err
+			},
+		}
+		return session.run()
+	},
+}
+
+type triageAction struct {
+	item     api.ProjectPaper
+	previous *api.Feedback
+	outcome  string
+}
+
+// triageSession walks unrated feed items over plain line-based input, so it
+// works in any terminal and can be scripted.
+type triageSession struct {
+	in          *bufio.Scanner
+	out         io.Writer
+	limit       int
+	fetch       func(offset int) (api.FeedResponse, error)
+	setFeedback func(ref, vote, reason string) (*api.Feedback, error)
+
+	queue     []api.ProjectPaper
+	offset    int
+	exhausted bool
+	shown     int
+	history   []triageAction
+	counts    map[string]int
+}
+
+func (s *triageSession) run() error {
+	s.counts = map[string]int{}
+	defer s.printSummary()
+
+	for s.limit == 0 || s.shown < s.limit {
+		item, ok, err := s.next()
+		if err != nil {
+			return fmt.Errorf("failed to fetch feed: %w", err)
+		}
+		if !ok {
+			fmt.Fprintln(s.out, "No more unrated papers.")
+			return nil
+		}
+
+		s.shown++
+		s.show(item)
+		if quit := s.prompt(item); quit {
+			return nil
+		}
+	}
+	return nil
+}
+
+// next returns the next unrated item, paging through the feed as needed.
+// Ratings never change the unfiltered feed's order, so offsets stay valid.
+func (s *triageSession) next() (api.ProjectPaper, bool, error) {
+	for len(s.queue) == 0 {
+		if s.exhausted {
+			return api.ProjectPaper{}, false, nil
+		}
+		page, err := s.fetch(s.offset)
+		if err != nil {
+			return api.ProjectPaper{}, false, err
+		}
+		s.offset += len(page.Items)
+		s.exhausted = len(page.Items) == 0 || s.offset >= page.Total
+		for _, item := range page.Items {
+			if item.Feedback == nil || strings.TrimSpace(item.Feedback.Vote) == "" {
+				s.queue = append(s.queue, item)
+			}
+		}
+	}
+	item := s.queue[0]
+	s.queue = s.queue[1:]
+	return item, true, nil
+}
+
+func (s *triageSession) show(item api.ProjectPaper) {
+	fmt.Fprintf(s.out, "\n[%d] %s  %s\n", s.shown, formatRelevance(item.RelevanceClass, item.RelevanceScore), terminalSafeInline(item.PaperTitle))
+	fmt.Fprintf(s.out, "    %s\n", joinDisplayParts(
+		firstAuthorSurname(item.Paper.Authors),
+		paperListLabel(item.Paper),
+		formatTime(item.Paper.PublishedDate),
+		item.ID,
+	))
+	if strings.TrimSpace(item.PersonalizedNote) != "" {
+		fmt.Fprintf(s.out, "\nNote:\n  %s\n", terminalSafeBlock(item.PersonalizedNote, "  "))
+	}
+	if strings.TrimSpace(item.Summary) != "" {
+		fmt.Fprintf(s.out, "\nSummary:\n  %s\n", terminalSafeBlock(item.Summary, "  "))
+	}
+}
+
+// prompt reads commands for one item until it is rated, skipped or the
+// session ends. It reports whether the user quit.
+func (s *triageSession) prompt(item api.ProjectPaper) bool {
+	for {
+		fmt.Fprint(s.out, "\n[u]pvote [d]ownvote [s]tar [n]ext [o]pen [z] undo [q]uit > ")
+		input, ok := s.readLine()
+		if !ok {
+			fmt.Fprintln(s.out)
+			return true
+		}
+
+		switch input {
+		case "u", "upvote":
+			if s.rate(item, "upvote", "") {
+				return false
+			}
+		case "s", "star":
+			if s.rate(item, "star", "") {
+				return false
+			}
+		case "d", "downvote":
+			reason, ok := s.promptReason()
+			if !ok {
+				continue
+			}
+			if s.rate(item, "downvote", reason) {
+				return false
+			}
+		case "", "n", "skip":
+			s.counts["skipped"]++
+			return false
+		case "o", "open":
+			s.open(item)
+		case "z", "undo":
+			if s.undo() {
+				// Re-rate the undone paper first, then come back to this one.
+				s.queue = append([]api.ProjectPaper{s.queue[0], item}, s.queue[1:]...)
+				s.shown--
+				return false
+			}
+		case "q", "quit":
+			return true
+		default:
+			fmt.Fprintf(s.out, "Unknown command %q.\n", input)
+		}
+	}
+}
+
+func (s *triageSession) promptReason() (string, bool) {
+	fmt.Fprint(s.out, "Reason: [n]ot relevant, [l]ow quality, enter for none, [c]ancel > ")
+	input, ok := s.readLine()
+	if !ok {
+		return "", false
+	}
+	switch input {
+	case "n", "not_relevant":
+		return "not_relevant", true
+	case "l", "low_quality":
+		return "low_quality", true
+	case "":
+		return "", true
+	default:
+		return "", false
+	}
+}
+
+func (s *triageSession) readLine() (string, bool) {
+	if !s.in.Scan() {
+		return "", false
+	}
+	return strings.ToLower(strings.TrimSpace(s.in.Text())), true
+}
+
+func (s *triageSession) rate(item api.ProjectPaper, vote, reason string) bool {
+	feedback, err := s.setFeedback(item.ID, vote, reason)
+	if err != nil {
+		fmt.Fprintf(s.out, "Failed to save feedback: %s\n", terminalSafeInline(err.Error()))
+		return false
+	}
+
+	s.history = append(s.history, triageAction{item: item, previous: item.Feedback, outcome: vote})
+	s.counts[vote]++
+	fmt.Fprintf(s.out, "Feedback set: %s\n", terminalSafeInline(formatFeedback(feedback)))
+	return true
+}
+
+// undo restores the feedback the last rated item had before this session
+// and queues it to be shown again.
+func (s *triageSession) undo() bool {
+	if len(s.history) == 0 {
+		fmt.Fprintln(s.out, "Nothing to undo.")
+		return false
+	}
+	last := s.history[len(s.history)-1]
+
+	vote, reason := "clear", ""
+	if last.previous != nil && last.previous.Vote != "" {
+		vote, reason = last.previous.Vote, last.previous.DownvoteReason
+	}
+	if _, err := s.setFeedback(last.item.ID, vote, reason); err != nil {
+		fmt.Fprintf(s.out, "Failed to undo: %s\n", terminalSafeInline(err.Error()))
+		return false
+	}
+
+	s.history = s.history[:len(s.history)-1]
+	s.counts[last.outcome]--
+	s.counts["undone"]++
+	s.queue = append([]api.ProjectPaper{last.item}, s.queue...)
+	s.shown--
+	fmt.Fprintf(s.out, "Undid %s on %s.\n", last.outcome, terminalSafeInline(last.item.ID))
+	return true
+}
+
+// open prints the paper's links and opens its page in the browser.
+func (s *triageSession) open(item api.ProjectPaper) {
+	s.showLinks(item)
+
+	target := item.Paper.URL
+	if strings.TrimSpace(target) == "" {
+		target = recommendationWebURL(item.ID)
+	}
+	if err := openURLFunc(target); err == nil {
+		fmt.Fprintf(s.out, "Opened %s\n", terminalSafeInline(target))
+	}
+}
+
+func (s *triageSession) showLinks(item api.ProjectPaper) {
+	for _, link := range []struct{ label, url string }{
+		{"URL", item.Paper.URL},
+		{"PDF", item.Paper.PdfURL},
+		{"DOI", doiURL(item.Paper.DOI)},
+		{"Web", recommendationWebURL(item.ID)},
+	} {
+		if strings.TrimSpace(link.url) != "" {
+			fmt.Fprintf(s.out, "  %-4s %s\n", link.label+":", terminalSafeInline(link.url))
+		}
+	}
+}
+
+func (s *triageSession) printSummary() {
+	rated := s.counts["upvote"] + s.counts["downvote"] + s.counts["star"]
+	fmt.Fprintf(s.out, "\nTriage summary: %d rated (%d upvoted, %d downvoted, %d starred), %d skipped",
+		rated, s.counts["upvote"], s.counts["downvote"], s.counts["star"], s.counts["skipped"])
+	if s.counts["undone"] > 0 {
+		fmt.Fprintf(s.out, ", %d undone", s.counts["undone"])
+	}
+	fmt.Fprintln(s.out, ".")
+}
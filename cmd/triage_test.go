@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newTriageTestServer(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var calls []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.URL.Path == "/api/projects/proj-1/feed":
+			calls = append(calls, "GET feed offset="+r.URL.Query().Get("offset")+" must_read="+r.URL.Query().Get("must_read"))
+			_, _ = w.Write([]byte(`{"items":[
+				{"id":"pp-1","paper_title":"First","summary":"One","relevance_class":2,"paper":{"authors":[{"name":"Ada Lovelace"}]}},
+				{"id":"pp-2","paper_title":"Already rated","feedback":{"vote":"upvote"},"paper":{}},
+				{"id":"pp-3","paper_title":"Third","personalized_note":"Read this","paper":{"url":"https://arxiv.org/abs/3"}},
+				{"id":"pp-4","paper_title":"Fourth","paper":{}}
+			],"total":4}`))
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/project-papers/"):
+			_, _ = w.Write([]byte(`{"id":"` + strings.TrimPrefix(r.URL.Path, "/api/project-papers/") + `"}`))
+		case strings.HasSuffix(r.URL.Path, "/feedback"):
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			ref := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/project-papers/"), "/feedback")
+			if r.Method == http.MethodDelete {
+				calls = append(calls, "DELETE "+ref)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			calls = append(calls, strings.TrimSpace(r.Method+" "+ref+" "+body["vote"]+" "+body["downvote_reason"]))
+			_ = json.NewEncoder(w).Encode(body)
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func runTriageTest(t *testing.T, input string, flags ...string) (string, []string) {
+	t.Helper()
+	server, calls := newTriageTestServer(t)
+	t.Setenv("PZ_API_URL", server.URL)
+	writeTestTokens(t)
+
+	cmd := &cobra.Command{}
+	cmd.Flags().BoolP("must-read", "m", false, "")
+	cmd.Flags().StringP("since", "s", "", "")
+	cmd.Flags().IntP("limit", "n", 0, "")
+	if err := cmd.Flags().Parse(flags); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetIn(strings.NewReader(input))
+
+	if err := triageCmd.RunE(cmd, []string{"proj-1"}); err != nil {
+		t.Fatalf("RunE: %v", err)
+	}
+	return stdout.String(), *calls
+}
+
+func TestTriageRatesUnratedPapersInOrder(t *testing.T) {
+	out, calls := runTriageTest(t, "u\nd\nn\n\n")
+
+	want := []string{
+		"GET feed offset= must_read=",
+		"PUT pp-1 upvote",
+		"PUT pp-3 downvote not_relevant",
+	}
+	if strings.Join(calls, "|") != strings.Join(want, "|") {
+		t.Fatalf("calls = %q, want %q", calls, want)
+	}
+	if strings.Contains(out, "Already rated") {
+		t.Fatalf("rated papers should be skipped:\n%s", out)
+	}
+	for _, want := range []string{"[1] Must Read  First", "Read this", "No more unrated papers.", "Triage summary: 2 rated (1 upvoted, 1 downvoted, 0 starred), 1 skipped."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestTriageUndoRestoresPreviousStateAndReshowsPaper(t *testing.T) {
+	out, calls := runTriageTest(t, "s\nz\nu\nq\n")
+
+	want := []string{
+		"GET feed offset= must_read=",
+		"PUT pp-1 star",
+		"DELETE pp-1",
+		"PUT pp-1 upvote",
+	}
+	if strings.Join(calls, "|") != strings.Join(want, "|") {
+		t.Fatalf("calls = %q, want %q", calls, want)
+	}
+	if strings.Count(out, "[1] Must Read  First") != 2 {
+		t.Fatalf("undone paper should be shown again:\n%s", out)
+	}
+	if !strings.Contains(out, "[2] Related  Third") {
+		t.Fatalf("paper after undo should keep its position:\n%s", out)
+	}
+	if !strings.Contains(out, "Triage summary: 1 rated (1 upvoted, 0 downvoted, 0 starred), 0 skipped, 1 undone.") {
+		t.Fatalf("summary:\n%s", out)
+	}
+}
+
+func TestTriageOpenShowsLinksAndLimitStopsEarly(t *testing.T) {
+	var opened []string
+	originalOpen := openURLFunc
+	openURLFunc = func(target string) error {
+		opened = append(opened, target)
+		return nil
+	}
+	t.Cleanup(func() { openURLFunc = originalOpen })
+
+	out, calls := runTriageTest(t, "n\no\nn\n", "--limit", "2", "--must-read")
+
+	if calls[0] != "GET feed offset= must_read=true" {
+		t.Fatalf("calls = %q", calls)
+	}
+	if !strings.Contains(out, "URL: https://arxiv.org/abs/3") {
+		t.Fatalf("open should print links:\n%s", out)
+	}
+	if len(opened) != 1 || opened[0] != "https://arxiv.org/abs/3" {
+		t.Fatalf("opened = %q", opened)
+	}
+	if strings.Contains(out, "Fourth") {
+		t.Fatalf("limit should stop after two papers:\n%s", out)
+	}
+}
+
+func TestTriageEndOfInputQuits(t *testing.T) {
+	out, calls := runTriageTest(t, "")
+
+	if len(calls) != 1 {
+		t.Fatalf("calls = %q", calls)
+	}
+	if !strings.Contains(out, "Triage summary: 0 rated") {
+		t.Fatalf("output:\n%s", out)
+	}
+}
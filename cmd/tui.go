@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const tuiResizePoll = 250 * time.Millisecond
+
+const (
+	ansiDim     = "\x1b[2m"
+	ansiReverse = "\x1b[7m"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui [project-id]",
+	Short: "Browse and triage a project feed in a full-screen terminal UI",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, ok := terminalFile(cmd.OutOrStdout())
+		if !ok || !isInteractiveTerminal(os.Stdin) {
+			return fmt.Errorf("pz tui needs an interactive terminal; use `pz feed` for scripts")
+		}
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+
+		projectID := ""
+		if len(args) == 1 {
+			projectID = args[0]
+		}
+
+		model := newTUIModel(&tuiAPISource{tokens: &tokens}, supportsColor(out))
+		return runTUI(model, projectID, os.Stdin, out)
+	},
+}
+
+// tuiAPISource serves the browser from the Paperzilla API.
+type tuiAPISource struct {
+	tokens *config.Tokens
+}
+
+func (s *tuiAPISource) Projects() ([]api.Project, error) {
+	return withAuth(s.tokens, func(at string) ([]api.Project, error) {
+		return api.FetchProjects(at)
+	})
+}
+
+func (s *tuiAPISource) Page(projectID, query string, mustRead bool, offset, limit int) ([]api.ProjectPaper, bool, error) {
+	if query != "" {
+		opts := api.FeedSearchOptions{Query: query, Limit: limit, Offset: offset}
+		if mustRead {
+			opts.MustRead = &mustRead
+		}
+		search, err := withAuth(s.tokens, func(at string) (api.FeedSearchResponse, error) {
+			return api.FetchFeedSearch(at, projectID, opts)
+		})
+		if err != nil {
+			return nil, false, wrapFeedSearchError(err)
+		}
+		return search.Items, search.HasMore, nil
+	}
+
+	feed, err := withAuth(s.tokens, func(at string) (api.FeedResponse, error) {
+		return api.FetchFeed(at, projectID, api.FeedOptions{MustReadOnly: mustRead, Limit: limit, Offset: offset})
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return feed.Items, offset+len(feed.Items) < feed.Total, nil
+}
+
+// SetFeedback does not warn about feedback log failures, since stderr is
+// hidden behind the full-screen view.
+func (s *tuiAPISource) SetFeedback(ref, vote, reason string) (*api.Feedback, error) {
+	client := newFeedbackClient(tokenAuth(s.tokens), *s.tokens, nil)
+	change, err := applyFeedback(client, feedbackRequest{Ref: ref, Vote: vote, Reason: reason})
+	return change.New, err
+}
+
+func (s *tuiAPISource) Markdown(ref string) (string, error) {
+	return withAuth(s.tokens, func(at string) (string, error) {
+		return api.FetchProjectPaperMarkdown(at, ref)
+	})
+}
+
+// runTUI drives the model on the alternate screen until the user quits.
+// The terminal is always restored, even when a load fails.
+func runTUI(model *tuiModel, projectID string, in, out *os.File) error {
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to set up terminal: %w", err)
+	}
+	defer term.Restore(int(in.Fd()), state)
+
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	keys := make(chan string)
+	go readTUIKeys(in, keys)
+
+	draw := func() {
+		width, height, err := terminalSizeFunc(out)
+		if err != nil || width <= 0 || height <= 0 {
+			width, height = defaultRenderWidth, 24
+		}
+		model.width, model.height = width, height
+		drawTUI(out, model.view())
+	}
+
+	ticker := time.NewTicker(tuiResizePoll)
+	defer ticker.Stop()
+	resized := func() bool {
+		width, height, err := terminalSizeFunc(out)
+		return err == nil && (width != model.width || height != model.height)
+	}
+
+	model.status = "Loading..."
+	task := model.init(projectID)
+	for !model.quit {
+		draw()
+		if task != nil {
+			task()
+			task = nil
+			continue
+		}
+
+		key, ok := waitTUIKey(keys, ticker.C, resized)
+		if !ok {
+			return nil
+		}
+		if key != "" {
+			task = model.handleKey(key)
+		}
+	}
+	return nil
+}
+
+// waitTUIKey blocks until a key arrives, or returns "" once the terminal
+// has been resized so the caller can redraw.
+func waitTUIKey(keys <-chan string, tick <-chan time.Time, resized func() bool) (string, bool) {
+	for {
+		select {
+		case key, ok := <-keys:
+			return key, ok
+		case <-tick:
+			if resized() {
+				return "", true
+			}
+		}
+	}
+}
+
+func drawTUI(out io.Writer, lines []string) {
+	var frame strings.Builder
+	frame.WriteString("\x1b[H")
+	for i, line := range lines {
+		if i > 0 {
+			frame.WriteString("\r\n")
+		}
+		frame.WriteString(line)
+		frame.WriteString("\x1b[K")
+	}
+	frame.WriteString("\x1b[J")
+	_, _ = io.WriteString(out, frame.String())
+}
+
+func readTUIKeys(in io.Reader, keys chan<- string) {
+	defer close(keys)
+	buf := make([]byte, 64)
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			return
+		}
+		for _, key := range parseTUIKeys(buf[:n]) {
+			keys <- key
+		}
+	}
+}
+
+var tuiEscapeKeys = map[string]string{
+	"[A": "up", "[B": "down", "[C": "right", "[D": "left",
+	"OA": "up", "OB": "down", "OC": "right", "OD": "left",
+	"[H": "home", "[F": "end", "OH": "home", "OF": "end",
+	"[1~": "home", "[4~": "end", "[5~": "pgup", "[6~": "pgdown",
+}
+
+// parseTUIKeys turns one read from a raw terminal into key names: single
+// characters, "enter", "esc", "backspace", "ctrl-x" or arrow and paging
+// keys. Unknown escape sequences are dropped.
+func parseTUIKeys(data []byte) []string {
+	var keys []string
+	for len(data) > 0 {
+		switch c := data[0]; {
+		case c == 0x1b:
+			if len(data) == 1 {
+				return append(keys, "esc")
+			}
+			n := escapeSequenceLen(data)
+			if key, ok := tuiEscapeKeys[string(data[1:n])]; ok {
+				keys = append(keys, key)
+			} else if n == 1 {
+				keys = append(keys, "esc")
+			}
+			data = data[n:]
+		case c == '\r' || c == '\n':
+			keys = append(keys, "enter")
+			data = data[1:]
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, "backspace")
+			data = data[1:]
+		case c == '\t':
+			keys = append(keys, "tab")
+			data = data[1:]
+		case c < 0x20:
+			keys = append(keys, "ctrl-"+string(rune('a'+c-1)))
+			data = data[1:]
+		default:
+			r, size := utf8.DecodeRune(data)
+			if r != utf8.RuneError {
+				keys = append(keys, string(r))
+			}
+			data = data[size:]
+		}
+	}
+	return keys
+}
+
+// escapeSequenceLen returns the length of the CSI or SS3 sequence at the
+// start of data, or 1 for a lone escape.
+func escapeSequenceLen(data []byte) int {
+	if len(data) < 2 || (data[1] != '[' && data[1] != 'O') {
+		return 1
+	}
+	if data[1] == 'O' {
+		return min(3, len(data))
+	}
+	for i := 2; i < len(data); i++ {
+		if data[i] >= 0x40 && data[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(data)
+}
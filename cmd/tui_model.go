@@ -0,0 +1,685 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/layout"
+	"github.com/paperzilla/pz/internal/mdrender"
+)
+
+const (
+	tuiPageSize      = 50
+	tuiPrefetchRows  = 5
+	tuiSplitMinWidth = 100
+)
+
+// tuiSource is everything the browser needs from the API. The command wires
+// it to withAuth-wrapped calls; tests use an in-memory fake.
+type tuiSource interface {
+	Projects() ([]api.Project, error)
+	Page(projectID, query string, mustRead bool, offset, limit int) ([]api.ProjectPaper, bool, error)
+	SetFeedback(ref, vote, reason string) (*api.Feedback, error)
+	Markdown(ref string) (string, error)
+}
+
+type tuiMode int
+
+const (
+	tuiModeList tuiMode = iota
+	tuiModeProjects
+	tuiModeSearch
+	tuiModeReason
+	tuiModeReader
+	tuiModeHelp
+)
+
+type tuiModel struct {
+	source tuiSource
+	color  bool
+	width  int
+	height int
+
+	projects      []api.Project
+	projectCursor int
+	projectID     string
+	projectName   string
+
+	items    []api.ProjectPaper
+	hasMore  bool
+	query    string
+	filter   string
+	input    string
+	mustRead bool
+
+	cursor    int
+	listTop   int
+	detailTop int
+
+	mode      tuiMode
+	reader    []string
+	readerTop int
+
+	status string
+	quit   bool
+}
+
+func newTUIModel(source tuiSource, color bool) *tuiModel {
+	return &tuiModel{source: source, color: color, mode: tuiModeProjects}
+}
+
+// init returns the first load: the project's feed, or the project picker
+// when no project was given.
+func (m *tuiModel) init(projectID string) func() {
+	if projectID == "" {
+		return m.loadProjects
+	}
+	m.projectID = projectID
+	m.projectName = projectID
+	m.mode = tuiModeList
+	return func() {
+		// The project list only supplies the header name and the p switcher;
+		// the feed still loads if it fails.
+		if projects, err := m.source.Projects(); err == nil {
+			m.projects = projects
+			for i, project := range projects {
+				if project.ID == projectID {
+					m.projectName = project.Name
+					m.projectCursor = i
+				}
+			}
+		}
+		m.reload()
+	}
+}
+
+func (m *tuiModel) loadProjects() {
+	projects, err := m.source.Projects()
+	if err != nil {
+		m.status = "Failed to load projects: " + err.Error()
+		return
+	}
+	m.projects = projects
+	if len(projects) == 1 {
+		m.openProject(projects[0])
+		m.reload()
+		return
+	}
+	if len(projects) == 0 {
+		m.status = "No projects found. Create your first project: " + cliGettingStartedURL
+	}
+}
+
+func (m *tuiModel) openProject(project api.Project) {
+	m.projectID = project.ID
+	m.projectName = project.Name
+	m.mode = tuiModeList
+	m.items = nil
+	m.query, m.filter = "", ""
+}
+
+func (m *tuiModel) reload() {
+	m.items, m.hasMore = nil, false
+	m.cursor, m.listTop, m.detailTop = 0, 0, 0
+	m.loadMore()
+}
+
+func (m *tuiModel) loadMore() {
+	items, hasMore, err := m.source.Page(m.projectID, m.query, m.mustRead, len(m.items), tuiPageSize)
+	if err != nil {
+		m.status = "Failed to load feed: " + err.Error()
+		m.hasMore = false
+		return
+	}
+	m.items = append(m.items, items...)
+	m.hasMore = hasMore
+	m.status = ""
+}
+
+// visible returns indices into items that match the incremental filter.
+func (m *tuiModel) visible() []int {
+	needle := strings.ToLower(strings.TrimSpace(m.filter))
+	if m.mode == tuiModeSearch {
+		needle = strings.ToLower(strings.TrimSpace(m.input))
+	}
+	indices := make([]int, 0, len(m.items))
+	for i, item := range m.items {
+		if needle == "" || strings.Contains(tuiSearchText(item), needle) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+func tuiSearchText(item api.ProjectPaper) string {
+	return strings.ToLower(strings.Join([]string{
+		item.PaperTitle,
+		authorNames(item.Paper.Authors),
+		item.Paper.VenueName,
+	}, "\n"))
+}
+
+func (m *tuiModel) selected() (*api.ProjectPaper, bool) {
+	visible := m.visible()
+	if m.cursor < 0 || m.cursor >= len(visible) {
+		return nil, false
+	}
+	return &m.items[visible[m.cursor]], true
+}
+
+// handleKey applies one keypress. Slow work (API calls) is returned as a
+// task so the caller can draw a "Loading..." frame before running it.
+func (m *tuiModel) handleKey(key string) func() {
+	if key == "ctrl-c" {
+		m.quit = true
+		return nil
+	}
+	switch m.mode {
+	case tuiModeProjects:
+		return m.handleProjectKey(key)
+	case tuiModeSearch:
+		return m.handleSearchKey(key)
+	case tuiModeReason:
+		return m.handleReasonKey(key)
+	case tuiModeReader:
+		m.handleReaderKey(key)
+		return nil
+	case tuiModeHelp:
+		m.mode = tuiModeList
+		return nil
+	}
+	return m.handleListKey(key)
+}
+
+func (m *tuiModel) handleProjectKey(key string) func() {
+	switch key {
+	case "q", "esc":
+		m.quit = true
+	case "j", "down":
+		m.projectCursor = min(m.projectCursor+1, max(len(m.projects)-1, 0))
+	case "k", "up":
+		m.projectCursor = max(m.projectCursor-1, 0)
+	case "enter":
+		if m.projectCursor < len(m.projects) {
+			m.openProject(m.projects[m.projectCursor])
+			m.status = "Loading..."
+			return m.reload
+		}
+	}
+	return nil
+}
+
+func (m *tuiModel) handleListKey(key string) func() {
+	visible := m.visible()
+	switch key {
+	case "q":
+		m.quit = true
+		return nil
+	case "esc":
+		switch {
+		case m.filter != "":
+			m.filter = ""
+			m.cursor = 0
+		case m.query != "":
+			m.query = ""
+			m.status = "Loading..."
+			return m.reload
+		}
+		return nil
+	case "?":
+		m.mode = tuiModeHelp
+		return nil
+	case "j", "down":
+		m.moveCursor(1, len(visible))
+	case "k", "up":
+		m.moveCursor(-1, len(visible))
+	case "pgdown", "ctrl-d", " ":
+		m.moveCursor(m.listHeight(), len(visible))
+	case "pgup", "ctrl-u":
+		m.moveCursor(-m.listHeight(), len(visible))
+	case "g", "home":
+		m.moveCursor(-len(visible), len(visible))
+	case "G", "end":
+		m.moveCursor(len(visible), len(visible))
+	case "J":
+		m.detailTop++
+		return nil
+	case "K":
+		m.detailTop = max(m.detailTop-1, 0)
+		return nil
+	case "/":
+		m.mode = tuiModeSearch
+		m.input = m.filter
+		return nil
+	case "m":
+		m.mustRead = !m.mustRead
+		m.status = "Loading..."
+		return m.reload
+	case "r":
+		m.status = "Loading..."
+		return m.reload
+	case "p":
+		if len(m.projects) > 1 {
+			m.mode = tuiModeProjects
+		}
+		return nil
+	case "u":
+		return m.feedbackTask("upvote", "")
+	case "s":
+		return m.feedbackTask("star", "")
+	case "c":
+		return m.feedbackTask("clear", "")
+	case "d":
+		if _, ok := m.selected(); ok {
+			m.mode = tuiModeReason
+		}
+		return nil
+	case "enter", "o":
+		return m.readerTask()
+	default:
+		return nil
+	}
+
+	if m.hasMore && m.filter == "" && m.cursor >= len(visible)-tuiPrefetchRows {
+		m.status = "Loading more..."
+		return m.loadMore
+	}
+	return nil
+}
+
+func (m *tuiModel) moveCursor(delta, count int) {
+	if count == 0 {
+		m.cursor = 0
+		return
+	}
+	m.cursor = min(max(m.cursor+delta, 0), count-1)
+	m.detailTop = 0
+}
+
+func (m *tuiModel) handleSearchKey(key string) func() {
+	switch key {
+	case "esc":
+		m.mode = tuiModeList
+		m.input = ""
+	case "backspace":
+		if m.input != "" {
+			runes := []rune(m.input)
+			m.input = string(runes[:len(runes)-1])
+		}
+		m.cursor = 0
+	case "enter":
+		m.mode = tuiModeList
+		input := strings.TrimSpace(m.input)
+		m.input = ""
+		m.cursor = 0
+		switch {
+		case input == "" && m.query == "":
+			m.filter = ""
+		case input == "":
+			// Leaving a server search returns to the full feed.
+			m.filter, m.query = "", ""
+			m.status = "Loading..."
+			return m.reload
+		case len(input) < api.MinFeedSearchQueryLength:
+			m.filter = input
+		default:
+			m.filter, m.query = "", input
+			m.status = "Searching..."
+			return m.reload
+		}
+	default:
+		if len([]rune(key)) == 1 {
+			m.input += key
+			m.cursor = 0
+		}
+	}
+	return nil
+}
+
+func (m *tuiModel) handleReasonKey(key string) func() {
+	m.mode = tuiModeList
+	switch key {
+	case "1", "n":
+		return m.feedbackTask("downvote", "not_relevant")
+	case "2", "l":
+		return m.feedbackTask("downvote", "low_quality")
+	case "enter", "d":
+		return m.feedbackTask("downvote", "")
+	}
+	return nil
+}
+
+func (m *tuiModel) feedbackTask(vote, reason string) func() {
+	item, ok := m.selected()
+	if !ok {
+		return nil
+	}
+	m.status = "Saving feedback..."
+	return func() {
+		feedback, err := m.source.SetFeedback(item.ID, vote, reason)
+		if err != nil {
+			m.status = "Failed to save feedback: " + err.Error()
+			return
+		}
+		item.Feedback = feedback
+		m.status = "Feedback: " + formatFeedback(feedback)
+	}
+}
+
+func (m *tuiModel) readerTask() func() {
+	item, ok := m.selected()
+	if !ok {
+		return nil
+	}
+	m.status = "Loading markdown..."
+	return func() {
+		markdown, err := m.source.Markdown(item.ID)
+		if err != nil {
+			var pending *api.PaperMarkdownPendingError
+			if errors.As(err, &pending) {
+				m.status = pending.FriendlyMessage()
+				return
+			}
+			m.status = "Failed to load markdown: " + err.Error()
+			return
+		}
+		rendered := mdrender.Render(markdown, mdrender.Options{
+			Width:  max(m.width-2, 20),
+			Color:  m.color,
+			Escape: terminalSafeInline,
+		})
+		m.reader = strings.Split(strings.TrimRight(rendered, "\n"), "\n")
+		m.readerTop = 0
+		m.mode = tuiModeReader
+		m.status = ""
+	}
+}
+
+func (m *tuiModel) handleReaderKey(key string) {
+	page := max(m.height-2, 1)
+	last := max(len(m.reader)-page, 0)
+	switch key {
+	case "q", "esc":
+		m.mode = tuiModeList
+	case "j", "down", "enter":
+		m.readerTop++
+	case "k", "up":
+		m.readerTop--
+	case " ", "pgdown", "ctrl-d", "f":
+		m.readerTop += page
+	case "b", "pgup", "ctrl-u":
+		m.readerTop -= page
+	case "g", "home":
+		m.readerTop = 0
+	case "G", "end":
+		m.readerTop = last
+	}
+	m.readerTop = min(max(m.readerTop, 0), last)
+}
+
+func (m *tuiModel) bodyHeight() int {
+	return max(m.height-2, 1)
+}
+
+func (m *tuiModel) listHeight() int {
+	if m.width >= tuiSplitMinWidth {
+		return m.bodyHeight()
+	}
+	return max(m.bodyHeight()/2, 1)
+}
+
+// view renders the whole screen as exactly m.height lines.
+func (m *tuiModel) view() []string {
+	var body []string
+	switch m.mode {
+	case tuiModeProjects:
+		body = m.projectLines()
+	case tuiModeReader:
+		body = m.readerLines()
+	case tuiModeHelp:
+		body = m.helpLines()
+	default:
+		body = m.splitLines()
+	}
+
+	lines := make([]string, 0, m.height)
+	lines = append(lines, m.style(m.fit(m.headerText()), ansiReverse))
+	for i := 0; i < m.bodyHeight(); i++ {
+		if i < len(body) {
+			lines = append(lines, body[i])
+		} else {
+			lines = append(lines, strings.Repeat(" ", m.width))
+		}
+	}
+	lines = append(lines, m.fit(m.footerText()))
+	return lines[:max(m.height, 0)]
+}
+
+func (m *tuiModel) headerText() string {
+	if m.mode == tuiModeProjects {
+		return " pz tui — choose a project"
+	}
+	parts := []string{" pz tui — " + terminalSafeInline(m.projectName)}
+	count := fmt.Sprintf("%d loaded", len(m.items))
+	if m.hasMore {
+		count += "+"
+	}
+	parts = append(parts, count)
+	if m.mustRead {
+		parts = append(parts, "must-read only")
+	}
+	if m.query != "" {
+		parts = append(parts, "search: "+terminalSafeInline(m.query))
+	}
+	if m.filter != "" {
+		parts = append(parts, "filter: "+terminalSafeInline(m.filter))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (m *tuiModel) footerText() string {
+	switch m.mode {
+	case tuiModeSearch:
+		return "/" + terminalSafeInline(m.input) + "▏  enter: search (3+ chars) or filter  esc: cancel"
+	case tuiModeReason:
+		return "Downvote reason: 1 not relevant  2 low quality  enter: none  esc: cancel"
+	case tuiModeReader:
+		return "j/k: scroll  space/b: page  g/G: top/bottom  q: back"
+	}
+	if m.status != "" {
+		return terminalSafeInline(m.status)
+	}
+	if m.mode == tuiModeProjects {
+		return "j/k: move  enter: open  q: quit"
+	}
+	return "u: upvote  d: downvote  s: star  c: clear  /: search  m: must-read  enter: read  ?: help  q: quit"
+}
+
+func (m *tuiModel) projectLines() []string {
+	lines := make([]string, 0, len(m.projects))
+	for i, project := range m.projects {
+		line := m.fit("  " + terminalSafeInline(project.Name) + "  " + terminalSafeInline(project.ID))
+		if i == m.projectCursor {
+			line = m.highlight(line)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (m *tuiModel) readerLines() []string {
+	end := min(m.readerTop+m.bodyHeight(), len(m.reader))
+	lines := make([]string, 0, end-m.readerTop)
+	for _, line := range m.reader[m.readerTop:end] {
+		// Rendered markdown is already wrapped and escaped; reset styles so
+		// nothing bleeds into the padding.
+		lines = append(lines, " "+line+ansiReset+"\x1b[K")
+	}
+	return lines
+}
+
+func (m *tuiModel) helpLines() []string {
+	help := []string{
+		"",
+		"  j/k, arrows     move selection",
+		"  space, ctrl-d   page down (ctrl-u/pgup: page up)",
+		"  g/G             first/last loaded paper",
+		"  J/K             scroll the detail pane",
+		"  enter, o        read the paper's markdown",
+		"  u / s / c       upvote / star / clear feedback",
+		"  d               downvote (then 1: not relevant, 2: low quality)",
+		"  /               search: filters as you type; enter with 3+ chars searches the full feed",
+		"  esc             clear the filter, then the search",
+		"  m               toggle must-read only",
+		"  r               reload",
+		"  p               switch project",
+		"  q               quit",
+		"",
+		"  Press any key to return.",
+	}
+	lines := make([]string, 0, len(help))
+	for _, line := range help {
+		lines = append(lines, m.fit(line))
+	}
+	return lines
+}
+
+// splitLines lays the list and detail panes side by side on wide screens
+// and stacked on narrow ones.
+func (m *tuiModel) splitLines() []string {
+	height := m.bodyHeight()
+	if m.width >= tuiSplitMinWidth {
+		listWidth := m.width * 2 / 5
+		detailWidth := m.width - listWidth - 3
+		list := m.listLines(listWidth, height)
+		detail := m.detailLines(detailWidth, height)
+		lines := make([]string, height)
+		for i := range lines {
+			lines[i] = list[i] + " " + m.style("│", ansiDim) + " " + detail[i]
+		}
+		return lines
+	}
+
+	listHeight := m.listHeight()
+	lines := m.listLines(m.width, listHeight)
+	lines = append(lines, m.style(strings.Repeat("─", m.width), ansiDim))
+	return append(lines, m.detailLines(m.width, height-listHeight-1)...)
+}
+
+func (m *tuiModel) listLines(width, height int) []string {
+	visible := m.visible()
+	if m.cursor < m.listTop {
+		m.listTop = m.cursor
+	}
+	if m.cursor >= m.listTop+height {
+		m.listTop = m.cursor - height + 1
+	}
+
+	lines := make([]string, 0, height)
+	for row := m.listTop; row < len(visible) && len(lines) < height; row++ {
+		item := m.items[visible[row]]
+		marker := "○"
+		if item.RelevanceClass == 2 {
+			marker = "★"
+		}
+		if fb := feedbackMarker(item.Feedback); fb != "" {
+			marker += " " + fb
+		}
+		line := layout.PadRight(layout.Truncate("  "+marker+" "+terminalSafeInline(item.PaperTitle), width), width)
+		if row == m.cursor {
+			line = m.highlight(line)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		empty := "No papers."
+		if m.status != "" {
+			empty = ""
+		}
+		lines = append(lines, layout.PadRight(empty, width))
+	}
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return lines
+}
+
+func (m *tuiModel) detailLines(width, height int) []string {
+	var lines []string
+	if item, ok := m.selected(); ok {
+		lines = m.detailContent(*item, width)
+	}
+	m.detailTop = min(m.detailTop, max(len(lines)-height, 0))
+	if m.detailTop > 0 {
+		lines = lines[m.detailTop:]
+	}
+
+	out := make([]string, 0, height)
+	for _, line := range lines {
+		if len(out) == height {
+			break
+		}
+		out = append(out, line)
+	}
+	for len(out) < height {
+		out = append(out, strings.Repeat(" ", width))
+	}
+	return out
+}
+
+func (m *tuiModel) detailContent(item api.ProjectPaper, width int) []string {
+	var lines []string
+	add := func(text, code string) {
+		for _, line := range layout.Wrap(text, width) {
+			lines = append(lines, m.style(layout.PadRight(line, width), code))
+		}
+	}
+	section := func(title, text string) {
+		if strings.TrimSpace(text) == "" {
+			return
+		}
+		lines = append(lines, strings.Repeat(" ", width))
+		add(title, ansiBold)
+		for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+			add(terminalSafeInline(paragraph), "")
+		}
+	}
+
+	add(terminalSafeInline(item.PaperTitle), ansiBold)
+	add(joinDisplayParts(
+		authorNames(item.Paper.Authors),
+		paperListLabel(item.Paper),
+		formatTime(item.Paper.PublishedDate),
+	), ansiDim)
+	add(fmt.Sprintf("%s · feedback: %s · %s",
+		formatRelevance(item.RelevanceClass, item.RelevanceScore),
+		terminalSafeInline(formatFeedback(item.Feedback)),
+		terminalSafeInline(item.ID),
+	), ansiDim)
+	section("Note", item.PersonalizedNote)
+	section("Summary", item.Summary)
+	section("Abstract", item.Paper.Abstract)
+	return lines
+}
+
+func (m *tuiModel) fit(text string) string {
+	return layout.PadRight(layout.Truncate(text, m.width), m.width)
+}
+
+// highlight marks the selected row. Rows start with a two-cell gutter that
+// carries the marker when color is off.
+func (m *tuiModel) highlight(line string) string {
+	if !m.color {
+		return ">" + strings.TrimPrefix(line, " ")
+	}
+	return ansiReverse + line + ansiReset
+}
+
+func (m *tuiModel) style(text, code string) string {
+	if !m.color || code == "" {
+		return text
+	}
+	return code + text + ansiReset
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/paperzilla/pz/internal/api"
+)
+
+type fakeTUISource struct {
+	items    []api.ProjectPaper
+	pages    []string
+	feedback map[string]string
+	markdown string
+}
+
+func newFakeTUISource(count int) *fakeTUISource {
+	source := &fakeTUISource{feedback: map[string]string{}}
+	for i := 0; i < count; i++ {
+		class := 1
+		if i%2 == 0 {
+			class = 2
+		}
+		source.items = append(source.items, api.ProjectPaper{
+			ID:             fmt.Sprintf("pp-%d", i),
+			PaperTitle:     fmt.Sprintf("Paper %d about graphs", i),
+			RelevanceClass: class,
+			Summary:        "Summary text",
+		})
+	}
+	return source
+}
+
+func (s *fakeTUISource) Projects() ([]api.Project, error) {
+	return []api.Project{{ID: "proj-1", Name: "Graph Learning"}, {ID: "proj-2", Name: "Other"}}, nil
+}
+
+func (s *fakeTUISource) Page(projectID, query string, mustRead bool, offset, limit int) ([]api.ProjectPaper, bool, error) {
+	s.pages = append(s.pages, fmt.Sprintf("%s q=%q must=%t offset=%d", projectID, query, mustRead, offset))
+	var matching []api.ProjectPaper
+	for _, item := range s.items {
+		if mustRead && item.RelevanceClass != 2 {
+			continue
+		}
+		if query != "" && !strings.Contains(item.PaperTitle, query) {
+			continue
+		}
+		matching = append(matching, item)
+	}
+	end := min(offset+limit, len(matching))
+	if offset > end {
+		return nil, false, nil
+	}
+	return matching[offset:end], end < len(matching), nil
+}
+
+func (s *fakeTUISource) SetFeedback(ref, vote, reason string) (*api.Feedback, error) {
+	s.feedback[ref] = strings.TrimSpace(vote + " " + reason)
+	if vote == "clear" {
+		return nil, nil
+	}
+	return &api.Feedback{Vote: vote, DownvoteReason: reason}, nil
+}
+
+func (s *fakeTUISource) Markdown(ref string) (string, error) {
+	return s.markdown, nil
+}
+
+func startTUIModel(t *testing.T, source *fakeTUISource) *tuiModel {
+	t.Helper()
+	model := newTUIModel(source, false)
+	model.width, model.height = 80, 20
+	model.init("proj-1")()
+	return model
+}
+
+func pressKeys(model *tuiModel, keys ...string) {
+	for _, key := range keys {
+		if task := model.handleKey(key); task != nil {
+			task()
+		}
+	}
+}
+
+func TestTUILoadsProjectFeedAndRendersPanes(t *testing.T) {
+	source := newFakeTUISource(3)
+	model := startTUIModel(t, source)
+
+	view := strings.Join(model.view(), "\n")
+	if len(model.view()) != 20 {
+		t.Fatalf("view has %d lines, want 20", len(model.view()))
+	}
+	for _, want := range []string{"Graph Learning", "> ★ Paper 0 about graphs", "  ○ Paper 1 about graphs", "Summary text"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestTUILoadsNextPageNearTheEndOfTheList(t *testing.T) {
+	source := newFakeTUISource(tuiPageSize + 10)
+	model := startTUIModel(t, source)
+
+	for i := 0; i < tuiPageSize-tuiPrefetchRows; i++ {
+		pressKeys(model, "j")
+	}
+
+	if len(model.items) != tuiPageSize+10 {
+		t.Fatalf("loaded %d items, want %d", len(model.items), tuiPageSize+10)
+	}
+	if model.hasMore {
+		t.Fatal("hasMore should be false after the last page")
+	}
+	if got := source.pages[len(source.pages)-1]; got != fmt.Sprintf(`proj-1 q="" must=false offset=%d`, tuiPageSize) {
+		t.Fatalf("last page request = %s", got)
+	}
+}
+
+func TestTUIFeedbackKeysUpdateSelectedPaper(t *testing.T) {
+	source := newFakeTUISource(3)
+	model := startTUIModel(t, source)
+
+	pressKeys(model, "u", "j", "d", "1", "j", "s", "c")
+
+	want := map[string]string{"pp-0": "upvote", "pp-1": "downvote not_relevant", "pp-2": "clear"}
+	for ref, vote := range want {
+		if source.feedback[ref] != vote {
+			t.Errorf("feedback[%s] = %q, want %q", ref, source.feedback[ref], vote)
+		}
+	}
+	if model.items[1].Feedback == nil || model.items[1].Feedback.DownvoteReason != "not_relevant" {
+		t.Fatalf("item feedback = %+v", model.items[1].Feedback)
+	}
+	if model.items[2].Feedback != nil {
+		t.Fatalf("cleared item feedback = %+v", model.items[2].Feedback)
+	}
+	if !strings.Contains(strings.Join(model.view(), "\n"), "[↓] Paper 1") {
+		t.Fatal("list should show the downvote marker")
+	}
+}
+
+func TestTUISearchFiltersAsYouTypeThenSearchesServer(t *testing.T) {
+	source := newFakeTUISource(12)
+	model := startTUIModel(t, source)
+
+	pressKeys(model, "/", "1")
+	if got := len(model.visible()); got != 3 {
+		t.Fatalf("visible while typing = %d, want 3 (1, 10, 11)", got)
+	}
+
+	pressKeys(model, "enter")
+	if model.filter != "1" || model.query != "" {
+		t.Fatalf("short input should stay a local filter: filter=%q query=%q", model.filter, model.query)
+	}
+
+	pressKeys(model, "esc", "/", "P", "a", "p", "e", "r", " ", "1", "1", "enter")
+	if model.query != "Paper 11" {
+		t.Fatalf("query = %q", model.query)
+	}
+	if got := source.pages[len(source.pages)-1]; got != `proj-1 q="Paper 11" must=false offset=0` {
+		t.Fatalf("last page request = %s", got)
+	}
+	if len(model.items) != 1 {
+		t.Fatalf("items = %d, want 1", len(model.items))
+	}
+
+	pressKeys(model, "esc")
+	if model.query != "" || len(model.items) != 12 {
+		t.Fatalf("esc should return to the full feed: query=%q items=%d", model.query, len(model.items))
+	}
+}
+
+func TestTUIMustReadToggleReloads(t *testing.T) {
+	source := newFakeTUISource(4)
+	model := startTUIModel(t, source)
+
+	pressKeys(model, "m")
+	if !model.mustRead || len(model.items) != 2 {
+		t.Fatalf("mustRead=%t items=%d", model.mustRead, len(model.items))
+	}
+}
+
+func TestTUIReaderRendersMarkdown(t *testing.T) {
+	source := newFakeTUISource(1)
+	source.markdown = "# Heading\n\nBody with $\\alpha$.\n"
+	model := startTUIModel(t, source)
+
+	pressKeys(model, "enter")
+	if model.mode != tuiModeReader {
+		t.Fatalf("mode = %v, want reader (status %q)", model.mode, model.status)
+	}
+	view := strings.Join(model.view(), "\n")
+	if !strings.Contains(view, "Heading") || !strings.Contains(view, "Body with α.") {
+		t.Fatalf("reader view:\n%s", view)
+	}
+
+	pressKeys(model, "q")
+	if model.mode != tuiModeList {
+		t.Fatalf("mode = %v, want list", model.mode)
+	}
+}
+
+func TestTUIEscapesServerText(t *testing.T) {
+	source := newFakeTUISource(1)
+	source.items[0].PaperTitle = "Evil\x1b]52;c;payload\a"
+	source.items[0].Summary = "line\x1b[2J"
+	model := startTUIModel(t, source)
+
+	view := strings.Join(model.view(), "\n")
+	if strings.ContainsRune(view, '\x1b') || strings.ContainsRune(view, '\a') {
+		t.Fatalf("view contains raw control characters: %q", view)
+	}
+	if !strings.Contains(view, `Evil\x1b]52;c;payload\x07`) {
+		t.Fatalf("view = %q", view)
+	}
+}
+
+func TestTUIWideLayoutSplitsPanes(t *testing.T) {
+	model := startTUIModel(t, newFakeTUISource(2))
+	model.width = 120
+
+	lines := model.view()
+	if !strings.Contains(lines[1], "│") || !strings.Contains(lines[1], "Paper 0 about graphs") {
+		t.Fatalf("first body line = %q", lines[1])
+	}
+}
+
+func TestParseTUIKeys(t *testing.T) {
+	got := parseTUIKeys([]byte("j\x1b[A\x1b[6~\r\x7f\x03é\x1b"))
+	want := []string{"j", "up", "pgdown", "enter", "backspace", "ctrl-c", "é", "esc"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("parseTUIKeys = %q, want %q", got, want)
+	}
+}
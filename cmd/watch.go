@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/config"
+	"github.com/paperzilla/pz/internal/notify"
+	"github.com/paperzilla/pz/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultWatchInterval = 15 * time.Minute
+	watchHookTimeout     = time.Minute
+)
+
+func init() {
+	watchCmd.Flags().Duration("interval", defaultWatchInterval, "Time between polls (at least 1m)")
+	watchCmd.Flags().BoolP("must-read", "m", false, "Only report must-read papers")
+	watchCmd.Flags().Bool("jsonl", false, "Print one JSON object per paper")
+	watchCmd.Flags().String("exec", "", "Run this command for each paper with the paper's JSON on stdin")
+	watchCmd.Flags().Bool("notify", false, "Also post new papers to the sinks set up for pz notify")
+	watchCmd.Flags().Bool("once", false, "Poll once and exit")
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [project-id...]",
+	Short: "Report newly ready recommendations as they arrive",
+	Long: "Poll project feeds and report each newly ready recommendation once.\n\n" +
+		"Without project IDs all projects are watched. The first run only records\n" +
+		"where each feed ends; papers that become ready after that are reported.\n" +
+		"Cursors are kept per account, project and --must-read in\n" +
+		"~/.paperzilla/watch.json (or $PZ_WATCH_STATE_PATH), so a restarted watcher\n" +
+		"picks up where it stopped.\n\n" +
+		"With --exec the command runs once per paper, without a shell, with the\n" +
+		"paper's JSON on stdin and PZ_PROJECT_ID and PZ_PROJECT_PAPER_ID set.\n" +
+		"With --notify new papers are also posted to Slack, Mattermost or webhook\n" +
+		"sinks (see pz notify).\n\n" +
+		"Failed polls are retried with backoff. Stop with Ctrl-C or SIGTERM.",
+	Example: `  pz watch
+  pz watch <project-id> --must-read --interval 5m
+  pz watch --jsonl >> papers.jsonl
+  pz watch --must-read --exec "notify.sh"
+  pz watch --notify`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		interval, _ := cmd.Flags().GetDuration("interval")
+		mustRead, _ := cmd.Flags().GetBool("must-read")
+		jsonl, _ := cmd.Flags().GetBool("jsonl")
+		hook, _ := cmd.Flags().GetString("exec")
+		notifyOut, _ := cmd.Flags().GetBool("notify")
+		once, _ := cmd.Flags().GetBool("once")
+
+		if interval < watch.MinInterval {
+			return fmt.Errorf("invalid watch request: --interval must be at least %s", watch.MinInterval)
+		}
+		hookArgv := strings.Fields(hook)
+		if hook != "" && len(hookArgv) == 0 {
+			return fmt.Errorf("invalid watch request: --exec is empty")
+		}
+		if jsonl && len(hookArgv) > 0 {
+			return fmt.Errorf("invalid watch request: use either --jsonl or --exec")
+		}
+
+		var sinks []config.NotifySink
+		if notifyOut {
+			loaded, err := loadNotifySinks()
+			if err != nil {
+				return err
+			}
+			sinks = loaded
+		}
+
+		tokens, err := loadRequiredAuth()
+		if err != nil {
+			return err
+		}
+
+		projects, err := withBackgroundAuth(&tokens, func(at string) ([]api.Project, error) {
+			return api.FetchProjects(at)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch projects: %w", err)
+		}
+		names, ids, err := watchedProjects(projects, args)
+		if err != nil {
+			return err
+		}
+
+		state, err := watch.LoadState(config.WatchStatePath())
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		errOut := cmd.ErrOrStderr()
+		logf := func(format string, args ...any) {
+			fmt.Fprintf(errOut, "%s %s\n", time.Now().Format("2006-01-02 15:04:05"), terminalSafeInline(fmt.Sprintf(format, args...)))
+		}
+
+		var notifier *notify.Notifier
+		if notifyOut {
+			notified, err := notify.LoadState(config.NotifyStatePath())
+			if err != nil {
+				return err
+			}
+			notifier = newNotifier(sinks, notified, logf)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if notifier != nil {
+			if err := notifier.RetryPending(ctx); err != nil {
+				logf("queued notifications still failing: %v", err)
+			}
+		}
+
+		w := &watch.Watcher{
+			Source:       &watchSource{tokens: &tokens},
+			State:        state,
+			ProjectIDs:   ids,
+			Interval:     interval,
+			Account:      tokens.Account(),
+			MustReadOnly: mustRead,
+			Logf:         logf,
+			Emit: func(event watch.Event) error {
+				if notifier != nil {
+					msg := notificationMessage(event.ProjectID, names[event.ProjectID], event.Item)
+					if err := notifier.Notify(ctx, msg); err != nil {
+						logf("notification failed, queued for retry: %v", err)
+					}
+				}
+				switch {
+				case len(hookArgv) > 0:
+					runWatchHook(ctx, hookArgv, event, out, errOut, logf)
+					return nil
+				case jsonl:
+					return writeWatchEventJSON(out, names[event.ProjectID], event)
+				default:
+					return writeWatchEvent(out, names[event.ProjectID], event)
+				}
+			},
+		}
+
+		if once {
+			return w.PollOnce(ctx)
+		}
+		logf("watching %d project(s) every %s", len(ids), interval)
+		if err := w.Run(ctx); err != nil {
+			return err
+		}
+		logf("stopped")
+		return nil
+	},
+}
+
+// watchedProjects maps project IDs to names and checks that requested
+// projects exist.
+func watchedProjects(projects []api.Project, requested []string) (map[string]string, []string, error) {
+	names := make(map[string]string, len(projects))
+	var ids []string
+	for _, project := range projects {
+		names[project.ID] = project.Name
+		if len(requested) == 0 {
+			ids = append(ids, project.ID)
+		}
+	}
+	for _, id := range requested {
+		if _, ok := names[id]; !ok {
+			return nil, nil, fmt.Errorf("project %s not found", id)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, nil, fmt.Errorf("no projects to watch")
+	}
+	return names, ids, nil
+}
+
+type watchSource struct {
+	tokens *config.Tokens
+}
+
+func (s *watchSource) Feed(projectID string, opts api.FeedOptions) (api.FeedResponse, error) {
+	return withBackgroundAuth(s.tokens, func(at string) (api.FeedResponse, error) {
+		return api.FetchFeed(at, projectID, opts)
+	})
+}
+
+func writeWatchEvent(out io.Writer, projectName string, event watch.Event) error {
+	p := event.Item
+	relevance := "○ Related"
+	if p.RelevanceClass == 2 {
+		relevance = "★ Must Read"
+	}
+	_, err := fmt.Fprintf(out, "%s  %s  %s\n", formatTime(p.ReadyAt), relevance, joinDisplayParts(
+		p.PaperTitle,
+		projectName,
+		p.ID,
+	))
+	return err
+}
+
+type watchEventJSON struct {
+	ProjectID   string           `json:"project_id"`
+	ProjectName string           `json:"project_name"`
+	Item        api.ProjectPaper `json:"item"`
+}
+
+func writeWatchEventJSON(out io.Writer, projectName string, event watch.Event) error {
+	data, err := json.Marshal(watchEventJSON{ProjectID: event.ProjectID, ProjectName: projectName, Item: event.Item})
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	_, err = fmt.Fprintln(out, string(data))
+	return err
+}
+
+// runWatchHook runs the --exec command for one paper. A failing hook is
+// logged and does not stop the watcher.
+func runWatchHook(ctx context.Context, argv []string, event watch.Event, out, errOut io.Writer, logf func(string, ...any)) {
+	data, err := json.Marshal(event.Item)
+	if err != nil {
+		logf("failed to encode paper %s: %v", event.Item.ID, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, watchHookTimeout)
+	defer cancel()
+
+	hook := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	hook.Stdin = bytes.NewReader(data)
+	hook.Stdout = out
+	hook.Stderr = errOut
+	hook.Env = append(os.Environ(), "PZ_PROJECT_ID="+event.ProjectID, "PZ_PROJECT_PAPER_ID="+event.Item.ID)
+	if err := hook.Run(); err != nil {
+		logf("hook failed for %s: %v", event.Item.ID, err)
+	}
+}
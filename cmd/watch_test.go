@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+func TestWatchOnceReportsNewPapers(t *testing.T) {
+	items := []string{`{"id":"pp-1","paper_title":"Old","relevance_class":2,"ready_at":"2026-01-01T00:00:00Z"}`}
+	var sinces []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/projects":
+			w.Write([]byte(`[{"id":"proj-1","name":"Graph Learning"}]`))
+		case "/api/projects/proj-1/feed":
+			sinces = append(sinces, r.URL.Query().Get("since"))
+			w.Write([]byte(`{"items":[` + strings.Join(items, ",") + `],"total":` + strconv.Itoa(len(items)) + `}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("PZ_API_URL", server.URL)
+	t.Setenv("PZ_WATCH_STATE_PATH", filepath.Join(t.TempDir(), "watch.json"))
+	writeTestTokens(t)
+
+	run := func(flags ...string) (string, string) {
+		cmd := &cobra.Command{}
+		cmd.Flags().Duration("interval", defaultWatchInterval, "")
+		cmd.Flags().BoolP("must-read", "m", false, "")
+		cmd.Flags().Bool("jsonl", false, "")
+		cmd.Flags().String("exec", "", "")
+		cmd.Flags().Bool("notify", false, "")
+		cmd.Flags().Bool("once", false, "")
+		if err := cmd.Flags().Parse(append(flags, "--once")); err != nil {
+			t.Fatalf("Parse: %v", err)
+		}
+		var stdout, stderr bytes.Buffer
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		if err := watchCmd.RunE(cmd, nil); err != nil {
+			t.Fatalf("RunE: %v", err)
+		}
+		return stdout.String(), stderr.String()
+	}
+
+	if out, log := run(); out != "" || !strings.Contains(log, "watching project proj-1 from 2026-01-01T00:00:00Z") {
+		t.Fatalf("first run stdout = %q, stderr = %q", out, log)
+	}
+
+	items = append(items, `{"id":"pp-2","paper_title":"New \u001b[31mpaper","relevance_class":2,"ready_at":"2026-01-02T10:30:00Z"}`)
+	if out, _ := run(); out != "2026-01-02 10:30  ★ Must Read  New \\x1b[31mpaper · Graph Learning · pp-2\n" {
+		t.Fatalf("second run stdout = %q", out)
+	}
+	if sinces[1] != "2026-01-01T00:00:00Z" {
+		t.Fatalf("since = %q", sinces)
+	}
+
+	items = append(items, `{"id":"pp-3","paper_title":"Newer","ready_at":"2026-01-03T00:00:00Z"}`)
+	out, _ := run("--jsonl")
+	var event watchEventJSON
+	if err := json.Unmarshal([]byte(out), &event); err != nil || event.ProjectName != "Graph Learning" || event.Item.ID != "pp-3" {
+		t.Fatalf("jsonl = %q, %v", out, err)
+	}
+}
+
+func TestWatchRejectsBadFlagsAndUnknownProjects(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().Duration("interval", 30*time.Second, "")
+	cmd.Flags().BoolP("must-read", "m", false, "")
+	cmd.Flags().Bool("jsonl", false, "")
+	cmd.Flags().String("exec", "", "")
+	cmd.Flags().Bool("once", false, "")
+	if err := watchCmd.RunE(cmd, nil); err == nil || !strings.Contains(err.Error(), "at least 1m") {
+		t.Fatalf("err = %v", err)
+	}
+
+	if _, _, err := watchedProjects([]api.Project{{ID: "p1"}}, []string{"p2"}); err == nil || err.Error() != "project p2 not found" {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestRunWatchHookPassesPaperOnStdin(t *testing.T) {
+	var stdout bytes.Buffer
+	var logs []string
+	logf := func(format string, args ...any) { logs = append(logs, format) }
+	event := watch.Event{ProjectID: "proj-1", Item: api.ProjectPaper{ID: "pp-1", PaperTitle: "Hooked"}}
+
+	runWatchHook(context.Background(), []string{"sh", "-c", `cat; echo " $PZ_PROJECT_ID $PZ_PROJECT_PAPER_ID"`}, event, &stdout, &stdout, logf)
+	if !strings.Contains(stdout.String(), `"paper_title":"Hooked"`) || !strings.HasSuffix(stdout.String(), " proj-1 pp-1\n") || len(logs) != 0 {
+		t.Fatalf("stdout = %q, logs = %q", stdout.String(), logs)
+	}
+
+	runWatchHook(context.Background(), []string{"false"}, event, &stdout, &stdout, logf)
+	if len(logs) != 1 {
+		t.Fatalf("failing hook should be logged, logs = %q", logs)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -791,3 +792,78 @@ func TestClearProjectPaperFeedback(t *testing.T) {
 		t.Fatalf("ClearProjectPaperFeedback: %v", err)
 	}
 }
+
+func TestUpdateProjectSendsOnlySetFields(t *testing.T) {
+	server := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/api/projects/proj-1" {
+			t.Fatalf("request = %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"email_frequency":"daily","positive_keywords":[]}` {
+			t.Fatalf("body = %s", body)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"id": "proj-1", "email_frequency": "daily"})
+	})
+	defer server.Close()
+
+	frequency := "daily"
+	project, err := UpdateProject("my_token", "proj-1", ProjectInput{EmailFrequency: &frequency, PositiveKeywords: &[]string{}})
+	if err != nil {
+		t.Fatalf("UpdateProject: %v", err)
+	}
+	if project.EmailFrequency != "daily" || project.NegativeKeywords == nil {
+		t.Fatalf("project = %#v", project)
+	}
+}
+
+func TestAPIErrorParsesFieldErrors(t *testing.T) {
+	err := parseAPIError(422, []byte(`{"detail":[{"loc":["body","name"],"msg":"field required"},{"loc":["query","limit"],"msg":"too large"}]}`))
+	want := []FieldError{{Field: "name", Message: "field required"}, {Field: "query.limit", Message: "too large"}}
+	if !reflect.DeepEqual(err.FieldErrors, want) {
+		t.Fatalf("FieldErrors = %#v", err.FieldErrors)
+	}
+	if err.Error() != "HTTP 422: name: field required; query.limit: too large" {
+		t.Fatalf("Error() = %q", err.Error())
+	}
+}
+
+func TestFetchCategoriesQueryParams(t *testing.T) {
+	server := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/categories" {
+			t.Fatalf("path = %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("source_id") != "1" || q.Get("search") != "cs.LG" {
+			t.Errorf("query = %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`[{"id":12,"code":"cs.LG","name":"Machine Learning","source_id":1,"source_name":"arXiv"}]`))
+	})
+	defer server.Close()
+
+	categories, err := FetchCategories("my_token", CategoryOptions{SourceID: 1, Search: " cs.LG "})
+	if err != nil {
+		t.Fatalf("FetchCategories: %v", err)
+	}
+	want := []Category{{ID: 12, Code: "cs.LG", Name: "Machine Learning", SourceID: 1, SourceName: "arXiv"}}
+	if !reflect.DeepEqual(categories, want) {
+		t.Fatalf("categories = %#v", categories)
+	}
+}
+
+func TestFetchSources(t *testing.T) {
+	server := startTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/sources" || r.URL.RawQuery != "" {
+			t.Fatalf("request = %s", r.URL)
+		}
+		w.Write([]byte(`[{"id":1,"name":"arXiv","base_url":"https://arxiv.org"}]`))
+	})
+	defer server.Close()
+
+	sources, err := FetchSources("my_token")
+	if err != nil {
+		t.Fatalf("FetchSources: %v", err)
+	}
+	if len(sources) != 1 || sources[0].Name != "arXiv" || sources[0].BaseURL != "https://arxiv.org" {
+		t.Fatalf("sources = %#v", sources)
+	}
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Category is a subject category within a source, such as cs.LG on arXiv.
+type Category struct {
+	ID         int    `json:"id"`
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	SourceID   int    `json:"source_id"`
+	SourceName string `json:"source_name"`
+}
+
+type CategoryOptions struct {
+	SourceID int
+	Search   string
+}
+
+// FetchSources lists the paper sources projects can watch, such as arXiv.
+func FetchSources(accessToken string) ([]ProjectSource, error) {
+	body, err := doRequest("GET", "/api/sources", nil, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []ProjectSource
+	if err := json.Unmarshal(body, &sources); err != nil {
+		return nil, err
+	}
+
+	return sources, nil
+}
+
+func FetchCategories(accessToken string, opts CategoryOptions) ([]Category, error) {
+	params := url.Values{}
+	if opts.SourceID > 0 {
+		params.Set("source_id", fmt.Sprintf("%d", opts.SourceID))
+	}
+	if search := strings.TrimSpace(opts.Search); search != "" {
+		params.Set("search", search)
+	}
+
+	path := "/api/categories"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
+	body, err := doRequest("GET", path, nil, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var categories []Category
+	if err := json.Unmarshal(body, &categories); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
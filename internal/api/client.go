@@ -31,6 +31,11 @@ func SetClientVersion(version string) {
 	clientVersion = trimmed
 }
 
+// UserAgent is sent with every request, including PDF downloads.
+func UserAgent() string {
+	return supportedUserAgent()
+}
+
 func supportedUserAgent() string {
 	return "paperzilla-pz/" + clientVersion
 }
@@ -19,6 +19,15 @@ type APIError struct {
 	UpgradeDestination string
 	UpgradePath        string
 	Body               string
+	// FieldErrors are per-field validation errors, when the server sent them.
+	FieldErrors []FieldError
+}
+
+// FieldError is a validation error for one request field, such as
+// "email_time" or "categories.0.weight".
+type FieldError struct {
+	Field   string
+	Message string
 }
 
 func (e *APIError) Error() string {
@@ -92,6 +101,17 @@ func parseAPIError(statusCode int, body []byte) *APIError {
 					err.Detail = strings.TrimSpace(string(data))
 				}
 			}
+		case []any:
+			err.FieldErrors = parseFieldErrors(detail)
+			if len(err.FieldErrors) > 0 {
+				messages := make([]string, len(err.FieldErrors))
+				for i, fieldErr := range err.FieldErrors {
+					messages[i] = fieldErr.Field + ": " + fieldErr.Message
+				}
+				err.Detail = strings.Join(messages, "; ")
+			} else if data, marshalErr := json.Marshal(detail); marshalErr == nil {
+				err.Detail = strings.TrimSpace(string(data))
+			}
 		case nil:
 		default:
 			if data, marshalErr := json.Marshal(detail); marshalErr == nil {
@@ -102,3 +122,30 @@ func parseAPIError(statusCode int, body []byte) *APIError {
 
 	return err
 }
+
+// parseFieldErrors reads validation errors shaped like
+// {"loc": ["body", "name"], "msg": "..."}. The leading "body" is dropped.
+func parseFieldErrors(detail []any) []FieldError {
+	var fieldErrors []FieldError
+	for _, item := range detail {
+		entry, ok := item.(map[string]any)
+		if !ok {
+			return nil
+		}
+		message, _ := entry["msg"].(string)
+		loc, _ := entry["loc"].([]any)
+		if strings.TrimSpace(message) == "" {
+			return nil
+		}
+		var path []string
+		for i, part := range loc {
+			text := fmt.Sprint(part)
+			if i == 0 && text == "body" {
+				continue
+			}
+			path = append(path, text)
+		}
+		fieldErrors = append(fieldErrors, FieldError{Field: strings.Join(path, "."), Message: strings.TrimSpace(message)})
+	}
+	return fieldErrors
+}
@@ -84,3 +84,63 @@ func (p *Project) normalizeMetadata() {
 		p.Categories = []ProjectCategory{}
 	}
 }
+
+// ProjectCategoryInput selects a category by ID with a matching weight.
+type ProjectCategoryInput struct {
+	ID     int     `json:"id"`
+	Weight float64 `json:"weight"`
+}
+
+// ProjectInput holds editable project fields. Nil fields are omitted, so an
+// update only changes what is set.
+type ProjectInput struct {
+	Name                *string                 `json:"name,omitempty"`
+	InterestDescription *string                 `json:"interest_description,omitempty"`
+	Mode                *string                 `json:"mode,omitempty"`
+	Visibility          *string                 `json:"visibility,omitempty"`
+	EmailFrequency      *string                 `json:"email_frequency,omitempty"`
+	EmailTime           *string                 `json:"email_time,omitempty"`
+	MaxCandidates       *int                    `json:"max_candidates,omitempty"`
+	MaxPapersPerDigests *int                    `json:"max_papers_per_digests,omitempty"`
+	PositiveKeywords    *[]string               `json:"positive_keywords,omitempty"`
+	NegativeKeywords    *[]string               `json:"negative_keywords,omitempty"`
+	SourceIDs           *[]int                  `json:"source_ids,omitempty"`
+	Categories          *[]ProjectCategoryInput `json:"categories,omitempty"`
+}
+
+func CreateProject(accessToken string, input ProjectInput) (Project, error) {
+	body, err := doRequest("POST", "/api/projects", input, accessToken)
+	if err != nil {
+		return Project{}, err
+	}
+
+	var project Project
+	if err := json.Unmarshal(body, &project); err != nil {
+		return Project{}, err
+	}
+
+	project.normalizeMetadata()
+	return project, nil
+}
+
+func UpdateProject(accessToken, id string, input ProjectInput) (Project, error) {
+	path := fmt.Sprintf("/api/projects/%s", id)
+	body, err := doRequest("PATCH", path, input, accessToken)
+	if err != nil {
+		return Project{}, err
+	}
+
+	var project Project
+	if err := json.Unmarshal(body, &project); err != nil {
+		return Project{}, err
+	}
+
+	project.normalizeMetadata()
+	return project, nil
+}
+
+func DeleteProject(accessToken, id string) error {
+	path := fmt.Sprintf("/api/projects/%s", id)
+	_, err := doRequest("DELETE", path, nil, accessToken)
+	return err
+}
@@ -0,0 +1,363 @@
+package authors
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Name is a personal name split into its bibliographic parts. Particle holds
+// lowercase-style prefixes such as "van" or "von der" that belong to the
+// family name but are ignored when sorting.
+type Name struct {
+	Given    string
+	Particle string
+	Family   string
+	Suffix   string
+}
+
+var particles = map[string]bool{
+	"al": true, "ap": true, "bin": true, "binti": true, "da": true, "das": true,
+	"de": true, "dei": true, "del": true, "della": true, "den": true, "der": true,
+	"des": true, "di": true, "do": true, "dos": true, "du": true, "el": true,
+	"la": true, "le": true, "lo": true, "st.": true, "ten": true, "ter": true,
+	"van": true, "vande": true, "vander": true, "von": true, "y": true, "zu": true,
+}
+
+var suffixes = map[string]bool{
+	"jr": true, "jr.": true, "sr": true, "sr.": true, "jnr": true, "snr": true,
+	"ii": true, "iii": true, "iv": true,
+	"phd": true, "ph.d.": true, "md": true, "m.d.": true,
+}
+
+// commonChineseSurnames backs the family-first heuristic for romanized names
+// like "Li Fei-Fei", where the hyphenated pinyin given name comes last.
+var commonChineseSurnames = map[string]bool{
+	"bai": true, "cai": true, "cao": true, "chen": true, "cheng": true, "cui": true,
+	"deng": true, "ding": true, "dong": true, "du": true, "fan": true, "fang": true,
+	"feng": true, "gao": true, "gu": true, "guo": true, "han": true, "he": true,
+	"hu": true, "huang": true, "jiang": true, "jin": true, "kong": true, "lei": true,
+	"li": true, "liang": true, "lin": true, "liu": true, "lu": true, "luo": true,
+	"ma": true, "mao": true, "meng": true, "pan": true, "peng": true, "qian": true,
+	"qin": true, "ren": true, "shen": true, "song": true, "su": true, "sun": true,
+	"tang": true, "tian": true, "wang": true, "wei": true, "wu": true, "xia": true,
+	"xiao": true, "xie": true, "xu": true, "xue": true, "yan": true, "yang": true,
+	"yao": true, "ye": true, "yu": true, "yuan": true, "zeng": true, "zhang": true,
+	"zhao": true, "zheng": true, "zhou": true, "zhu": true,
+}
+
+var pinyinInitials = []string{
+	"zh", "ch", "sh", "b", "p", "m", "f", "d", "t", "n", "l", "g", "k", "h",
+	"j", "q", "x", "r", "z", "c", "s", "y", "w",
+}
+
+var pinyinFinals = map[string]bool{
+	"a": true, "o": true, "e": true, "ai": true, "ei": true, "ao": true, "ou": true,
+	"an": true, "en": true, "ang": true, "eng": true, "ong": true, "er": true,
+	"i": true, "ia": true, "ie": true, "iao": true, "iu": true, "ian": true,
+	"in": true, "iang": true, "ing": true, "iong": true, "u": true, "ua": true,
+	"uo": true, "uai": true, "ui": true, "uan": true, "un": true, "uang": true,
+	"ue": true, "v": true, "ve": true,
+}
+
+// Parse splits a display name into given names, particles, family name and
+// suffix. It understands "Family, Given" comma forms, trailing suffixes such
+// as "Jr." or "III", family-first CJK names and a narrow set of romanized
+// Chinese names. Unparseable input lands in Family so callers always have a
+// non-empty surname for non-empty input.
+func Parse(raw string) Name {
+	raw = strings.Join(strings.Fields(raw), " ")
+	if raw == "" {
+		return Name{}
+	}
+
+	if strings.Contains(raw, ",") {
+		if name, ok := parseCommaForm(raw); ok {
+			return name
+		}
+	}
+
+	tokens := strings.Fields(raw)
+	var suffix []string
+	for len(tokens) > 1 && isSuffix(tokens[len(tokens)-1]) {
+		suffix = append([]string{tokens[len(tokens)-1]}, suffix...)
+		tokens = tokens[:len(tokens)-1]
+	}
+	name := parseTokens(tokens)
+	name.Suffix = strings.Join(suffix, " ")
+	return name
+}
+
+func parseCommaForm(raw string) (Name, bool) {
+	parts := strings.Split(raw, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	// "John Smith, Jr." keeps the natural order with a detached suffix.
+	allSuffixes := true
+	for _, part := range parts[1:] {
+		if !isSuffix(part) {
+			allSuffixes = false
+			break
+		}
+	}
+	if allSuffixes {
+		name := parseTokens(strings.Fields(parts[0]))
+		name.Suffix = strings.Join(nonEmpty(parts[1:]), " ")
+		return name, name.Family != ""
+	}
+
+	family := parts[0]
+	var given []string
+	var suffix []string
+	for _, part := range parts[1:] {
+		if isSuffix(part) {
+			suffix = append(suffix, part)
+		} else if part != "" {
+			given = append(given, part)
+		}
+	}
+	if family == "" {
+		return Name{}, false
+	}
+
+	familyTokens := strings.Fields(family)
+	var particle []string
+	for len(familyTokens) > 1 && isParticle(familyTokens[0]) {
+		particle = append(particle, familyTokens[0])
+		familyTokens = familyTokens[1:]
+	}
+
+	// BibTeX-style "Beethoven, Ludwig van" keeps the particle after the given name.
+	givenTokens := strings.Fields(strings.Join(given, " "))
+	for len(givenTokens) > 1 && isParticle(givenTokens[len(givenTokens)-1]) && len(particle) == 0 {
+		particle = append([]string{givenTokens[len(givenTokens)-1]}, particle...)
+		givenTokens = givenTokens[:len(givenTokens)-1]
+	}
+
+	return Name{
+		Given:    strings.Join(givenTokens, " "),
+		Particle: strings.Join(particle, " "),
+		Family:   strings.Join(familyTokens, " "),
+		Suffix:   strings.Join(suffix, " "),
+	}, true
+}
+
+func parseTokens(tokens []string) Name {
+	switch {
+	case len(tokens) == 0:
+		return Name{}
+	case len(tokens) == 1:
+		return Name{Family: tokens[0]}
+	case isCJK(tokens[0]):
+		return Name{Family: tokens[0], Given: strings.Join(tokens[1:], " ")}
+	case isRomanizedChineseFamilyFirst(tokens):
+		return Name{Family: tokens[0], Given: tokens[1]}
+	}
+
+	familyStart := len(tokens) - 1
+	for familyStart > 1 && isLowercaseParticle(tokens[familyStart-1]) {
+		familyStart--
+	}
+
+	return Name{
+		Given:    strings.Join(tokens[:familyStart], " "),
+		Particle: strings.Join(tokens[familyStart:len(tokens)-1], " "),
+		Family:   tokens[len(tokens)-1],
+	}
+}
+
+// Surname is the family name as it is cited in running text, including any
+// particle: "van Beethoven", "Li", "Doe".
+func (n Name) Surname() string {
+	if n.Particle == "" {
+		return n.Family
+	}
+	return n.Particle + " " + n.Family
+}
+
+// String renders the name in natural reading order.
+func (n Name) String() string {
+	parts := []string{n.Given, n.Surname()}
+	if n.familyFirst() {
+		parts = []string{n.Surname(), n.Given}
+	}
+	display := strings.Join(nonEmpty(parts), " ")
+	if n.Suffix != "" {
+		display += " " + n.Suffix
+	}
+	return display
+}
+
+func (n Name) familyFirst() bool {
+	return isCJK(n.Family) || isRomanizedChineseFamilyFirst([]string{n.Family, n.Given})
+}
+
+// SortKey orders names by family name first, ignoring particles, case and
+// common diacritics.
+func (n Name) SortKey() string {
+	return Fold(strings.Join(nonEmpty([]string{n.Family, n.Given, n.Particle, n.Suffix}), " "))
+}
+
+// CitationKey returns the ASCII-only family-name stem used for citation keys
+// and generated filenames, e.g. "vanbeethoven".
+func (n Name) CitationKey() string {
+	var key strings.Builder
+	for _, r := range Fold(n.Particle + n.Family) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			key.WriteRune(r)
+		}
+	}
+	return key.String()
+}
+
+// Matches reports whether query names this author, ignoring case and common
+// diacritics. A single-word query must equal one whole name part; longer
+// queries match as a substring of the full name or equal the surname.
+func (n Name) Matches(query string) bool {
+	query = Fold(strings.Join(strings.Fields(query), " "))
+	if query == "" {
+		return false
+	}
+	if Fold(n.Family) == query || Fold(n.Surname()) == query {
+		return true
+	}
+	full := Fold(n.String())
+	if !strings.Contains(query, " ") {
+		for _, token := range strings.Fields(full) {
+			if token == query {
+				return true
+			}
+		}
+		return false
+	}
+	return strings.Contains(full, query)
+}
+
+// Less orders two names by SortKey.
+func Less(a, b Name) bool {
+	return a.SortKey() < b.SortKey()
+}
+
+// Fold lowercases s and strips common Latin diacritics so "Erdős" and
+// "erdos" compare equal.
+func Fold(s string) string {
+	var folded strings.Builder
+	folded.Grow(len(s))
+	for _, r := range strings.ToLower(s) {
+		if replacement, ok := foldTable[r]; ok {
+			folded.WriteString(replacement)
+			continue
+		}
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		folded.WriteRune(r)
+	}
+	return folded.String()
+}
+
+var foldTable = buildFoldTable()
+
+func buildFoldTable() map[rune]string {
+	groups := map[string]string{
+		"a":  "àáâãäåāăą",
+		"ae": "æ",
+		"c":  "çćĉċč",
+		"d":  "ďđð",
+		"e":  "èéêëēĕėęě",
+		"g":  "ĝğġģ",
+		"h":  "ĥħ",
+		"i":  "ìíîïĩīĭįı",
+		"j":  "ĵ",
+		"k":  "ķ",
+		"l":  "ĺļľŀł",
+		"n":  "ñńņňŉ",
+		"o":  "òóôõöøōŏő",
+		"oe": "œ",
+		"r":  "ŕŗř",
+		"s":  "śŝşšș",
+		"ss": "ß",
+		"t":  "ţťŧț",
+		"th": "þ",
+		"u":  "ùúûüũūŭůűų",
+		"w":  "ŵ",
+		"y":  "ýÿŷ",
+		"z":  "źżž",
+	}
+	table := make(map[rune]string)
+	for replacement, runes := range groups {
+		for _, r := range runes {
+			table[r] = replacement
+		}
+	}
+	return table
+}
+
+// isParticle reports whether token is a known particle. A single capital
+// letter is always an initial, as in "Andrew Y Ng" or "Ng, Andrew Y".
+func isParticle(token string) bool {
+	if len([]rune(token)) == 1 && unicode.IsUpper([]rune(token)[0]) {
+		return false
+	}
+	return particles[strings.ToLower(token)]
+}
+
+// isLowercaseParticle is isParticle for names in reading order, where,
+// like BibTeX's "von" part, only a lowercase particle joins the family
+// name: "Anh Do Nguyen" has the middle name Do, "Anh do Nguyen" the
+// particle do.
+func isLowercaseParticle(token string) bool {
+	return token == strings.ToLower(token) && isParticle(token)
+}
+
+func isSuffix(token string) bool {
+	return suffixes[strings.ToLower(strings.TrimSpace(token))]
+}
+
+func isCJK(token string) bool {
+	for _, r := range token {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return true
+		}
+	}
+	return false
+}
+
+func isRomanizedChineseFamilyFirst(tokens []string) bool {
+	if len(tokens) != 2 || !commonChineseSurnames[strings.ToLower(tokens[0])] {
+		return false
+	}
+	syllables := strings.Split(tokens[1], "-")
+	if len(syllables) != 2 {
+		return false
+	}
+	for _, syllable := range syllables {
+		if !isPinyinSyllable(strings.ToLower(syllable)) {
+			return false
+		}
+	}
+	return true
+}
+
+func isPinyinSyllable(s string) bool {
+	if pinyinFinals[s] {
+		return true
+	}
+	for _, initial := range pinyinInitials {
+		if strings.HasPrefix(s, initial) && pinyinFinals[s[len(initial):]] {
+			return true
+		}
+	}
+	return false
+}
+
+func nonEmpty(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			out = append(out, value)
+		}
+	}
+	return out
+}
@@ -0,0 +1,143 @@
+package authors
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Name
+	}{
+		{raw: "", want: Name{}},
+		{raw: "   ", want: Name{}},
+		{raw: "Jane Smith", want: Name{Given: "Jane", Family: "Smith"}},
+		{raw: "  Jane   Q.  Smith ", want: Name{Given: "Jane Q.", Family: "Smith"}},
+		{raw: "Plato", want: Name{Family: "Plato"}},
+		{raw: "Ludwig van Beethoven Jr.", want: Name{Given: "Ludwig", Particle: "van", Family: "Beethoven", Suffix: "Jr."}},
+		{raw: "Ursula von der Leyen", want: Name{Given: "Ursula", Particle: "von der", Family: "Leyen"}},
+		{raw: "Jean de la Fontaine", want: Name{Given: "Jean", Particle: "de la", Family: "Fontaine"}},
+		{raw: "Vincent Van Gogh", want: Name{Given: "Vincent Van", Family: "Gogh"}},
+		{raw: "Andrew Y Ng", want: Name{Given: "Andrew Y", Family: "Ng"}},
+		{raw: "Anh Do Nguyen", want: Name{Given: "Anh Do", Family: "Nguyen"}},
+		{raw: "Marie Le Pen", want: Name{Given: "Marie Le", Family: "Pen"}},
+		{raw: "Ana Da Silva", want: Name{Given: "Ana Da", Family: "Silva"}},
+		{raw: "Ana da Silva", want: Name{Given: "Ana", Particle: "da", Family: "Silva"}},
+		{raw: "Ng, Andrew Y", want: Name{Given: "Andrew Y", Family: "Ng"}},
+		{raw: "José Ortega y Gasset", want: Name{Given: "José Ortega", Particle: "y", Family: "Gasset"}},
+		{raw: "Van Morrison", want: Name{Given: "Van", Family: "Morrison"}},
+		{raw: "Martin Luther King Jr.", want: Name{Given: "Martin Luther", Family: "King", Suffix: "Jr."}},
+		{raw: "John Smith III", want: Name{Given: "John", Family: "Smith", Suffix: "III"}},
+		{raw: "John Smith, Jr.", want: Name{Given: "John", Family: "Smith", Suffix: "Jr."}},
+		{raw: "Doe, Jane", want: Name{Given: "Jane", Family: "Doe"}},
+		{raw: "Doe, Jane, Jr.", want: Name{Given: "Jane", Family: "Doe", Suffix: "Jr."}},
+		{raw: "Doe, Jr., Jane", want: Name{Given: "Jane", Family: "Doe", Suffix: "Jr."}},
+		{raw: "van Beethoven, Ludwig", want: Name{Given: "Ludwig", Particle: "van", Family: "Beethoven"}},
+		{raw: "Beethoven, Ludwig van", want: Name{Given: "Ludwig", Particle: "van", Family: "Beethoven"}},
+		{raw: "Jean-Pierre Dupont", want: Name{Given: "Jean-Pierre", Family: "Dupont"}},
+		{raw: "Emma Smith-Jones", want: Name{Given: "Emma", Family: "Smith-Jones"}},
+		{raw: "Li Fei-Fei", want: Name{Given: "Fei-Fei", Family: "Li"}},
+		{raw: "Wang Xiao-Ming", want: Name{Given: "Xiao-Ming", Family: "Wang"}},
+		{raw: "Ma Lloyd-Webber", want: Name{Given: "Ma", Family: "Lloyd-Webber"}},
+		{raw: "Fei-Fei Li", want: Name{Given: "Fei-Fei", Family: "Li"}},
+		{raw: "李飞飞", want: Name{Family: "李飞飞"}},
+		{raw: "山田 太郎", want: Name{Given: "太郎", Family: "山田"}},
+		{raw: "김 민준", want: Name{Given: "민준", Family: "김"}},
+		{raw: "Paul Erdős", want: Name{Given: "Paul", Family: "Erdős"}},
+		{raw: "Jr.", want: Name{Family: "Jr."}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := Parse(tt.raw); got != tt.want {
+				t.Fatalf("Parse(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameSurnameAndString(t *testing.T) {
+	tests := []struct {
+		raw         string
+		wantSurname string
+		wantString  string
+	}{
+		{raw: "Ludwig van Beethoven Jr.", wantSurname: "van Beethoven", wantString: "Ludwig van Beethoven Jr."},
+		{raw: "Doe, Jane", wantSurname: "Doe", wantString: "Jane Doe"},
+		{raw: "Li Fei-Fei", wantSurname: "Li", wantString: "Li Fei-Fei"},
+		{raw: "山田 太郎", wantSurname: "山田", wantString: "山田 太郎"},
+		{raw: "Plato", wantSurname: "Plato", wantString: "Plato"},
+		{raw: "Andrew Y Ng", wantSurname: "Ng", wantString: "Andrew Y Ng"},
+		{raw: "Anh Do Nguyen", wantSurname: "Nguyen", wantString: "Anh Do Nguyen"},
+	}
+
+	for _, tt := range tests {
+		name := Parse(tt.raw)
+		if got := name.Surname(); got != tt.wantSurname {
+			t.Errorf("Parse(%q).Surname() = %q, want %q", tt.raw, got, tt.wantSurname)
+		}
+		if got := name.String(); got != tt.wantString {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.raw, got, tt.wantString)
+		}
+	}
+}
+
+func TestNameCitationKey(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{raw: "Ludwig van Beethoven", want: "vanbeethoven"},
+		{raw: "Paul Erdős", want: "erdos"},
+		{raw: "Emma Smith-Jones", want: "smithjones"},
+		{raw: "Doe, Jane", want: "doe"},
+		{raw: "李飞飞", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := Parse(tt.raw).CitationKey(); got != tt.want {
+			t.Errorf("Parse(%q).CitationKey() = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestNameMatches(t *testing.T) {
+	tests := []struct {
+		raw   string
+		query string
+		want  bool
+	}{
+		{raw: "Paul Erdős", query: "erdos", want: true},
+		{raw: "Paul Erdős", query: "Paul Erdos", want: true},
+		{raw: "Ludwig van Beethoven", query: "van beethoven", want: true},
+		{raw: "Ludwig van Beethoven", query: "beethoven", want: true},
+		{raw: "Ludwig van Beethoven", query: "van", want: true},
+		{raw: "Jane Smith", query: "smi", want: false},
+		{raw: "Jane Smith", query: "", want: false},
+		{raw: "Doe, Jane", query: "jane doe", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := Parse(tt.raw).Matches(tt.query); got != tt.want {
+			t.Errorf("Parse(%q).Matches(%q) = %t, want %t", tt.raw, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestLessSortsByFamilyIgnoringParticles(t *testing.T) {
+	names := []Name{
+		Parse("Jane Smith"),
+		Parse("Ludwig van Beethoven"),
+		Parse("Paul Erdős"),
+		Parse("Alan Adams"),
+	}
+	sort.Slice(names, func(i, j int) bool { return Less(names[i], names[j]) })
+
+	want := []string{"Adams", "Beethoven", "Erdős", "Smith"}
+	for i, name := range names {
+		if name.Family != want[i] {
+			t.Fatalf("sorted[%d] = %q, want %q", i, name.Family, want[i])
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package config
 
-import "os"
+import (
+	"os"
+	"path/filepath"
+)
 
 func APIURL() string {
 	if v := os.Getenv("PZ_API_URL"); v != "" {
@@ -8,3 +11,48 @@ func APIURL() string {
 	}
 	return "https://paperzilla.ai"
 }
+
+// LibraryPath is the local SQLite library written by pz sync.
+func LibraryPath() string {
+	if v := os.Getenv("PZ_LIBRARY_PATH"); v != "" {
+		return v
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".paperzilla", "library.db")
+}
+
+// WatchStatePath holds the per-project cursors of pz watch.
+func WatchStatePath() string {
+	if v := os.Getenv("PZ_WATCH_STATE_PATH"); v != "" {
+		return v
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".paperzilla", "watch.json")
+}
+
+// NotifyStatePath records which papers each notification sink has announced.
+func NotifyStatePath() string {
+	if v := os.Getenv("PZ_NOTIFY_STATE_PATH"); v != "" {
+		return v
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".paperzilla", "notified.json")
+}
+
+// SeenStatePath records which recommendations have been shown, per account.
+func SeenStatePath() string {
+	if v := os.Getenv("PZ_SEEN_STATE_PATH"); v != "" {
+		return v
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".paperzilla", "seen.json")
+}
+
+// FeedbackLogPath is the append-only log of feedback changes made with pz.
+func FeedbackLogPath() string {
+	if v := os.Getenv("PZ_FEEDBACK_LOG_PATH"); v != "" {
+		return v
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".paperzilla", "feedback-log.jsonl")
+}
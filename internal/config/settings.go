@@ -0,0 +1,65 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Settings holds optional user preferences from ~/.paperzilla/config.json.
+type Settings struct {
+	Pager   string       `json:"pager,omitempty"`
+	Browser string       `json:"browser,omitempty"`
+	Notify  []NotifySink `json:"notify,omitempty"`
+	SMTP    *SMTP        `json:"smtp,omitempty"`
+}
+
+// SMTP is the mail server pz digest send delivers through.
+type SMTP struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"`
+	Username string `json:"username,omitempty"`
+	// Password may be left out and set in PZ_SMTP_PASSWORD instead.
+	Password string `json:"password,omitempty"`
+	From     string `json:"from"`
+	// TLS is "starttls" (the default), "tls" for implicit TLS, usually on
+	// port 465, or "none" for a trusted local relay.
+	TLS string `json:"tls,omitempty"`
+}
+
+// NotifySink is an incoming webhook that pz watch --notify posts new
+// papers to.
+type NotifySink struct {
+	Name string `json:"name"`
+	// Type is "webhook" for plain JSON, "slack" or "mattermost".
+	Type string `json:"type"`
+	URL  string `json:"url"`
+	// Projects limits the sink to these project IDs. Empty means all.
+	Projects []string `json:"projects,omitempty"`
+	// IncludeRelated also announces related papers, not only must-reads.
+	IncludeRelated bool `json:"include_related,omitempty"`
+}
+
+func settingsPath() string {
+	if p := os.Getenv("PZ_CONFIG_PATH"); p != "" {
+		return p
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".paperzilla", "config.json")
+}
+
+// LoadSettings returns zero Settings when no config file exists.
+func LoadSettings() (Settings, error) {
+	var s Settings
+	data, err := os.ReadFile(settingsPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(data, &s)
+	return s, err
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadSettingsMissingFileReturnsDefaults(t *testing.T) {
+	t.Setenv("PZ_CONFIG_PATH", filepath.Join(t.TempDir(), "config.json"))
+
+	got, err := LoadSettings()
+	if err != nil {
+		t.Fatalf("LoadSettings: %v", err)
+	}
+	if !reflect.DeepEqual(got, Settings{}) {
+		t.Errorf("got %+v, want zero settings", got)
+	}
+}
+
+func TestLoadSettingsReadsPager(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv("PZ_CONFIG_PATH", path)
+	if err := os.WriteFile(path, []byte(`{"pager":"less -R"}`), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	got, err := LoadSettings()
+	if err != nil {
+		t.Fatalf("LoadSettings: %v", err)
+	}
+	if got.Pager != "less -R" {
+		t.Errorf("Pager = %q, want %q", got.Pager, "less -R")
+	}
+}
+
+func TestLoadSettingsRejectsInvalidJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv("PZ_CONFIG_PATH", path)
+	if err := os.WriteFile(path, []byte(`{"pager":`), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if _, err := LoadSettings(); err == nil {
+		t.Fatal("expected error for invalid JSON, got nil")
+	}
+}
@@ -1,9 +1,11 @@
 package config
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 type Tokens struct {
@@ -59,3 +61,24 @@ func LoadTokens() (Tokens, error) {
 	err = json.Unmarshal(data, &t)
 	return t, err
 }
+
+// Account identifies the logged-in account for local state, using the
+// subject of the access token. The token is not verified; tokens that are
+// not JWTs share the "default" account.
+func (t Tokens) Account() string {
+	parts := strings.Split(t.AccessToken, ".")
+	if len(parts) != 3 {
+		return "default"
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return "default"
+	}
+	var claims struct {
+		Subject string `json:"sub"`
+	}
+	if json.Unmarshal(payload, &claims) != nil || claims.Subject == "" {
+		return "default"
+	}
+	return claims.Subject
+}
@@ -120,3 +120,15 @@ func TestSaveTokensOverwriteRepairsFilePermissions(t *testing.T) {
 		t.Fatalf("got %+v, want %+v", got, want)
 	}
 }
+
+func TestTokensAccountUsesJWTSubject(t *testing.T) {
+	jwt := "eyJhbGciOiJIUzI1NiJ9." + "eyJzdWIiOiJ1c2VyLTQyIiwiZXhwIjoxfQ" + ".sig"
+	if got := (Tokens{AccessToken: jwt}).Account(); got != "user-42" {
+		t.Errorf("Account() = %q, want user-42", got)
+	}
+	for _, token := range []string{"", "access_abc", "a.!!!.c", "a.e30.c"} {
+		if got := (Tokens{AccessToken: token}).Account(); got != "default" {
+			t.Errorf("Account(%q) = %q, want default", token, got)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Package digest builds and mails feed digests.
+package digest
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// Digest is the content of one email.
+type Digest struct {
+	ProjectName string
+	Since       time.Time
+	Until       time.Time
+	MustRead    []Entry
+	Related     []Entry
+}
+
+// Entry is one paper. Links must already be safe http(s) URLs.
+type Entry struct {
+	Title     string
+	Byline    string
+	Relevance int
+	Summary   string
+	Note      string
+	Links     []Link
+}
+
+type Link struct {
+	Label string
+	URL   string
+}
+
+func (d Digest) Count() int {
+	return len(d.MustRead) + len(d.Related)
+}
+
+// Subject is the default subject line.
+func (d Digest) Subject() string {
+	papers := "papers"
+	if d.Count() == 1 {
+		papers = "paper"
+	}
+	return fmt.Sprintf("%s: %d new %s (%s)", d.ProjectName, d.Count(), papers, d.Period())
+}
+
+func (d Digest) Period() string {
+	return d.Since.Format("Jan 2") + " – " + d.Until.Format("Jan 2, 2006")
+}
+
+// Text renders the plain-text part.
+func (d Digest) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n%s\n", d.ProjectName, d.Period())
+	for _, section := range d.sections() {
+		fmt.Fprintf(&b, "\n%s\n%s\n", section.Heading, strings.Repeat("=", len([]rune(section.Heading))))
+		for _, entry := range section.Entries {
+			fmt.Fprintf(&b, "\n%s\n", entry.Title)
+			if meta := entry.Meta(); meta != "" {
+				fmt.Fprintf(&b, "%s\n", meta)
+			}
+			if entry.Summary != "" {
+				fmt.Fprintf(&b, "\n%s\n", entry.Summary)
+			}
+			if entry.Note != "" {
+				fmt.Fprintf(&b, "\nWhy it matters: %s\n", entry.Note)
+			}
+			for _, link := range entry.Links {
+				fmt.Fprintf(&b, "%s: %s\n", link.Label, link.URL)
+			}
+		}
+	}
+	b.WriteString("\n--\nSent with pz, the Paperzilla CLI.\n")
+	return b.String()
+}
+
+// HTML renders the HTML part. Inline styles keep it readable in mail
+// clients that drop <style> blocks.
+func (d Digest) HTML() (string, error) {
+	var b bytes.Buffer
+	err := htmlTemplate.Execute(&b, struct {
+		Digest
+		Sections []section
+	}{d, d.sections()})
+	if err != nil {
+		return "", fmt.Errorf("failed to render digest: %w", err)
+	}
+	return b.String(), nil
+}
+
+type section struct {
+	Heading string
+	Entries []Entry
+}
+
+func (d Digest) sections() []section {
+	var sections []section
+	if len(d.MustRead) > 0 {
+		sections = append(sections, section{"Must read", d.MustRead})
+	}
+	if len(d.Related) > 0 {
+		sections = append(sections, section{"Related", d.Related})
+	}
+	return sections
+}
+
+// Meta is the byline and relevance line.
+func (e Entry) Meta() string {
+	parts := []string{}
+	if e.Byline != "" {
+		parts = append(parts, e.Byline)
+	}
+	if e.Relevance > 0 {
+		parts = append(parts, fmt.Sprintf("relevance: %d%%", e.Relevance))
+	}
+	return strings.Join(parts, " · ")
+}
+
+var htmlTemplate = template.Must(template.New("digest").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Subject}}</title></head>
+<body style="margin:0;padding:24px;font-family:-apple-system,Segoe UI,Helvetica,Arial,sans-serif;color:#1f2328;line-height:1.5">
+<div style="max-width:640px;margin:0 auto">
+<h1 style="font-size:22px;margin:0">{{.ProjectName}}</h1>
+<p style="color:#59636e;margin:4px 0 0">{{.Period}}</p>
+{{range .Sections}}
+<h2 style="font-size:17px;margin:28px 0 0;padding-bottom:4px;border-bottom:1px solid #d1d9e0">{{.Heading}}</h2>
+{{range .Entries}}
+<div style="margin:18px 0">
+<div style="font-size:16px;font-weight:600">{{if .Links}}<a href="{{(index .Links 0).URL}}" style="color:#0969da;text-decoration:none">{{.Title}}</a>{{else}}{{.Title}}{{end}}</div>
+{{with .Meta}}<div style="color:#59636e;font-size:13px">{{.}}</div>{{end}}
+{{with .Summary}}<p style="margin:8px 0 0">{{.}}</p>{{end}}
+{{with .Note}}<p style="margin:8px 0 0;padding-left:10px;border-left:3px solid #d1d9e0;color:#59636e"><strong>Why it matters:</strong> {{.}}</p>{{end}}
+{{if .Links}}<div style="margin-top:6px;font-size:13px">{{range $i, $link := .Links}}{{if $i}} · {{end}}<a href="{{$link.URL}}" style="color:#0969da">{{$link.Label}}</a>{{end}}</div>{{end}}
+</div>
+{{end}}
+{{end}}
+<p style="color:#59636e;font-size:12px;margin-top:32px">Sent with pz, the Paperzilla CLI.</p>
+</div>
+</body>
+</html>
+`))
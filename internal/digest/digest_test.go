@@ -0,0 +1,264 @@
+package digest
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http/httptest"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testDigest() Digest {
+	return Digest{
+		ProjectName: "Graph <Learning>",
+		Since:       time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC),
+		Until:       time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+		MustRead: []Entry{{
+			Title:     "Attention & everything",
+			Byline:    "Vaswani et al. · NeurIPS · 2017",
+			Relevance: 91,
+			Summary:   "A <b>bold</b> claim.",
+			Note:      "Matches your interest in transformers.",
+			Links:     []Link{{"Paperzilla", "https://paperzilla.ai/recommendations/pp-1"}, {"PDF", "https://arxiv.org/pdf/1706.03762"}},
+		}},
+		Related: []Entry{{Title: "Graph pooling", Links: []Link{{"Paper", "javascript:alert(1)"}}}},
+	}
+}
+
+func TestDigestRendersTextAndEscapedHTML(t *testing.T) {
+	d := testDigest()
+	if got := d.Subject(); got != "Graph <Learning>: 2 new papers (Oct 12 – Oct 19, 2026)" {
+		t.Errorf("Subject = %q", got)
+	}
+
+	text := d.Text()
+	for _, want := range []string{
+		"Must read\n=========\n\nAttention & everything\nVaswani et al. · NeurIPS · 2017 · relevance: 91%\n\nA <b>bold</b> claim.\n",
+		"Why it matters: Matches your interest in transformers.\nPaperzilla: https://paperzilla.ai/recommendations/pp-1\nPDF: https://arxiv.org/pdf/1706.03762\n",
+		"Related\n=======\n\nGraph pooling\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text missing %q:\n%s", want, text)
+		}
+	}
+
+	html, err := d.HTML()
+	if err != nil {
+		t.Fatalf("HTML: %v", err)
+	}
+	for _, want := range []string{
+		"<h1 style=\"font-size:22px;margin:0\">Graph &lt;Learning&gt;</h1>",
+		`<a href="https://paperzilla.ai/recommendations/pp-1" style="color:#0969da;text-decoration:none">Attention &amp; everything</a>`,
+		"A &lt;b&gt;bold&lt;/b&gt; claim.",
+		`href="#ZgotmplZ"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("html missing %q:\n%s", want, html)
+		}
+	}
+	if strings.Contains(html, "javascript:") {
+		t.Error("unsafe link rendered")
+	}
+}
+
+func TestComposeBuildsMultipartAlternative(t *testing.T) {
+	raw, err := Compose(Header{
+		From:    "Lab Bot <bot@lab.example>",
+		To:      []string{"team@lab.example, Ada <ada@lab.example>"},
+		Subject: "Ümlauts\r\nBcc: evil@example.com",
+		Date:    time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC),
+	}, "plain ünïcode line\n", "<p>html</p>")
+	if err != nil {
+		t.Fatalf("Compose: %v", err)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if msg.Header.Get("Bcc") != "" {
+		t.Fatal("subject injected a header")
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if subject != "Ümlauts Bcc: evil@example.com" {
+		t.Errorf("Subject = %q", subject)
+	}
+	if to := msg.Header.Get("To"); to != `<team@lab.example>, "Ada" <ada@lab.example>` {
+		t.Errorf("To = %q", to)
+	}
+	if !strings.HasSuffix(msg.Header.Get("Message-ID"), "@lab.example>") {
+		t.Errorf("Message-ID = %q", msg.Header.Get("Message-ID"))
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q", mediaType)
+	}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	var parts []string
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextRawPart: %v", err)
+		}
+		body, _ := io.ReadAll(quotedprintable.NewReader(part))
+		parts = append(parts, part.Header.Get("Content-Type")+": "+string(body))
+	}
+	if len(parts) != 2 || parts[0] != "text/plain; charset=utf-8: plain ünïcode line\r\n" || parts[1] != "text/html; charset=utf-8: <p>html</p>" {
+		t.Fatalf("parts = %q", parts)
+	}
+
+	if _, err := Compose(Header{From: "bot@lab.example", To: []string{"not an address"}}, "", ""); err == nil {
+		t.Fatal("invalid recipient should fail")
+	}
+}
+
+// fakeSMTP accepts one session and records the commands and message.
+type fakeSMTP struct {
+	addr     string
+	starttls bool
+	cert     tls.Certificate
+	commands chan []string
+}
+
+func startFakeSMTP(t *testing.T, starttls bool) (*fakeSMTP, *x509.CertPool) {
+	t.Helper()
+	certServer := httptest.NewUnstartedServer(nil)
+	certServer.StartTLS()
+	cert := certServer.TLS.Certificates[0]
+	pool := x509.NewCertPool()
+	pool.AddCert(certServer.Certificate())
+	certServer.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	server := &fakeSMTP{addr: listener.Addr().String(), starttls: starttls, cert: cert, commands: make(chan []string, 1)}
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var commands []string
+		defer func() { server.commands <- commands }()
+
+		rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+		reply := func(lines ...string) {
+			for _, line := range lines {
+				rw.WriteString(line + "\r\n")
+			}
+			rw.Flush()
+		}
+		reply("220 fake ESMTP")
+		secure := false
+		for {
+			line, err := rw.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			commands = append(commands, line)
+			verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch verb {
+			case "EHLO":
+				if server.starttls && !secure {
+					reply("250-fake", "250 STARTTLS")
+				} else {
+					reply("250-fake", "250 AUTH PLAIN")
+				}
+			case "STARTTLS":
+				reply("220 go ahead")
+				tlsConn := tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{server.cert}})
+				if err := tlsConn.Handshake(); err != nil {
+					return
+				}
+				conn = tlsConn
+				rw = bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+				secure = true
+			case "AUTH":
+				reply("235 ok")
+			case "MAIL", "RCPT":
+				reply("250 ok")
+			case "DATA":
+				reply("354 go ahead")
+				var data strings.Builder
+				for {
+					l, err := rw.ReadString('\n')
+					if err != nil || l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				commands = append(commands, "MESSAGE "+data.String())
+				reply("250 queued")
+			case "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("502 unknown")
+			}
+		}
+	}()
+	return server, pool
+}
+
+func (s *fakeSMTP) mailer() Mailer {
+	host, port, _ := net.SplitHostPort(s.addr)
+	n, _ := strconv.Atoi(port)
+	return Mailer{Host: host, Port: n}
+}
+
+func TestMailerSendsOverStartTLSWithAuth(t *testing.T) {
+	server, pool := startFakeSMTP(t, true)
+	m := server.mailer()
+	m.Username, m.Password = "bot", "secret"
+	m.TLSConfig = &tls.Config{RootCAs: pool, ServerName: "example.com"}
+
+	if err := m.Send("bot@lab.example", []string{"a@lab.example", "b@lab.example"}, []byte("Subject: hi\r\n\r\nbody\r\n")); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	commands := strings.Join(<-server.commands, "\n")
+	auth := "AUTH PLAIN " + base64.StdEncoding.EncodeToString([]byte("\x00bot\x00secret"))
+	for _, want := range []string{"STARTTLS", auth, "MAIL FROM:<bot@lab.example>", "RCPT TO:<a@lab.example>", "RCPT TO:<b@lab.example>", "MESSAGE Subject: hi\r\n\r\nbody\r\n"} {
+		if !strings.Contains(commands, want) {
+			t.Errorf("commands missing %q:\n%s", want, commands)
+		}
+	}
+	if strings.Index(commands, "STARTTLS") > strings.Index(commands, "AUTH") {
+		t.Error("authenticated before STARTTLS")
+	}
+}
+
+func TestMailerRefusesToDowngradeWithoutStartTLS(t *testing.T) {
+	server, _ := startFakeSMTP(t, false)
+	m := server.mailer()
+	m.Username, m.Password = "bot", "secret"
+	err := m.Send("bot@lab.example", []string{"a@lab.example"}, []byte("x"))
+	if err == nil || !strings.Contains(err.Error(), "does not offer STARTTLS") {
+		t.Fatalf("err = %v", err)
+	}
+	if commands := strings.Join(<-server.commands, "\n"); strings.Contains(commands, "AUTH") || strings.Contains(commands, "MAIL") {
+		t.Fatalf("sent commands after refusing: %s", commands)
+	}
+
+	if err := (Mailer{Host: "mail.example", TLS: "ssl"}).Validate(); err == nil {
+		t.Fatal("unknown tls mode should fail")
+	}
+}
@@ -0,0 +1,108 @@
+package digest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Header holds the addressing of one message.
+type Header struct {
+	From    string
+	To      []string
+	Subject string
+	Date    time.Time
+}
+
+// ParseAddresses validates a list of recipients given as separate values,
+// each of which may itself be a comma-separated list.
+func ParseAddresses(values []string) ([]*mail.Address, error) {
+	var addresses []*mail.Address
+	for _, value := range values {
+		list, err := mail.ParseAddressList(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q: %w", value, err)
+		}
+		addresses = append(addresses, list...)
+	}
+	return addresses, nil
+}
+
+// Compose builds a multipart/alternative message with a plain-text and an
+// HTML part.
+func Compose(h Header, text, html string) ([]byte, error) {
+	from, err := mail.ParseAddress(h.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address %q: %w", h.From, err)
+	}
+	to, err := ParseAddresses(h.To)
+	if err != nil {
+		return nil, err
+	}
+	if len(to) == 0 {
+		return nil, fmt.Errorf("no recipients")
+	}
+	recipients := make([]string, len(to))
+	for i, address := range to {
+		recipients[i] = address.String()
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build message: %w", err)
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(strings.ReplaceAll(part.content, "\n", "\r\n"))); err != nil {
+			return nil, fmt.Errorf("failed to build message: %w", err)
+		}
+		if err := qp.Close(); err != nil {
+			return nil, fmt.Errorf("failed to build message: %w", err)
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build message: %w", err)
+	}
+
+	var msg bytes.Buffer
+	header := func(key, value string) {
+		fmt.Fprintf(&msg, "%s: %s\r\n", key, value)
+	}
+	header("From", from.String())
+	header("To", strings.Join(recipients, ", "))
+	// Newlines in the subject would let it add headers of its own.
+	header("Subject", mime.QEncoding.Encode("utf-8", strings.Join(strings.Fields(h.Subject), " ")))
+	header("Date", h.Date.Format(time.RFC1123Z))
+	header("Message-ID", messageID(from.Address))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "multipart/alternative; boundary="+parts.Boundary())
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 && at < len(from)-1 {
+		domain = from[at+1:]
+	}
+	var random [12]byte
+	_, _ = rand.Read(random[:])
+	return "<pz." + hex.EncodeToString(random[:]) + "@" + domain + ">"
+}
@@ -0,0 +1,122 @@
+package digest
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+const (
+	TLSStartTLS = "starttls"
+	TLSImplicit = "tls"
+	TLSNone     = "none"
+
+	smtpTimeout = 30 * time.Second
+)
+
+// Mailer sends messages through one SMTP server.
+type Mailer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// TLS is TLSStartTLS (the default), TLSImplicit or TLSNone. With
+	// STARTTLS a server that does not offer it is an error rather than a
+	// silent downgrade.
+	TLS string
+	// TLSConfig overrides the TLS settings, for tests.
+	TLSConfig *tls.Config
+}
+
+func (m Mailer) Validate() error {
+	if m.Host == "" {
+		return fmt.Errorf("smtp host is not set")
+	}
+	switch m.TLS {
+	case "", TLSStartTLS, TLSImplicit, TLSNone:
+	default:
+		return fmt.Errorf("smtp tls must be starttls, tls or none")
+	}
+	if m.Port < 0 || m.Port > 65535 {
+		return fmt.Errorf("smtp port %d is out of range", m.Port)
+	}
+	return nil
+}
+
+func (m Mailer) port() int {
+	if m.Port != 0 {
+		return m.Port
+	}
+	if m.TLS == TLSImplicit {
+		return 465
+	}
+	return 587
+}
+
+// Send delivers msg from the envelope sender to each recipient.
+func (m Mailer) Send(from string, to []string, msg []byte) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	addr := net.JoinHostPort(m.Host, strconv.Itoa(m.port()))
+	tlsConfig := m.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{ServerName: m.Host}
+	}
+
+	var conn net.Conn
+	var err error
+	if m.TLS == TLSImplicit {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: smtpTimeout}, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = net.DialTimeout("tcp", addr, smtpTimeout)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(5 * smtpTimeout))
+
+	client, err := smtp.NewClient(conn, m.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if m.TLS == "" || m.TLS == TLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("%s does not offer STARTTLS; set \"tls\" to \"tls\" or \"none\" if that is expected", addr)
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+
+	if m.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.Username, m.Password, m.Host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("server rejected sender %s: %w", from, err)
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("server rejected recipient %s: %w", rcpt, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return client.Quit()
+}
@@ -0,0 +1,378 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultWorkers = 4
+	DefaultMaxSize = 100 << 20
+
+	partSuffix = ".part"
+)
+
+var pdfMagic = []byte("%PDF-")
+
+type Status string
+
+const (
+	StatusDownloaded Status = "downloaded"
+	StatusSkipped    Status = "skipped"
+	StatusFailed     Status = "failed"
+)
+
+// Job is one file to fetch. Name is relative to the download directory.
+type Job struct {
+	Ref  string
+	URL  string
+	Name string
+}
+
+type Result struct {
+	Job     Job
+	Status  Status
+	Size    int64
+	Resumed bool
+	Err     error
+}
+
+// Downloader fetches jobs into Dir with bounded parallelism. Partial files
+// are kept as "<name>.part" and resumed with a Range request on the next
+// run; finished files are recorded in the manifest so re-runs skip them.
+type Downloader struct {
+	Client    *http.Client
+	Dir       string
+	Workers   int
+	MaxSize   int64
+	UserAgent string
+	Now       func() time.Time
+	// Progress is called once per job as it finishes, never concurrently.
+	Progress func(Result)
+}
+
+func NewDownloader(dir string) Downloader {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = 30 * time.Second
+	return Downloader{
+		Client:  &http.Client{Transport: transport},
+		Dir:     dir,
+		Workers: DefaultWorkers,
+		MaxSize: DefaultMaxSize,
+		Now:     time.Now,
+	}
+}
+
+// Run downloads all jobs and returns one result per job, in job order. The
+// error is only set when the directory or manifest cannot be used at all;
+// per-file failures are reported in the results.
+func (d Downloader) Run(ctx context.Context, jobs []Job) ([]Result, error) {
+	if err := os.MkdirAll(d.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create download directory: %w", err)
+	}
+	manifest, err := LoadManifest(d.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, len(jobs))
+	indexes := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for w := 0; w < max(d.Workers, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result := d.fetch(ctx, jobs[i], manifest, &mu)
+				mu.Lock()
+				results[i] = result
+				if d.Progress != nil {
+					d.Progress(result)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range jobs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results, nil
+}
+
+func (d Downloader) fetch(ctx context.Context, job Job, manifest *Manifest, mu *sync.Mutex) Result {
+	result := Result{Job: job}
+	path := filepath.Join(d.Dir, job.Name)
+
+	mu.Lock()
+	entry, recorded := manifest.Files[job.Name]
+	mu.Unlock()
+	// Never overwrite a file that was downloaded for another URL.
+	if recorded && entry.URL != job.URL {
+		result.Status = StatusFailed
+		result.Err = fmt.Errorf("%s is already the download of %s", job.Name, entry.URL)
+		return result
+	}
+	if recorded {
+		if info, err := os.Stat(path); err == nil && info.Size() == entry.Size {
+			result.Status = StatusSkipped
+			result.Size = entry.Size
+			return result
+		}
+	}
+
+	size, sum, resumed, err := d.download(ctx, job.URL, path)
+	result.Resumed = resumed
+	if err != nil {
+		result.Status = StatusFailed
+		result.Err = err
+		return result
+	}
+
+	mu.Lock()
+	manifest.Files[job.Name] = Entry{
+		Ref:          job.Ref,
+		URL:          job.URL,
+		Size:         size,
+		SHA256:       sum,
+		DownloadedAt: d.now().UTC(),
+	}
+	err = manifest.Save()
+	mu.Unlock()
+	if err != nil {
+		result.Status = StatusFailed
+		result.Err = err
+		return result
+	}
+
+	result.Status = StatusDownloaded
+	result.Size = size
+	return result
+}
+
+// download fetches url into path via path.part and returns the final size
+// and SHA-256. An existing part file is resumed when the server honors the
+// Range request and restarted otherwise.
+func (d Downloader) download(ctx context.Context, url, path string) (int64, string, bool, error) {
+	partPath := path + partSuffix
+	hasher := sha256.New()
+
+	offset, err := hashPart(partPath, hasher)
+	if err != nil {
+		return 0, "", false, err
+	}
+
+	resp, err := d.get(ctx, url, offset)
+	if err != nil {
+		return 0, "", false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0 {
+		// The part file no longer matches the remote file; start over.
+		resp.Body.Close()
+		offset = 0
+		hasher.Reset()
+		if resp, err = d.get(ctx, url, 0); err != nil {
+			return 0, "", false, err
+		}
+		defer resp.Body.Close()
+	}
+
+	total, resumed, err := d.checkResponse(resp, offset)
+	if err != nil {
+		return 0, "", false, err
+	}
+	if !resumed {
+		offset = 0
+		hasher.Reset()
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if resumed {
+		flags = os.O_WRONLY | os.O_APPEND
+	}
+	file, err := os.OpenFile(partPath, flags, 0o644)
+	if err != nil {
+		return 0, "", resumed, fmt.Errorf("failed to open %s: %w", partPath, err)
+	}
+
+	body := io.LimitReader(resp.Body, d.maxSize()-offset+1)
+	var head []byte
+	if offset == 0 {
+		// Check the first bytes before writing anything, so HTML error
+		// pages never land on disk under a .pdf name.
+		head = make([]byte, len(pdfMagic))
+		n, _ := io.ReadFull(body, head)
+		head = head[:n]
+		if !bytes.Equal(head, pdfMagic) {
+			file.Close()
+			os.Remove(partPath)
+			return 0, "", false, errors.New("response is not a PDF")
+		}
+	}
+
+	written, copyErr := io.Copy(io.MultiWriter(file, hasher), io.MultiReader(bytes.NewReader(head), body))
+	closeErr := file.Close()
+	size := offset + written
+	switch {
+	case copyErr != nil:
+		// Keep the part file so the next run can resume.
+		return 0, "", resumed, fmt.Errorf("download interrupted after %d bytes: %w", size, copyErr)
+	case closeErr != nil:
+		return 0, "", resumed, fmt.Errorf("failed to write %s: %w", partPath, closeErr)
+	case size > d.maxSize():
+		os.Remove(partPath)
+		return 0, "", resumed, fmt.Errorf("file exceeds the %s size limit", FormatSize(d.maxSize()))
+	case total >= 0 && size != total:
+		return 0, "", resumed, fmt.Errorf("download incomplete: got %d of %d bytes", size, total)
+	}
+
+	if err := os.Rename(partPath, path); err != nil {
+		return 0, "", resumed, fmt.Errorf("failed to save %s: %w", path, err)
+	}
+	return size, hex.EncodeToString(hasher.Sum(nil)), resumed, nil
+}
+
+func (d Downloader) get(ctx context.Context, url string, offset int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/pdf")
+	if d.UserAgent != "" {
+		req.Header.Set("User-Agent", d.UserAgent)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+// checkResponse validates status, content type and declared size. It
+// returns the expected final file size (-1 if unknown) and whether the
+// response continues the part file at offset.
+func (d Downloader) checkResponse(resp *http.Response, offset int64) (int64, bool, error) {
+	var total int64 = -1
+	resumed := false
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if resp.ContentLength >= 0 {
+			total = resp.ContentLength
+		}
+	case http.StatusPartialContent:
+		start, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			return 0, false, fmt.Errorf("unexpected Content-Range %q", resp.Header.Get("Content-Range"))
+		}
+		total, resumed = size, true
+	default:
+		return 0, false, fmt.Errorf("server returned %s", resp.Status)
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil {
+		if strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "html") || strings.HasSuffix(mediaType, "json") {
+			return 0, false, fmt.Errorf("unexpected content type %s", mediaType)
+		}
+	}
+	if total > d.maxSize() {
+		return 0, false, fmt.Errorf("file is %s, over the %s size limit", FormatSize(total), FormatSize(d.maxSize()))
+	}
+	return total, resumed, nil
+}
+
+// hashPart feeds an existing part file into hasher and returns its size.
+func hashPart(path string, hasher hash.Hash) (int64, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer file.Close()
+
+	n, err := io.Copy(hasher, file)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return n, nil
+}
+
+// parseContentRange parses "bytes start-end/size". The size may be "*".
+func parseContentRange(value string) (int64, int64, bool) {
+	rest, ok := strings.CutPrefix(value, "bytes ")
+	if !ok {
+		return 0, 0, false
+	}
+	span, sizeText, ok := strings.Cut(rest, "/")
+	if !ok {
+		return 0, 0, false
+	}
+	startText, _, ok := strings.Cut(span, "-")
+	if !ok {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(startText, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if sizeText == "*" {
+		return start, -1, true
+	}
+	size, err := strconv.ParseInt(sizeText, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, size, true
+}
+
+func (d Downloader) maxSize() int64 {
+	if d.MaxSize <= 0 {
+		return DefaultMaxSize
+	}
+	return d.MaxSize
+}
+
+func (d Downloader) now() time.Time {
+	if d.Now != nil {
+		return d.Now()
+	}
+	return time.Now()
+}
+
+// FormatSize renders a byte count as B, KB or MB.
+func FormatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -0,0 +1,200 @@
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var samplePDF = []byte("%PDF-1.7\n" + strings.Repeat("stream data ", 200) + "\n%%EOF\n")
+
+type pdfServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []string
+}
+
+func newPDFServer(t *testing.T, ignoreRange bool) *pdfServer {
+	t.Helper()
+	s := &pdfServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, strings.TrimSpace(r.URL.Path+" "+r.Header.Get("Range")))
+		s.mu.Unlock()
+
+		switch r.URL.Path {
+		case "/paper.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			if ignoreRange {
+				w.Write(samplePDF)
+				return
+			}
+			http.ServeContent(w, r, "paper.pdf", time.Time{}, strings.NewReader(string(samplePDF)))
+		case "/paywall.pdf":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, "<html>Sign in</html>")
+		case "/mislabelled.pdf":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			fmt.Fprint(w, "<html>Sign in</html>")
+		case "/missing.pdf":
+			http.NotFound(w, r)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestRunDownloadsAndRecordsManifest(t *testing.T) {
+	server := newPDFServer(t, false)
+	dir := t.TempDir()
+	downloader := NewDownloader(dir)
+
+	results, err := downloader.Run(context.Background(), []Job{{Ref: "p1", URL: server.URL + "/paper.pdf", Name: "a.pdf"}})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if results[0].Status != StatusDownloaded || results[0].Size != int64(len(samplePDF)) {
+		t.Fatalf("result = %+v", results[0])
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "a.pdf"))
+	if err != nil || string(data) != string(samplePDF) {
+		t.Fatalf("file = %q, %v", data, err)
+	}
+	manifest, err := LoadManifest(dir)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	sum := sha256.Sum256(samplePDF)
+	entry := manifest.Files["a.pdf"]
+	if entry.URL != server.URL+"/paper.pdf" || entry.SHA256 != hex.EncodeToString(sum[:]) || entry.Ref != "p1" {
+		t.Fatalf("entry = %+v", entry)
+	}
+
+	results, err = downloader.Run(context.Background(), []Job{{Ref: "p1", URL: server.URL + "/paper.pdf", Name: "a.pdf"}})
+	if err != nil || results[0].Status != StatusSkipped {
+		t.Fatalf("second run = %+v, %v", results, err)
+	}
+	if len(server.requests) != 1 {
+		t.Fatalf("requests = %q", server.requests)
+	}
+}
+
+func TestRunResumesPartialDownload(t *testing.T) {
+	server := newPDFServer(t, false)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.pdf"+partSuffix), samplePDF[:100], 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := NewDownloader(dir).Run(context.Background(), []Job{{URL: server.URL + "/paper.pdf", Name: "a.pdf"}})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !results[0].Resumed || results[0].Status != StatusDownloaded {
+		t.Fatalf("result = %+v", results[0])
+	}
+	if server.requests[0] != "/paper.pdf bytes=100-" {
+		t.Fatalf("requests = %q", server.requests)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, "a.pdf"))
+	if string(data) != string(samplePDF) {
+		t.Fatal("resumed file differs from the source")
+	}
+	manifest, _ := LoadManifest(dir)
+	sum := sha256.Sum256(samplePDF)
+	if manifest.Files["a.pdf"].SHA256 != hex.EncodeToString(sum[:]) {
+		t.Fatal("resumed hash should cover the whole file")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.pdf"+partSuffix)); !os.IsNotExist(err) {
+		t.Fatalf("part file should be gone: %v", err)
+	}
+}
+
+func TestRunRestartsWhenServerIgnoresRange(t *testing.T) {
+	server := newPDFServer(t, true)
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.pdf"+partSuffix), []byte("%PDF-stale"), 0o644)
+
+	results, _ := NewDownloader(dir).Run(context.Background(), []Job{{URL: server.URL + "/paper.pdf", Name: "a.pdf"}})
+	if results[0].Status != StatusDownloaded || results[0].Resumed {
+		t.Fatalf("result = %+v", results[0])
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "a.pdf"))
+	if string(data) != string(samplePDF) {
+		t.Fatal("file should be downloaded from scratch")
+	}
+}
+
+func TestRunRejectsNonPDFResponses(t *testing.T) {
+	server := newPDFServer(t, false)
+	dir := t.TempDir()
+	downloader := NewDownloader(dir)
+	downloader.MaxSize = 1000
+
+	results, err := downloader.Run(context.Background(), []Job{
+		{URL: server.URL + "/paywall.pdf", Name: "paywall.pdf"},
+		{URL: server.URL + "/mislabelled.pdf", Name: "mislabelled.pdf"},
+		{URL: server.URL + "/missing.pdf", Name: "missing.pdf"},
+		{URL: server.URL + "/paper.pdf", Name: "large.pdf"},
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	want := []string{"unexpected content type text/html", "response is not a PDF", "server returned 404", "over the 1000 B size limit"}
+	for i, result := range results {
+		if result.Status != StatusFailed || result.Err == nil || !strings.Contains(result.Err.Error(), want[i]) {
+			t.Errorf("%s: result = %+v, want error containing %q", result.Job.Name, result, want[i])
+		}
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Fatalf("failed downloads left files: %v", entries)
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	start, size, ok := parseContentRange("bytes 100-199/200")
+	if !ok || start != 100 || size != 200 {
+		t.Fatalf("got %d, %d, %t", start, size, ok)
+	}
+	if _, size, ok := parseContentRange("bytes 0-9/*"); !ok || size != -1 {
+		t.Fatalf("unknown size: %d, %t", size, ok)
+	}
+	if _, _, ok := parseContentRange("items 0-9/10"); ok {
+		t.Fatal("non-byte range should fail")
+	}
+}
+
+func TestRunNeverOverwritesAFileRecordedForAnotherURL(t *testing.T) {
+	server := newPDFServer(t, false)
+	dir := t.TempDir()
+	d := NewDownloader(dir)
+	if _, err := d.Run(context.Background(), []Job{{URL: server.URL + "/paper.pdf", Name: "smith.pdf"}}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	results, err := d.Run(context.Background(), []Job{{URL: server.URL + "/other.pdf", Name: "smith.pdf"}})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if results[0].Status != StatusFailed || !strings.Contains(results[0].Err.Error(), "already the download of") {
+		t.Fatalf("result = %+v", results[0])
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "smith.pdf")); string(data) != string(samplePDF) {
+		t.Fatal("smith.pdf was overwritten")
+	}
+}
@@ -0,0 +1,64 @@
+package download
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/paperzilla/pz/internal/config"
+)
+
+// ManifestName is the manifest file kept in each download directory.
+const ManifestName = ".pz-downloads.json"
+
+type Entry struct {
+	Ref          string    `json:"ref,omitempty"`
+	URL          string    `json:"url"`
+	Size         int64     `json:"size"`
+	SHA256       string    `json:"sha256"`
+	DownloadedAt time.Time `json:"downloaded_at"`
+}
+
+// Manifest records completed downloads keyed by file name.
+type Manifest struct {
+	Files map[string]Entry `json:"files"`
+
+	path string
+}
+
+func LoadManifest(dir string) (*Manifest, error) {
+	manifest := &Manifest{Files: map[string]Entry{}, path: filepath.Join(dir, ManifestName)}
+
+	data, err := os.ReadFile(manifest.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read download manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse download manifest %s: %w", manifest.path, err)
+	}
+	if manifest.Files == nil {
+		manifest.Files = map[string]Entry{}
+	}
+	return manifest, nil
+}
+
+// Save writes the manifest atomically so an interrupted run never leaves it
+// half-written.
+func (m *Manifest) Save() error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode download manifest: %w", err)
+	}
+
+	// The folder is often shared, so match the permissions of the PDFs.
+	if err := config.WriteFileAtomic(m.path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write download manifest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package download
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/paperzilla/pz/internal/authors"
+)
+
+// DefaultNameTemplate produces names like
+// "vaswani2017-ab12cd34-attention-is-all-you-need.pdf".
+const DefaultNameTemplate = "{author}{year}-{short_id}-{slug}"
+
+const maxSlugLength = 60
+
+// NameFields are the values available to a filename template.
+type NameFields struct {
+	Author  string // first author's citation key
+	Year    string
+	ShortID string
+	ID      string
+	Slug    string
+}
+
+var templateFields = map[string]func(NameFields) string{
+	"author":   func(f NameFields) string { return f.Author },
+	"year":     func(f NameFields) string { return f.Year },
+	"short_id": func(f NameFields) string { return f.ShortID },
+	"id":       func(f NameFields) string { return f.ID },
+	"slug":     func(f NameFields) string { return f.Slug },
+}
+
+// ValidateTemplate reports unknown or unterminated placeholders.
+func ValidateTemplate(template string) error {
+	_, err := RenderName(template, NameFields{ID: "x"})
+	return err
+}
+
+// RenderName expands {author}, {year}, {short_id}, {id} and {slug} in
+// template and returns a safe ".pdf" filename. Values are reduced to
+// lowercase ASCII letters, digits and hyphens, so names never contain path
+// separators. Separators left over by empty values are collapsed; if
+// nothing is left the ID is used.
+func RenderName(template string, fields NameFields) (string, error) {
+	var name strings.Builder
+	rest := strings.TrimSuffix(template, ".pdf")
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			name.WriteString(Slugify(rest))
+			break
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated placeholder in filename template %q", template)
+		}
+		key := rest[start+1 : start+end]
+		value, ok := templateFields[key]
+		if !ok {
+			return "", fmt.Errorf("unknown placeholder {%s} in filename template; use {author}, {year}, {short_id}, {id} or {slug}", key)
+		}
+		name.WriteString(Slugify(rest[:start]))
+		name.WriteString(Slugify(value(fields)))
+		rest = rest[start+end+1:]
+	}
+
+	result := collapseSeparators(name.String())
+	if result == "" {
+		result = collapseSeparators(Slugify(fields.ID))
+	}
+	return result + ".pdf", nil
+}
+
+// Slugify lowercases s, folds common diacritics and keeps ASCII letters,
+// digits, "-" and "_", turning everything else into "-".
+func Slugify(s string) string {
+	var b strings.Builder
+	for _, r := range authors.Fold(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// FieldsFor builds template fields from API paper data. The slug falls back
+// to the title and is shortened at a word boundary.
+func FieldsFor(id, shortID, slug, title, firstAuthor, publishedDate string) NameFields {
+	if strings.TrimSpace(slug) == "" {
+		slug = title
+	}
+	slug = collapseSeparators(Slugify(slug))
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength]
+		if cut := strings.LastIndexByte(slug, '-'); cut > maxSlugLength/2 {
+			slug = slug[:cut]
+		}
+	}
+
+	year := ""
+	if len(publishedDate) >= 4 && isDigits(publishedDate[:4]) {
+		year = publishedDate[:4]
+	}
+
+	author := ""
+	if strings.TrimSpace(firstAuthor) != "" {
+		author = authors.Parse(firstAuthor).CitationKey()
+	}
+
+	return NameFields{Author: author, Year: year, ShortID: shortID, ID: id, Slug: slug}
+}
+
+func collapseSeparators(s string) string {
+	var b strings.Builder
+	var pending byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '-' || c == '_' {
+			if pending == 0 || c == '_' {
+				pending = c
+			}
+			continue
+		}
+		if pending != 0 && b.Len() > 0 {
+			b.WriteByte(pending)
+		}
+		pending = 0
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,46 @@
+package download
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderName(t *testing.T) {
+	fields := FieldsFor("paper-1", "ab12cd34", "", "Attention Is All You Need!", "Ashish Vaswani", "2017-06-12T00:00:00Z")
+
+	tests := []struct {
+		template string
+		fields   NameFields
+		want     string
+	}{
+		{DefaultNameTemplate, fields, "vaswani2017-ab12cd34-attention-is-all-you-need.pdf"},
+		{"{year}/{author}_{slug}.pdf", fields, "2017-vaswani_attention-is-all-you-need.pdf"},
+		{"{author}-{year}-{short_id}", NameFields{ID: "paper-2", ShortID: "ff00"}, "ff00.pdf"},
+		{"{author}", NameFields{ID: "../etc/passwd"}, "etc-passwd.pdf"},
+		{"{slug}", FieldsFor("x", "", "", "Über Gödel's Beweis", "Kurt Gödel", ""), "uber-godel-s-beweis.pdf"},
+	}
+	for _, tt := range tests {
+		got, err := RenderName(tt.template, tt.fields)
+		if err != nil || got != tt.want {
+			t.Errorf("RenderName(%q) = %q, %v; want %q", tt.template, got, err, tt.want)
+		}
+	}
+}
+
+func TestRenderNameRejectsBadTemplates(t *testing.T) {
+	for _, template := range []string{"{title}", "{author"} {
+		if err := ValidateTemplate(template); err == nil {
+			t.Errorf("ValidateTemplate(%q) succeeded", template)
+		}
+	}
+}
+
+func TestFieldsForShortensLongSlugs(t *testing.T) {
+	fields := FieldsFor("x", "", strings.Repeat("graph-neural-networks-", 10), "", "van der Berg, Jan", "")
+	if len(fields.Slug) > maxSlugLength || strings.HasSuffix(fields.Slug, "-") {
+		t.Fatalf("slug = %q", fields.Slug)
+	}
+	if fields.Author != "vanderberg" {
+		t.Fatalf("author = %q", fields.Author)
+	}
+}
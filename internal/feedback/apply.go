@@ -0,0 +1,111 @@
+package feedback
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+)
+
+const (
+	DefaultWorkers  = 4
+	DefaultAttempts = 3
+
+	retryDelay = 500 * time.Millisecond
+)
+
+type Result struct {
+	Row      Row
+	Attempts int
+	Err      error
+}
+
+// Runner applies rows with bounded parallelism, retrying network errors,
+// rate limits and server errors.
+type Runner struct {
+	Workers  int
+	Attempts int
+	// Apply sends one row.
+	Apply func(Row) error
+	Sleep func(context.Context, time.Duration) error
+	// Progress is called once per row as it finishes, never concurrently.
+	Progress func(Result)
+}
+
+// Run applies all rows and returns one result per row, in row order. Rows
+// not started before ctx is cancelled fail with ctx.Err().
+func (r Runner) Run(ctx context.Context, rows []Row) []Result {
+	results := make([]Result, len(rows))
+	indexes := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for w := 0; w < max(r.Workers, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result := r.apply(ctx, rows[i])
+				mu.Lock()
+				results[i] = result
+				if r.Progress != nil {
+					r.Progress(result)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range rows {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+func (r Runner) apply(ctx context.Context, row Row) Result {
+	result := Result{Row: row}
+	attempts := max(r.Attempts, 1)
+	for {
+		if err := ctx.Err(); err != nil {
+			result.Err = err
+			return result
+		}
+		result.Attempts++
+		result.Err = r.Apply(row)
+		if result.Err == nil || result.Attempts >= attempts || !Retryable(result.Err) {
+			return result
+		}
+		if err := r.sleep(ctx, retryDelay<<(result.Attempts-1)); err != nil {
+			return result
+		}
+	}
+}
+
+func (r Runner) sleep(ctx context.Context, d time.Duration) error {
+	if r.Sleep != nil {
+		return r.Sleep(ctx, d)
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Retryable reports whether err may succeed on another attempt.
+func Retryable(err error) bool {
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == 429 || apiErr.StatusCode >= 500
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
@@ -0,0 +1,166 @@
+package feedback
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+)
+
+func TestParseReadsJSONLAndCSV(t *testing.T) {
+	want := []Row{
+		{Line: 2, Ref: "pp-1", Vote: "upvote"},
+		{Line: 3, Ref: "pp-2", Vote: "downvote", Reason: "low_quality"},
+	}
+
+	jsonl := "# exported from the sheet\n{\"ref\":\"pp-1\",\"vote\":\"upvote\"}\n{\"ref\":\" pp-2 \",\"vote\":\"Downvote\",\"reason\":\"low_quality\"}\n\n"
+	rows, err := Parse(strings.NewReader(jsonl), FormatJSONL)
+	if err != nil || !reflect.DeepEqual(rows, want) {
+		t.Fatalf("jsonl rows = %+v, err = %v", rows, err)
+	}
+
+	csv := "ref,vote,reason\npp-1,upvote\npp-2, downvote, low_quality\n"
+	rows, err = Parse(strings.NewReader(csv), FormatCSV)
+	if err != nil || !reflect.DeepEqual(rows, want) {
+		t.Fatalf("csv rows = %+v, err = %v", rows, err)
+	}
+
+	if DetectFormat("votes.CSV") != FormatCSV || DetectFormat("-") != FormatJSONL {
+		t.Fatal("DetectFormat picked the wrong format")
+	}
+}
+
+func TestParseReportsEveryInvalidRow(t *testing.T) {
+	input := strings.Join([]string{
+		`{"ref":"pp-1","vote":"upvote"}`,
+		`{"ref":"pp-2","vote":"meh"}`,
+		`{"ref":"pp-3","vote":"star","reason":"low_quality"}`,
+		`{"ref":"pp-1","vote":"star"}`,
+		`{"ref":"pp-4","vote":"downvote","reson":"typo"}`,
+		`not json`,
+	}, "\n")
+	_, err := Parse(strings.NewReader(input), FormatJSONL)
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("err = %v", err)
+	}
+	var lines []int
+	for _, p := range invalid.Problems {
+		lines = append(lines, p.Line)
+	}
+	if !reflect.DeepEqual(lines, []int{2, 3, 4, 5, 6}) {
+		t.Fatalf("problems = %+v", invalid.Problems)
+	}
+	if !strings.Contains(err.Error(), "line 4: pp-1 is already set on line 1") {
+		t.Fatalf("err = %v", err)
+	}
+
+	if _, err := Parse(strings.NewReader("pp-1\n"), FormatCSV); err == nil || !strings.Contains(err.Error(), "line 1: expected ref,vote[,reason]") {
+		t.Fatalf("csv err = %v", err)
+	}
+	if _, err := Parse(strings.NewReader("\n"), FormatJSONL); err == nil {
+		t.Fatal("empty input should fail")
+	}
+}
+
+func TestRunnerRetriesTransientErrorsOnly(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	runner := Runner{
+		Workers:  2,
+		Attempts: 3,
+		Sleep:    func(context.Context, time.Duration) error { return nil },
+		Apply: func(row Row) error {
+			mu.Lock()
+			calls[row.Ref]++
+			n := calls[row.Ref]
+			mu.Unlock()
+			switch row.Ref {
+			case "flaky":
+				if n < 3 {
+					return &api.APIError{StatusCode: 503}
+				}
+			case "missing":
+				return &api.APIError{StatusCode: 404}
+			case "down":
+				return &api.APIError{StatusCode: 500}
+			}
+			return nil
+		},
+	}
+	var progress int
+	runner.Progress = func(Result) { progress++ }
+
+	results := runner.Run(context.Background(), []Row{{Ref: "ok"}, {Ref: "flaky"}, {Ref: "missing"}, {Ref: "down"}})
+	got := map[string]int{}
+	for _, result := range results {
+		got[result.Row.Ref] = result.Attempts
+		if (result.Err != nil) != (result.Row.Ref == "missing" || result.Row.Ref == "down") {
+			t.Errorf("%s: err = %v", result.Row.Ref, result.Err)
+		}
+	}
+	if !reflect.DeepEqual(got, map[string]int{"ok": 1, "flaky": 3, "missing": 1, "down": 3}) {
+		t.Fatalf("attempts = %v", got)
+	}
+	if results[0].Row.Ref != "ok" || results[3].Row.Ref != "down" || progress != 4 {
+		t.Fatalf("results out of order or progress = %d", progress)
+	}
+}
+
+func TestRunnerBoundsConcurrency(t *testing.T) {
+	var active, peak atomic.Int32
+	runner := Runner{
+		Workers: 3,
+		Apply: func(Row) error {
+			n := active.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			active.Add(-1)
+			return nil
+		},
+	}
+	runner.Run(context.Background(), make([]Row, 12))
+	if peak.Load() > 3 {
+		t.Fatalf("peak concurrency = %d", peak.Load())
+	}
+}
+
+func TestLogAppendsAndFindsUndoableChanges(t *testing.T) {
+	log := &Log{Path: filepath.Join(t.TempDir(), "nested", "feedback-log.jsonl")}
+	if entries, err := log.Entries(); err != nil || len(entries) != 0 {
+		t.Fatalf("missing log: entries = %v, err = %v", entries, err)
+	}
+
+	first, err := log.Append(LogEntry{Profile: "me", Ref: "pp-1", New: &api.Feedback{Vote: "star"}})
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	second, _ := log.Append(LogEntry{Profile: "me", Ref: "pp-2", Previous: &api.Feedback{Vote: "upvote"}})
+	_, _ = log.Append(LogEntry{Profile: "other", Ref: "pp-3"})
+	_, _ = log.Append(LogEntry{Profile: "me", Ref: "pp-2", New: &api.Feedback{Vote: "upvote"}, Undoes: second.ID})
+
+	entries, err := log.Entries()
+	if err != nil || len(entries) != 4 {
+		t.Fatalf("entries = %v, err = %v", entries, err)
+	}
+	if first.ID == "" || first.Time.IsZero() || entries[0].ID != first.ID || entries[0].New.Vote != "star" {
+		t.Fatalf("first entry = %+v", entries[0])
+	}
+
+	undoable := Undoable(entries, "me", 5)
+	if len(undoable) != 1 || undoable[0].ID != first.ID {
+		t.Fatalf("undoable = %+v", undoable)
+	}
+}
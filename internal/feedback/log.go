@@ -0,0 +1,128 @@
+package feedback
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+)
+
+// LogEntry is one feedback change. Previous and New are nil when the
+// recommendation had no feedback.
+type LogEntry struct {
+	ID       string        `json:"id"`
+	Time     time.Time     `json:"time"`
+	Profile  string        `json:"profile"`
+	Ref      string        `json:"ref"`
+	Title    string        `json:"title,omitempty"`
+	Previous *api.Feedback `json:"previous"`
+	New      *api.Feedback `json:"new"`
+	// Undoes is the ID of the entry this change reverted.
+	Undoes string `json:"undoes,omitempty"`
+}
+
+// Log is an append-only JSON lines file of feedback changes.
+type Log struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// Append writes e as one line, filling in its ID and time if unset.
+func (l *Log) Append(e LogEntry) (LogEntry, error) {
+	if e.ID == "" {
+		e.ID = newEntryID()
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	e.Time = e.Time.UTC()
+	data, err := json.Marshal(e)
+	if err != nil {
+		return e, fmt.Errorf("failed to encode feedback log entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.Path), 0o700); err != nil {
+		return e, fmt.Errorf("failed to write feedback log: %w", err)
+	}
+	f, err := os.OpenFile(l.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return e, fmt.Errorf("failed to write feedback log: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return e, fmt.Errorf("failed to write feedback log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return e, fmt.Errorf("failed to write feedback log: %w", err)
+	}
+	return e, nil
+}
+
+// Entries returns every entry, oldest first. A missing log is empty.
+func (l *Log) Entries() ([]LogEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.Open(l.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feedback log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []LogEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("failed to parse feedback log %s line %d: %w", l.Path, line, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read feedback log: %w", err)
+	}
+	return entries, nil
+}
+
+// Undoable returns up to n of profile's most recent changes, newest first,
+// skipping undos and changes that were already undone.
+func Undoable(entries []LogEntry, profile string, n int) []LogEntry {
+	undone := map[string]bool{}
+	for _, e := range entries {
+		if e.Undoes != "" {
+			undone[e.Undoes] = true
+		}
+	}
+	var result []LogEntry
+	for i := len(entries) - 1; i >= 0 && len(result) < n; i-- {
+		e := entries[i]
+		if e.Profile != profile || e.Undoes != "" || undone[e.ID] {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+func newEntryID() string {
+	var random [8]byte
+	_, _ = rand.Read(random[:])
+	return hex.EncodeToString(random[:])
+}
@@ -0,0 +1,211 @@
+// Package feedback reads bulk feedback files and applies them with bounded
+// concurrency.
+package feedback
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+)
+
+// Row is one feedback change. Line is where it appeared in the input.
+type Row struct {
+	Line   int    `json:"line"`
+	Ref    string `json:"ref"`
+	Vote   string `json:"vote"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Problem is one invalid row.
+type Problem struct {
+	Line    int
+	Message string
+}
+
+// ValidationError lists every invalid row, so a file can be fixed in one go.
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		lines[i] = fmt.Sprintf("line %d: %s", p.Line, p.Message)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DetectFormat picks a format from a file name; anything that is not .csv,
+// including stdin, is read as JSON lines.
+func DetectFormat(name string) string {
+	if strings.EqualFold(filepath.Ext(name), ".csv") {
+		return FormatCSV
+	}
+	return FormatJSONL
+}
+
+// Parse reads and validates all rows. Nothing is returned unless every row
+// is valid.
+func Parse(r io.Reader, format string) ([]Row, error) {
+	var rows []Row
+	var problems []Problem
+	var err error
+	switch format {
+	case FormatJSONL:
+		rows, problems, err = parseJSONL(r)
+	case FormatCSV:
+		rows, problems, err = parseCSV(r)
+	default:
+		return nil, fmt.Errorf("unknown format %q (expected jsonl or csv)", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]int{}
+	for _, row := range rows {
+		if message := validate(row); message != "" {
+			problems = append(problems, Problem{row.Line, message})
+			continue
+		}
+		if first, ok := seen[row.Ref]; ok {
+			problems = append(problems, Problem{row.Line, fmt.Sprintf("%s is already set on line %d", row.Ref, first)})
+			continue
+		}
+		seen[row.Ref] = row.Line
+	}
+	if len(problems) > 0 {
+		sort.SliceStable(problems, func(i, j int) bool { return problems[i].Line < problems[j].Line })
+		return nil, &ValidationError{Problems: problems}
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("no feedback rows found")
+	}
+	return rows, nil
+}
+
+func validate(row Row) string {
+	if row.Ref == "" {
+		return "ref is required"
+	}
+	switch row.Vote {
+	case "upvote", "star", "clear":
+		if row.Reason != "" {
+			return "reason is only allowed with downvote"
+		}
+	case "downvote":
+		switch row.Reason {
+		case "", "not_relevant", "low_quality":
+		default:
+			return fmt.Sprintf("invalid reason %q (expected not_relevant or low_quality)", row.Reason)
+		}
+	case "":
+		return "vote is required"
+	default:
+		return fmt.Sprintf("invalid vote %q (expected upvote, downvote, star, or clear)", row.Vote)
+	}
+	return ""
+}
+
+func normalize(row Row) Row {
+	row.Ref = strings.TrimSpace(row.Ref)
+	row.Vote = strings.ToLower(strings.TrimSpace(row.Vote))
+	row.Reason = strings.ToLower(strings.TrimSpace(row.Reason))
+	return row
+}
+
+// parseJSONL reads one object per line. Blank lines and lines starting with
+// # are skipped.
+func parseJSONL(r io.Reader) ([]Row, []Problem, error) {
+	var rows []Row
+	var problems []Problem
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 || text[0] == '#' {
+			continue
+		}
+		var row Row
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row); err != nil {
+			problems = append(problems, Problem{line, fmt.Sprintf("invalid JSON: %v", err)})
+			continue
+		}
+		row.Line = line
+		rows = append(rows, normalize(row))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read feedback rows: %w", err)
+	}
+	return rows, problems, nil
+}
+
+// parseCSV reads ref,vote[,reason] records. A first record starting with
+// "ref" is a header; with one, columns are matched by name and others are
+// ignored, so exports with extra columns can be applied as they are.
+func parseCSV(r io.Reader) ([]Row, []Problem, error) {
+	var rows []Row
+	var problems []Problem
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	columns := map[string]int{"ref": 0, "vote": 1, "reason": 2}
+	header := false
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				problems = append(problems, Problem{parseErr.Line, parseErr.Err.Error()})
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to read feedback rows: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if first && strings.EqualFold(strings.TrimSpace(record[0]), "ref") {
+			header = true
+			columns = map[string]int{}
+			for i, name := range record {
+				columns[strings.ToLower(strings.TrimSpace(name))] = i
+			}
+			if _, ok := columns["vote"]; !ok {
+				problems = append(problems, Problem{line, "header has no vote column"})
+				return rows, problems, nil
+			}
+			continue
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if !header && (len(record) < 2 || len(record) > 3) {
+			problems = append(problems, Problem{line, fmt.Sprintf("expected ref,vote[,reason], got %d fields", len(record))})
+			continue
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		rows = append(rows, normalize(Row{Line: line, Ref: field("ref"), Vote: field("vote"), Reason: field("reason")}))
+	}
+	return rows, problems, nil
+}
@@ -0,0 +1,192 @@
+// Package feedmerge merges the feeds of several projects into one reading
+// list, with one entry per canonical paper.
+package feedmerge
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/authors"
+)
+
+// Sort orders accepted by Sort.
+const (
+	SortReady = "ready"
+	SortScore = "score"
+)
+
+type Project struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Feed is the fetched feed of one project.
+type Feed struct {
+	Project Project
+	Items   []api.ProjectPaper
+}
+
+// Recommendation is one project's recommendation of a paper. ID is the
+// project paper ID, which pz rec, pz open and pz feedback take.
+type Recommendation struct {
+	ProjectID      string        `json:"project_id"`
+	ProjectName    string        `json:"project_name"`
+	ID             string        `json:"id"`
+	RelevanceScore float64       `json:"relevance_score"`
+	RelevanceClass int           `json:"relevance_class"`
+	ReadyAt        string        `json:"ready_at"`
+	Feedback       *api.Feedback `json:"feedback"`
+}
+
+// Item is a paper recommended by one or more projects. ReadyAt is when it
+// first reached any of them and BestScore is its highest relevance.
+// Recommendations are ordered by relevance, best first.
+type Item struct {
+	PaperTitle      string           `json:"paper_title"`
+	ReadyAt         string           `json:"ready_at"`
+	BestScore       float64          `json:"best_score"`
+	MustRead        bool             `json:"must_read"`
+	Paper           api.Paper        `json:"paper"`
+	Recommendations []Recommendation `json:"recommendations"`
+}
+
+// Merge combines feeds by canonical paper ID. Items without one are kept
+// apart, keyed by their project paper ID. A project that lists the same
+// paper twice contributes one recommendation.
+func Merge(feeds []Feed) []Item {
+	items := []Item{}
+	index := map[string]int{}
+	for _, feed := range feeds {
+		for _, p := range feed.Items {
+			key := "paper:" + p.Paper.ID
+			if p.Paper.ID == "" {
+				key = "project-paper:" + p.ID
+			}
+			rec := Recommendation{
+				ProjectID:      feed.Project.ID,
+				ProjectName:    feed.Project.Name,
+				ID:             p.ID,
+				RelevanceScore: p.RelevanceScore,
+				RelevanceClass: p.RelevanceClass,
+				ReadyAt:        p.ReadyAt,
+				Feedback:       p.Feedback,
+			}
+
+			i, ok := index[key]
+			if !ok {
+				index[key] = len(items)
+				items = append(items, Item{
+					PaperTitle:      p.PaperTitle,
+					ReadyAt:         p.ReadyAt,
+					BestScore:       p.RelevanceScore,
+					MustRead:        p.RelevanceClass == 2,
+					Paper:           p.Paper,
+					Recommendations: []Recommendation{rec},
+				})
+				continue
+			}
+
+			item := &items[i]
+			if hasProject(item.Recommendations, feed.Project.ID) {
+				continue
+			}
+			item.Recommendations = append(item.Recommendations, rec)
+			item.BestScore = max(item.BestScore, p.RelevanceScore)
+			item.MustRead = item.MustRead || p.RelevanceClass == 2
+			if earlier(p.ReadyAt, item.ReadyAt) {
+				item.ReadyAt = p.ReadyAt
+			}
+			if item.PaperTitle == "" {
+				item.PaperTitle = p.PaperTitle
+			}
+		}
+	}
+
+	for _, item := range items {
+		sort.SliceStable(item.Recommendations, func(i, j int) bool {
+			return item.Recommendations[i].RelevanceScore > item.Recommendations[j].RelevanceScore
+		})
+	}
+	return items
+}
+
+// ParseSort checks a --sort value; empty means SortReady.
+func ParseSort(value string) (string, error) {
+	switch value = strings.ToLower(strings.TrimSpace(value)); value {
+	case "":
+		return SortReady, nil
+	case SortReady, SortScore:
+		return value, nil
+	}
+	return "", fmt.Errorf("invalid sort %q (expected %s or %s)", value, SortReady, SortScore)
+}
+
+// Sort orders items newest first (SortReady) or by best score (SortScore),
+// using the other key, then the first author and then the title to break
+// ties.
+func Sort(items []Item, by string) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		newer := ready(a).After(ready(b))
+		sameReady := ready(a).Equal(ready(b))
+		switch {
+		case by == SortScore && a.BestScore != b.BestScore:
+			return a.BestScore > b.BestScore
+		case !sameReady:
+			return newer
+		case a.BestScore != b.BestScore:
+			return a.BestScore > b.BestScore
+		}
+		if authorA, authorB := firstAuthor(a), firstAuthor(b); authorA.SortKey() != authorB.SortKey() {
+			return authors.Less(authorA, authorB)
+		}
+		return a.PaperTitle < b.PaperTitle
+	})
+}
+
+func firstAuthor(item Item) authors.Name {
+	if len(item.Paper.Authors) == 0 {
+		return authors.Name{}
+	}
+	return authors.Parse(item.Paper.Authors[0].Name)
+}
+
+func hasProject(recs []Recommendation, projectID string) bool {
+	for _, rec := range recs {
+		if rec.ProjectID == projectID {
+			return true
+		}
+	}
+	return false
+}
+
+// earlier reports whether a is before b. Unparseable times never win, so
+// one bad timestamp does not hide a good one.
+func earlier(a, b string) bool {
+	at, aOK := parseTime(a)
+	bt, bOK := parseTime(b)
+	return aOK && (!bOK || at.Before(bt))
+}
+
+func ready(item Item) time.Time {
+	return ReadyTime(item.ReadyAt)
+}
+
+// ReadyTime parses a ready_at timestamp, or returns the zero time when it
+// cannot be parsed.
+func ReadyTime(value string) time.Time {
+	t, _ := parseTime(value)
+	return t
+}
+
+func parseTime(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+		if t, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
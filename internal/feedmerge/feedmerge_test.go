@@ -0,0 +1,88 @@
+package feedmerge
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/paperzilla/pz/internal/api"
+)
+
+func testPaper(id, paperID, title string, score float64, class int, ready string) api.ProjectPaper {
+	return api.ProjectPaper{ID: id, PaperTitle: title, RelevanceScore: score, RelevanceClass: class, ReadyAt: ready,
+		Paper: api.Paper{ID: paperID}}
+}
+
+func titles(items []Item) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = item.PaperTitle
+	}
+	return out
+}
+
+func TestMergeCombinesRecommendationsByPaper(t *testing.T) {
+	feeds := []Feed{
+		{Project: Project{ID: "a", Name: "Graphs"}, Items: []api.ProjectPaper{
+			testPaper("pp-a1", "paper-1", "Shared", 0.6, 1, "2025-09-03T08:00:00Z"),
+			testPaper("pp-a2", "", "No canonical ID", 0.5, 1, "2025-09-02T08:00:00Z"),
+			testPaper("pp-a3", "paper-1", "Shared", 0.6, 1, "2025-09-03T08:00:00Z"),
+		}},
+		{Project: Project{ID: "b", Name: "Proteins"}, Items: []api.ProjectPaper{
+			testPaper("pp-b1", "paper-1", "Shared", 0.9, 2, "2025-09-01T08:00:00Z"),
+			testPaper("pp-b2", "", "No canonical ID", 0.4, 1, "2025-09-02T08:00:00Z"),
+		}},
+	}
+	items := Merge(feeds)
+
+	if got := titles(items); !reflect.DeepEqual(got, []string{"Shared", "No canonical ID", "No canonical ID"}) {
+		t.Fatalf("titles = %v", got)
+	}
+	shared := items[0]
+	if shared.BestScore != 0.9 || !shared.MustRead || shared.ReadyAt != "2025-09-01T08:00:00Z" {
+		t.Fatalf("shared = %+v", shared)
+	}
+	if len(shared.Recommendations) != 2 || shared.Recommendations[0].ID != "pp-b1" || shared.Recommendations[1].ProjectName != "Graphs" {
+		t.Fatalf("recommendations = %+v", shared.Recommendations)
+	}
+}
+
+func TestSort(t *testing.T) {
+	items := []Item{
+		{PaperTitle: "Old but great", BestScore: 0.99, ReadyAt: "2025-09-01T08:00:00Z"},
+		{PaperTitle: "New", BestScore: 0.5, ReadyAt: "2025-09-03T08:00:00Z"},
+		{PaperTitle: "New and better", BestScore: 0.7, ReadyAt: "2025-09-03T08:00:00Z"},
+		{PaperTitle: "Undated", BestScore: 0.99},
+	}
+
+	Sort(items, SortReady)
+	if got := titles(items); !reflect.DeepEqual(got, []string{"New and better", "New", "Old but great", "Undated"}) {
+		t.Fatalf("by ready = %v", got)
+	}
+	Sort(items, SortScore)
+	if got := titles(items); !reflect.DeepEqual(got, []string{"Old but great", "Undated", "New and better", "New"}) {
+		t.Fatalf("by score = %v", got)
+	}
+}
+
+func TestSortBreaksTiesByFirstAuthor(t *testing.T) {
+	item := func(title, author string) Item {
+		return Item{PaperTitle: title, BestScore: 0.5, ReadyAt: "2025-09-03T08:00:00Z",
+			Paper: api.Paper{Authors: []api.Author{{Name: author}}}}
+	}
+	items := []Item{item("A", "Ludwig van Beethoven"), item("B", "Bach, Johann Sebastian"), item("C", "Li Wei")}
+	Sort(items, SortReady)
+	if got := titles(items); !reflect.DeepEqual(got, []string{"B", "A", "C"}) {
+		t.Fatalf("titles = %v", got)
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	for value, want := range map[string]string{"": SortReady, "Score": SortScore, "ready": SortReady} {
+		if got, err := ParseSort(value); err != nil || got != want {
+			t.Fatalf("ParseSort(%q) = %q, %v", value, got, err)
+		}
+	}
+	if _, err := ParseSort("title"); err == nil {
+		t.Fatal("ParseSort(title) succeeded")
+	}
+}
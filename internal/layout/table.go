@@ -0,0 +1,145 @@
+package layout
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const columnGap = "  "
+
+// Column describes one table column. Flexible columns are truncated before
+// any column is dropped; when shrinking is not enough, columns are dropped in
+// ascending Priority order, rightmost first on ties. MinWidth bounds how far a
+// flexible column may shrink.
+type Column struct {
+	Header     string
+	Priority   int
+	Flexible   bool
+	MinWidth   int
+	AlignRight bool
+}
+
+// Table renders rows of pre-escaped cells aligned by display width.
+type Table struct {
+	Columns []Column
+	Rows    [][]string
+}
+
+// Render writes the table so each line fits maxWidth cells. A maxWidth of
+// zero or less disables fitting and every column keeps its natural width.
+func (t Table) Render(w io.Writer, maxWidth int) error {
+	visible, widths := t.fit(maxWidth)
+	if len(visible) == 0 {
+		return nil
+	}
+
+	header := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		header[i] = column.Header
+	}
+	if err := t.writeRow(w, header, visible, widths); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		if err := t.writeRow(w, row, visible, widths); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t Table) writeRow(w io.Writer, row []string, visible []int, widths map[int]int) error {
+	cells := make([]string, 0, len(visible))
+	for n, i := range visible {
+		cell := ""
+		if i < len(row) {
+			cell = row[i]
+		}
+		cell = Truncate(cell, widths[i])
+		switch {
+		case t.Columns[i].AlignRight:
+			cell = PadLeft(cell, widths[i])
+		case n < len(visible)-1:
+			cell = PadRight(cell, widths[i])
+		}
+		cells = append(cells, cell)
+	}
+	_, err := fmt.Fprintln(w, strings.TrimRight(strings.Join(cells, columnGap), " "))
+	return err
+}
+
+func (t Table) naturalWidths() []int {
+	widths := make([]int, len(t.Columns))
+	for i, column := range t.Columns {
+		widths[i] = Width(column.Header)
+	}
+	for _, row := range t.Rows {
+		for i := range t.Columns {
+			if i < len(row) {
+				widths[i] = max(widths[i], Width(row[i]))
+			}
+		}
+	}
+	return widths
+}
+
+// fit returns the indexes of the columns to show and their widths.
+func (t Table) fit(maxWidth int) ([]int, map[int]int) {
+	natural := t.naturalWidths()
+	visible := make([]int, len(t.Columns))
+	for i := range visible {
+		visible[i] = i
+	}
+
+	for {
+		widths := make(map[int]int, len(visible))
+		total := Width(columnGap) * (len(visible) - 1)
+		for _, i := range visible {
+			widths[i] = natural[i]
+			total += natural[i]
+		}
+		if maxWidth <= 0 || total <= maxWidth {
+			return visible, widths
+		}
+
+		if t.shrink(visible, widths, total-maxWidth) || len(visible) == 1 {
+			return visible, widths
+		}
+		visible = t.dropLowestPriority(visible)
+	}
+}
+
+// shrink takes excess cells from flexible columns, widest first, and
+// reports whether the table now fits.
+func (t Table) shrink(visible []int, widths map[int]int, excess int) bool {
+	for excess > 0 {
+		widest := -1
+		for _, i := range visible {
+			column := t.Columns[i]
+			floor := max(column.MinWidth, Width(Ellipsis)+1, 1)
+			if !column.Flexible || widths[i] <= floor {
+				continue
+			}
+			if widest < 0 || widths[i] > widths[widest] {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			return false
+		}
+		widths[widest]--
+		excess--
+	}
+	return true
+}
+
+func (t Table) dropLowestPriority(visible []int) []int {
+	drop := 0
+	for n, i := range visible {
+		if t.Columns[i].Priority <= t.Columns[visible[drop]].Priority {
+			drop = n
+		}
+	}
+	return append(append([]int{}, visible[:drop]...), visible[drop+1:]...)
+}
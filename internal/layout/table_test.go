@@ -0,0 +1,76 @@
+package layout
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func testTable() Table {
+	return Table{
+		Columns: []Column{
+			{Header: "ID", Priority: 3},
+			{Header: "NAME", Priority: 4, Flexible: true, MinWidth: 8},
+			{Header: "MODE", Priority: 1},
+			{Header: "SCORE", Priority: 2, AlignRight: true},
+		},
+		Rows: [][]string{
+			{"p-1", "Machine Learning Papers", "auto", "92"},
+			{"p-2", "日本語のプロジェクト", "manual", "7"},
+		},
+	}
+}
+
+func TestTableRenderNaturalWidthWhenUnbounded(t *testing.T) {
+	var out bytes.Buffer
+	if err := testTable().Render(&out, 0); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	want := "" +
+		"ID   NAME                     MODE    SCORE\n" +
+		"p-1  Machine Learning Papers  auto       92\n" +
+		"p-2  日本語のプロジェクト     manual      7\n"
+	if out.String() != want {
+		t.Fatalf("Render() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestTableRenderShrinksFlexibleColumnFirst(t *testing.T) {
+	var out bytes.Buffer
+	if err := testTable().Render(&out, 36); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		if Width(line) > 36 {
+			t.Fatalf("line %q is %d cells wide, want <= 36", line, Width(line))
+		}
+	}
+	if !strings.Contains(out.String(), "MODE") || !strings.Contains(out.String(), "SCORE") {
+		t.Fatalf("shrinking should keep every column:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "Machine Learn...") {
+		t.Fatalf("name was not truncated:\n%s", out.String())
+	}
+}
+
+func TestTableRenderDropsLowPriorityColumns(t *testing.T) {
+	var out bytes.Buffer
+	if err := testTable().Render(&out, 16); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	output := out.String()
+	if strings.Contains(output, "MODE") || strings.Contains(output, "SCORE") {
+		t.Fatalf("low-priority columns should be dropped:\n%s", output)
+	}
+	if !strings.Contains(output, "ID") || !strings.Contains(output, "NAME") {
+		t.Fatalf("high-priority columns should survive:\n%s", output)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+		if Width(line) > 16 {
+			t.Fatalf("line %q is %d cells wide, want <= 16", line, Width(line))
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package layout
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Ellipsis marks text that was shortened to fit a column.
+const Ellipsis = "..."
+
+const zeroWidthJoiner = '\u200d'
+
+// wideRanges lists East Asian Wide and Fullwidth blocks plus the emoji
+// blocks terminals render with two cells.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115f}, {0x231a, 0x231b}, {0x2329, 0x232a}, {0x23e9, 0x23ec},
+	{0x23f0, 0x23f0}, {0x23f3, 0x23f3}, {0x25fd, 0x25fe}, {0x2614, 0x2615},
+	{0x2648, 0x2653}, {0x267f, 0x267f}, {0x2693, 0x2693}, {0x26a1, 0x26a1},
+	{0x26aa, 0x26ab}, {0x26bd, 0x26be}, {0x26c4, 0x26c5}, {0x26ce, 0x26ce},
+	{0x26d4, 0x26d4}, {0x26ea, 0x26ea}, {0x26f2, 0x26f3}, {0x26f5, 0x26f5},
+	{0x26fa, 0x26fa}, {0x26fd, 0x26fd}, {0x2705, 0x2705}, {0x270a, 0x270b},
+	{0x2728, 0x2728}, {0x274c, 0x274c}, {0x274e, 0x274e}, {0x2753, 0x2755},
+	{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27b0, 0x27b0}, {0x27bf, 0x27bf},
+	{0x2b1b, 0x2b1c}, {0x2b50, 0x2b50}, {0x2b55, 0x2b55}, {0x2e80, 0x303e},
+	{0x3041, 0x33ff}, {0x3400, 0x4dbf}, {0x4e00, 0x9fff}, {0xa000, 0xa4cf},
+	{0xa960, 0xa97f}, {0xac00, 0xd7a3}, {0xf900, 0xfaff}, {0xfe10, 0xfe19},
+	{0xfe30, 0xfe6f}, {0xff00, 0xff60}, {0xffe0, 0xffe6}, {0x16fe0, 0x16fe4},
+	{0x17000, 0x18aff}, {0x1b000, 0x1b2ff}, {0x1f004, 0x1f004}, {0x1f0cf, 0x1f0cf},
+	{0x1f18e, 0x1f18e}, {0x1f191, 0x1f19a}, {0x1f200, 0x1f251}, {0x1f300, 0x1f64f},
+	{0x1f680, 0x1f6ff}, {0x1f7e0, 0x1f7eb}, {0x1f90c, 0x1f9ff}, {0x1fa70, 0x1faff},
+	{0x20000, 0x2fffd}, {0x30000, 0x3fffd},
+}
+
+// RuneWidth returns the number of terminal cells r occupies on its own.
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0, r < 0x20, r >= 0x7f && r < 0xa0:
+		return 0
+	case r < 0x300:
+		return 1
+	case isZeroWidth(r):
+		return 0
+	case isWide(r):
+		return 2
+	default:
+		return 1
+	}
+}
+
+func isZeroWidth(r rune) bool {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return true
+	case r >= 0x1160 && r <= 0x11ff:
+		// Hangul medial vowels and final consonants join the preceding syllable.
+		return true
+	case isEmojiModifier(r):
+		return true
+	default:
+		return false
+	}
+}
+
+func isWide(r rune) bool {
+	lo, hi := 0, len(wideRanges)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		switch {
+		case r < wideRanges[mid][0]:
+			hi = mid
+		case r > wideRanges[mid][1]:
+			lo = mid + 1
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+func isEmojiModifier(r rune) bool {
+	return r >= 0x1f3fb && r <= 0x1f3ff
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+func isVariationSelector(r rune) bool {
+	return r >= 0xfe00 && r <= 0xfe0f
+}
+
+func extendsCluster(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		isVariationSelector(r) ||
+		isEmojiModifier(r) ||
+		r == zeroWidthJoiner
+}
+
+// Graphemes splits s into user-perceived characters: a base rune plus any
+// combining marks, variation selectors, emoji modifiers, zero-width-joiner
+// sequences and regional-indicator flag pairs that follow it.
+func Graphemes(s string) []string {
+	var clusters []string
+	for len(s) > 0 {
+		n := nextGraphemeLen(s)
+		clusters = append(clusters, s[:n])
+		s = s[n:]
+	}
+	return clusters
+}
+
+func nextGraphemeLen(s string) int {
+	first, size := utf8.DecodeRuneInString(s)
+	end := size
+	prev := first
+	regionalCount := 0
+	if isRegionalIndicator(first) {
+		regionalCount = 1
+	}
+
+	for end < len(s) {
+		r, n := utf8.DecodeRuneInString(s[end:])
+		switch {
+		case prev == zeroWidthJoiner, extendsCluster(r):
+			// Joined emoji sequences and combining marks stay with their base.
+		case isRegionalIndicator(r) && regionalCount == 1:
+			regionalCount++
+		default:
+			return end
+		}
+		end += n
+		prev = r
+	}
+	return end
+}
+
+// GraphemeWidth returns the display width of one grapheme cluster.
+func GraphemeWidth(cluster string) int {
+	width := 0
+	emojiPresentation := false
+	for i, r := range cluster {
+		if i == 0 {
+			width = RuneWidth(r)
+			continue
+		}
+		if r == 0xfe0f {
+			emojiPresentation = true
+		}
+		if isRegionalIndicator(r) {
+			width = 2
+		}
+	}
+	if emojiPresentation && width == 1 {
+		width = 2
+	}
+	return width
+}
+
+// Width returns the number of terminal cells s occupies.
+func Width(s string) int {
+	width := 0
+	for len(s) > 0 {
+		n := nextGraphemeLen(s)
+		width += GraphemeWidth(s[:n])
+		s = s[n:]
+	}
+	return width
+}
+
+// Truncate shortens s to at most width cells, cutting only on grapheme
+// boundaries and appending Ellipsis when anything was removed.
+func Truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if Width(s) <= width {
+		return s
+	}
+
+	suffix := Ellipsis
+	if width <= Width(Ellipsis) {
+		suffix = ""
+	}
+	budget := width - Width(suffix)
+
+	var out strings.Builder
+	used := 0
+	for len(s) > 0 {
+		n := nextGraphemeLen(s)
+		w := GraphemeWidth(s[:n])
+		if used+w > budget {
+			break
+		}
+		out.WriteString(s[:n])
+		used += w
+		s = s[n:]
+	}
+	return out.String() + suffix
+}
+
+// PadRight appends spaces so s fills width cells.
+func PadRight(s string, width int) string {
+	if gap := width - Width(s); gap > 0 {
+		return s + strings.Repeat(" ", gap)
+	}
+	return s
+}
+
+// PadLeft prepends spaces so s fills width cells.
+func PadLeft(s string, width int) string {
+	if gap := width - Width(s); gap > 0 {
+		return strings.Repeat(" ", gap) + s
+	}
+	return s
+}
+
+// Wrap breaks s into lines of at most width cells at spaces, splitting
+// words that are wider than a whole line on grapheme boundaries. Existing
+// newlines start new lines.
+func Wrap(s string, width int) []string {
+	if width <= 0 {
+		return strings.Split(s, "\n")
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		line, used := "", 0
+		for _, word := range strings.Fields(paragraph) {
+			for Width(word) > width {
+				if used > 0 {
+					lines = append(lines, line)
+					line, used = "", 0
+				}
+				head := cut(word, width)
+				lines = append(lines, head)
+				word = word[len(head):]
+			}
+			w := Width(word)
+			switch {
+			case used == 0:
+				line, used = word, w
+			case used+1+w <= width:
+				line += " " + word
+				used += 1 + w
+			default:
+				lines = append(lines, line)
+				line, used = word, w
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// cut returns the longest prefix of s that fits in width cells.
+func cut(s string, width int) string {
+	used, end := 0, 0
+	for end < len(s) {
+		n := nextGraphemeLen(s[end:])
+		w := GraphemeWidth(s[end : end+n])
+		if used+w > width {
+			break
+		}
+		used += w
+		end += n
+	}
+	if end == 0 && len(s) > 0 {
+		end = nextGraphemeLen(s)
+	}
+	return s[:end]
+}
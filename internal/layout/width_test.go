@@ -0,0 +1,110 @@
+package layout
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{name: "ascii", input: "Transformer", want: 11},
+		{name: "greek", input: "Ελληνικά", want: 8},
+		{name: "cjk", input: "日本語", want: 6},
+		{name: "hangul", input: "한국어", want: 6},
+		{name: "fullwidth", input: "ＡＢ", want: 4},
+		{name: "combining acute", input: "Café", want: 4},
+		{name: "emoji", input: "🚀", want: 2},
+		{name: "emoji with skin tone", input: "👍🏽", want: 2},
+		{name: "zwj family", input: "👨‍👩‍👧", want: 2},
+		{name: "flag", input: "🇳🇱", want: 2},
+		{name: "heart with emoji presentation", input: "❤️", want: 2},
+		{name: "symbols", input: "★ Must Read [↑]", want: 15},
+		{name: "empty", input: "", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Width(tt.input); got != tt.want {
+				t.Fatalf("Width(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraphemesKeepClustersTogether(t *testing.T) {
+	got := Graphemes("é👍🏽🇳🇱a")
+	want := []string{"é", "👍🏽", "🇳🇱", "a"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("Graphemes() = %q, want %q", got, want)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		width int
+		want  string
+	}{
+		{name: "fits", input: "short", width: 10, want: "short"},
+		{name: "ascii", input: "A Novel Approach", width: 10, want: "A Novel..."},
+		{name: "greek", input: "Ελληνικά κείμενα", width: 8, want: "Ελλην..."},
+		{name: "cjk does not split wide rune", input: "日本語のタイトル", width: 8, want: "日本..."},
+		{name: "combining mark stays attached", input: "Café society", width: 7, want: "Café..."},
+		{name: "zwj emoji kept whole", input: "👨‍👩‍👧 family", width: 5, want: "👨‍👩‍👧..."},
+		{name: "too narrow for ellipsis", input: "abcdef", width: 2, want: "ab"},
+		{name: "zero", input: "abc", width: 0, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Truncate(tt.input, tt.width)
+			if got != tt.want {
+				t.Fatalf("Truncate(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Fatalf("Truncate(%q, %d) produced invalid UTF-8: %q", tt.input, tt.width, got)
+			}
+			if Width(got) > tt.width {
+				t.Fatalf("Width(%q) = %d exceeds %d", got, Width(got), tt.width)
+			}
+		})
+	}
+}
+
+func TestPad(t *testing.T) {
+	if got := PadRight("日本", 6); got != "日本  " {
+		t.Fatalf("PadRight() = %q", got)
+	}
+	if got := PadLeft("42", 4); got != "  42" {
+		t.Fatalf("PadLeft() = %q", got)
+	}
+	if got := PadRight("toolong", 3); got != "toolong" {
+		t.Fatalf("PadRight() = %q", got)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  []string
+	}{
+		{"the quick brown fox", 10, []string{"the quick", "brown fox"}},
+		{"one\n\ntwo", 10, []string{"one", "", "two"}},
+		{"abcdefghijkl xy", 5, []string{"abcde", "fghij", "kl xy"}},
+		{"日本語のテキスト", 6, []string{"日本語", "のテキ", "スト"}},
+		{"  spaced   out  ", 20, []string{"spaced out"}},
+	}
+	for _, tt := range tests {
+		got := Wrap(tt.in, tt.width)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("Wrap(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
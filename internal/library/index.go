@@ -0,0 +1,345 @@
+package library
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/authors"
+	"github.com/paperzilla/pz/internal/search"
+)
+
+// Hit is one local search result.
+type Hit struct {
+	Item        api.ProjectPaper
+	ProjectID   string
+	ProjectName string
+	Score       float64
+	Markdown    string
+}
+
+type SearchOptions struct {
+	ProjectID string
+	Limit     int
+}
+
+// SaveMarkdown stores a recommendation's markdown so it is searchable.
+func (l *Library) SaveMarkdown(projectPaperID, content string, fetchedAt time.Time) error {
+	_, err := l.db.Exec(`INSERT INTO markdown (project_paper_id, content, fetched_at) VALUES (?, ?, ?)
+		ON CONFLICT(project_paper_id) DO UPDATE SET content = excluded.content, fetched_at = excluded.fetched_at`,
+		projectPaperID, content, formatTime(fetchedAt))
+	if err != nil {
+		return fmt.Errorf("failed to save markdown: %w", err)
+	}
+	return nil
+}
+
+// MissingMarkdown lists a project's items whose paper markdown is ready on
+// the server but not stored locally.
+func (l *Library) MissingMarkdown(projectID string) ([]string, error) {
+	rows, err := l.db.Query(`SELECT pp.id, pp.data FROM project_papers pp
+		LEFT JOIN markdown m ON m.project_paper_id = pp.id
+		WHERE pp.project_id = ? AND m.project_paper_id IS NULL`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read library papers: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id, data string
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, fmt.Errorf("failed to read library papers: %w", err)
+		}
+		var item api.ProjectPaper
+		if json.Unmarshal([]byte(data), &item) == nil && item.Paper.MarkdownReady {
+			ids = append(ids, id)
+		}
+	}
+	return ids, rows.Err()
+}
+
+// RefreshIndex indexes items added or changed since the last refresh and
+// returns how many were (re)indexed. Removed items drop out of the index
+// through foreign keys.
+func (l *Library) RefreshIndex() (int, error) {
+	rows, err := l.db.Query(`SELECT pp.id, pp.data, COALESCE(m.content, ''),
+			pp.synced_at || '|' || COALESCE(m.fetched_at, '') AS version
+		FROM project_papers pp
+		LEFT JOIN markdown m ON m.project_paper_id = pp.id
+		LEFT JOIN search_docs d ON d.id = pp.id
+		WHERE d.version IS NULL OR d.version <> pp.synced_at || '|' || COALESCE(m.fetched_at, '')`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read library papers: %w", err)
+	}
+	type staleDoc struct {
+		id, version string
+		fields      [search.NumFields]string
+	}
+	var stale []staleDoc
+	for rows.Next() {
+		var doc staleDoc
+		var data, markdown string
+		if err := rows.Scan(&doc.id, &data, &markdown, &doc.version); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to read library papers: %w", err)
+		}
+		var item api.ProjectPaper
+		if err := json.Unmarshal([]byte(data), &item); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to decode library paper: %w", err)
+		}
+		doc.fields = searchFields(item, markdown)
+		stale = append(stale, doc)
+	}
+	rows.Close()
+	if len(stale) == 0 {
+		return 0, nil
+	}
+
+	tx, err := l.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to update search index: %w", err)
+	}
+	defer tx.Rollback()
+
+	insert, err := tx.Prepare(`INSERT INTO search_postings (term, doc_id, field, positions) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update search index: %w", err)
+	}
+	defer insert.Close()
+
+	for _, doc := range stale {
+		if _, err := tx.Exec(`DELETE FROM search_postings WHERE doc_id = ?`, doc.id); err != nil {
+			return 0, fmt.Errorf("failed to update search index: %w", err)
+		}
+
+		var lengths [search.NumFields]int
+		positions := map[string]*[search.NumFields][]string{}
+		for f, text := range doc.fields {
+			tokens := search.Tokenize(text)
+			lengths[f] = len(tokens)
+			for _, token := range tokens {
+				if positions[token.Term] == nil {
+					positions[token.Term] = &[search.NumFields][]string{}
+				}
+				positions[token.Term][f] = append(positions[token.Term][f], strconv.Itoa(token.Pos))
+			}
+		}
+
+		if _, err := tx.Exec(`INSERT INTO search_docs (id, version, len_title, len_summary, len_note, len_abstract, len_markdown)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(id) DO UPDATE SET version = excluded.version, len_title = excluded.len_title,
+				len_summary = excluded.len_summary, len_note = excluded.len_note,
+				len_abstract = excluded.len_abstract, len_markdown = excluded.len_markdown`,
+			doc.id, doc.version, lengths[0], lengths[1], lengths[2], lengths[3], lengths[4]); err != nil {
+			return 0, fmt.Errorf("failed to update search index: %w", err)
+		}
+		for term, fields := range positions {
+			for f, list := range fields {
+				if len(list) == 0 {
+					continue
+				}
+				if _, err := insert.Exec(term, doc.id, f, strings.Join(list, ",")); err != nil {
+					return 0, fmt.Errorf("failed to update search index: %w", err)
+				}
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to update search index: %w", err)
+	}
+	return len(stale), nil
+}
+
+func searchFields(item api.ProjectPaper, markdown string) [search.NumFields]string {
+	title := item.PaperTitle
+	if title == "" {
+		title = item.Paper.Title
+	}
+	return [search.NumFields]string{
+		search.FieldTitle:    title,
+		search.FieldSummary:  item.Summary,
+		search.FieldNote:     item.PersonalizedNote,
+		search.FieldAbstract: item.Paper.Abstract,
+		search.FieldMarkdown: markdown,
+	}
+}
+
+// Search refreshes the index and returns the best matches for q. Queries
+// with only filters return matching papers newest first.
+func (l *Library) Search(q search.Query, opts SearchOptions) ([]Hit, error) {
+	if _, err := l.RefreshIndex(); err != nil {
+		return nil, err
+	}
+
+	stats := search.Stats{DocFreq: map[string]int{}}
+	var avg [search.NumFields]sql.NullFloat64
+	if err := l.db.QueryRow(`SELECT COUNT(*), AVG(len_title), AVG(len_summary), AVG(len_note), AVG(len_abstract), AVG(len_markdown)
+		FROM search_docs`).Scan(&stats.Docs, &avg[0], &avg[1], &avg[2], &avg[3], &avg[4]); err != nil {
+		return nil, fmt.Errorf("failed to read search index: %w", err)
+	}
+	for f := range avg {
+		stats.AvgLen[f] = avg[f].Float64
+	}
+
+	// Postings for the query terms decide which documents can match.
+	var candidates map[string]search.DocTerms
+	if q.HasText() {
+		candidates = map[string]search.DocTerms{}
+		for _, term := range q.AllTerms() {
+			docs, err := l.postings(term)
+			if err != nil {
+				return nil, err
+			}
+			stats.DocFreq[term] = len(docs)
+			for id, postings := range docs {
+				if candidates[id] == nil {
+					candidates[id] = search.DocTerms{}
+				}
+				candidates[id][term] = postings
+			}
+		}
+		for id, terms := range candidates {
+			if !search.Matches(q, terms) {
+				delete(candidates, id)
+			}
+		}
+		if len(candidates) == 0 {
+			return nil, nil
+		}
+	}
+
+	query := `SELECT pp.id, pp.project_id, p.name, pp.data, pp.ready_at,
+			d.len_title, d.len_summary, d.len_note, d.len_abstract, d.len_markdown
+		FROM project_papers pp
+		JOIN projects p ON p.id = pp.project_id
+		JOIN search_docs d ON d.id = pp.id`
+	var args []any
+	if opts.ProjectID != "" {
+		query += ` WHERE pp.project_id = ?`
+		args = append(args, opts.ProjectID)
+	}
+	rows, err := l.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read library papers: %w", err)
+	}
+	defer rows.Close()
+
+	type scored struct {
+		hit     Hit
+		readyAt string
+		author  string
+	}
+	var results []scored
+	for rows.Next() {
+		var id, projectID, projectName, data, readyAt string
+		var lengths [search.NumFields]int
+		if err := rows.Scan(&id, &projectID, &projectName, &data, &readyAt,
+			&lengths[0], &lengths[1], &lengths[2], &lengths[3], &lengths[4]); err != nil {
+			return nil, fmt.Errorf("failed to read library papers: %w", err)
+		}
+		terms, ok := candidates[id]
+		if candidates != nil && !ok {
+			continue
+		}
+
+		var item api.ProjectPaper
+		if err := json.Unmarshal([]byte(data), &item); err != nil {
+			return nil, fmt.Errorf("failed to decode library paper: %w", err)
+		}
+		names := make([]string, 0, len(item.Paper.Authors))
+		for _, author := range item.Paper.Authors {
+			names = append(names, author.Name)
+		}
+		if !q.MatchesFilters(names, item.Paper.VenueName, publishedYear(item.Paper.PublishedDate)) {
+			continue
+		}
+
+		hit := Hit{Item: item, ProjectID: projectID, ProjectName: projectName}
+		if terms != nil {
+			hit.Score = search.Score(q, lengths, terms, stats)
+		}
+		result := scored{hit: hit, readyAt: readyAt}
+		if len(names) > 0 {
+			result.author = authors.Parse(names[0]).SortKey()
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read library papers: %w", err)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].hit.Score != results[j].hit.Score {
+			return results[i].hit.Score > results[j].hit.Score
+		}
+		if results[i].readyAt != results[j].readyAt {
+			return results[i].readyAt > results[j].readyAt
+		}
+		return results[i].author < results[j].author
+	})
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+
+	hits := make([]Hit, len(results))
+	for i, result := range results {
+		hits[i] = result.hit
+		if q.HasText() {
+			err := l.db.QueryRow(`SELECT content FROM markdown WHERE project_paper_id = ?`, result.hit.Item.ID).Scan(&hits[i].Markdown)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("failed to read markdown: %w", err)
+			}
+		}
+	}
+	return hits, nil
+}
+
+// postings loads one term's postings keyed by document.
+func (l *Library) postings(term string) (map[string]*[search.NumFields]search.Posting, error) {
+	rows, err := l.db.Query(`SELECT doc_id, field, positions FROM search_postings WHERE term = ?`, term)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read search index: %w", err)
+	}
+	defer rows.Close()
+
+	docs := map[string]*[search.NumFields]search.Posting{}
+	for rows.Next() {
+		var id, positions string
+		var field int
+		if err := rows.Scan(&id, &field, &positions); err != nil {
+			return nil, fmt.Errorf("failed to read search index: %w", err)
+		}
+		if field < 0 || field >= int(search.NumFields) {
+			continue
+		}
+		if docs[id] == nil {
+			docs[id] = &[search.NumFields]search.Posting{}
+		}
+		for _, value := range strings.Split(positions, ",") {
+			if pos, err := strconv.Atoi(value); err == nil {
+				docs[id][field].Positions = append(docs[id][field].Positions, pos)
+			}
+		}
+	}
+	return docs, rows.Err()
+}
+
+func publishedYear(date string) int {
+	if len(date) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(date[:4])
+	if err != nil {
+		return 0
+	}
+	return year
+}
@@ -0,0 +1,101 @@
+package library
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/search"
+)
+
+func newSearchSource() *fakeSource {
+	item := func(id, title, abstract, author, venue, date string) api.ProjectPaper {
+		return api.ProjectPaper{
+			ID:         id,
+			PaperTitle: title,
+			ReadyAt:    "2026-01-01T00:00:00Z",
+			Paper: api.Paper{
+				ID: "paper-" + id, Title: title, Abstract: abstract, VenueName: venue, PublishedDate: date,
+				Authors: []api.Author{{Name: author}}, MarkdownReady: id == "g2",
+			},
+		}
+	}
+	return &fakeSource{
+		projects: []api.Project{{ID: "p1", Name: "Graphs"}, {ID: "p2", Name: "Agents"}},
+		items: map[string][]api.ProjectPaper{
+			"p1": {
+				item("g1", "Graph transformers for molecules", "We apply message passing to chemistry.", "Jan van den Berg", "NeurIPS", "2023-05-01"),
+				item("g2", "Scalable message passing", "Efficient graph learning.", "Ada Lovelace", "ICML", "2021-07-01"),
+			},
+			"p2": {
+				item("a1", "Tool-using language agents", "Agents that plan with graphs of tools.", "Grace Hopper", "ICLR", "2024-01-01"),
+			},
+		},
+		markdown: map[string]string{"g2": "# Method\n\nWe use a hierarchical pooling scheme."},
+	}
+}
+
+func searchIDs(t *testing.T, lib *Library, query string, opts SearchOptions) []string {
+	t.Helper()
+	q, err := search.ParseQuery(query)
+	if err != nil {
+		t.Fatalf("ParseQuery(%q): %v", query, err)
+	}
+	hits, err := lib.Search(q, opts)
+	if err != nil {
+		t.Fatalf("Search(%q): %v", query, err)
+	}
+	ids := []string{}
+	for _, hit := range hits {
+		ids = append(ids, hit.Item.ID)
+	}
+	return ids
+}
+
+func TestSearchRanksFiltersAndCoversMarkdown(t *testing.T) {
+	lib := openTestLibrary(t)
+	if _, err := lib.Sync(newSearchSource(), SyncOptions{Markdown: true}); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	tests := []struct {
+		query string
+		opts  SearchOptions
+		want  string
+	}{
+		{"graphs", SearchOptions{}, "g1 g2 a1"},
+		{"graph", SearchOptions{ProjectID: "p2"}, "a1"},
+		{`"message passing"`, SearchOptions{}, "g2 g1"},
+		{`"passing message"`, SearchOptions{}, ""},
+		{"hierarchical pooling", SearchOptions{}, "g2"},
+		{"author:berg", SearchOptions{}, "g1"},
+		{"graph year:2022-", SearchOptions{}, "g1 a1"},
+		{"venue:icml", SearchOptions{}, "g2"},
+		{"graph", SearchOptions{Limit: 1}, "g1"},
+	}
+	for _, tt := range tests {
+		got := searchIDs(t, lib, tt.query, tt.opts)
+		if joined := strings.Join(got, " "); joined != tt.want {
+			t.Errorf("search %q = %q, want %q", tt.query, joined, tt.want)
+		}
+	}
+}
+
+func TestSearchIndexesNewItemsIncrementally(t *testing.T) {
+	lib := openTestLibrary(t)
+	source := newSearchSource()
+	if _, err := lib.Sync(source, SyncOptions{}); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if n, _ := lib.RefreshIndex(); n != 0 {
+		t.Fatalf("index should be current after sync, refreshed %d", n)
+	}
+
+	source.items["p2"] = append(source.items["p2"], api.ProjectPaper{ID: "a2", PaperTitle: "Quantum agents", ReadyAt: "2026-02-01T00:00:00Z"})
+	if _, err := lib.Sync(source, SyncOptions{ProjectIDs: []string{"p2"}}); err != nil {
+		t.Fatalf("second Sync: %v", err)
+	}
+	if got := strings.Join(searchIDs(t, lib, "quantum", SearchOptions{}), " "); got != "a2" {
+		t.Fatalf("search = %q", got)
+	}
+}
@@ -0,0 +1,163 @@
+// Package library keeps a local SQLite mirror of projects, their feeds and
+// feedback so other commands can work without re-paging the API.
+package library
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	_ "modernc.org/sqlite"
+)
+
+// migrations are applied in order. The schema version is the number of
+// applied migrations, stored in PRAGMA user_version. Never edit a released
+// migration; append a new one.
+var migrations = []string{
+	`CREATE TABLE projects (
+		id            TEXT PRIMARY KEY,
+		name          TEXT NOT NULL,
+		data          TEXT NOT NULL,
+		last_ready_at TEXT NOT NULL DEFAULT '',
+		synced_at     TEXT NOT NULL
+	);
+	CREATE TABLE papers (
+		id             TEXT PRIMARY KEY,
+		short_id       TEXT NOT NULL DEFAULT '',
+		title          TEXT NOT NULL DEFAULT '',
+		abstract       TEXT NOT NULL DEFAULT '',
+		authors        TEXT NOT NULL DEFAULT '',
+		published_date TEXT NOT NULL DEFAULT '',
+		venue          TEXT NOT NULL DEFAULT '',
+		data           TEXT NOT NULL
+	);
+	CREATE TABLE project_papers (
+		id                TEXT PRIMARY KEY,
+		project_id        TEXT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+		paper_id          TEXT NOT NULL DEFAULT '',
+		short_id          TEXT NOT NULL DEFAULT '',
+		paper_title       TEXT NOT NULL DEFAULT '',
+		summary           TEXT NOT NULL DEFAULT '',
+		personalized_note TEXT NOT NULL DEFAULT '',
+		relevance_class   INTEGER NOT NULL DEFAULT 0,
+		relevance_score   REAL NOT NULL DEFAULT 0,
+		ready_at          TEXT NOT NULL DEFAULT '',
+		feedback_vote     TEXT NOT NULL DEFAULT '',
+		feedback_reason   TEXT NOT NULL DEFAULT '',
+		data              TEXT NOT NULL,
+		synced_at         TEXT NOT NULL
+	);
+	CREATE INDEX project_papers_project_ready ON project_papers(project_id, ready_at);
+	CREATE INDEX project_papers_paper ON project_papers(paper_id);`,
+
+	`CREATE TABLE markdown (
+		project_paper_id TEXT PRIMARY KEY REFERENCES project_papers(id) ON DELETE CASCADE,
+		content          TEXT NOT NULL,
+		fetched_at       TEXT NOT NULL
+	);
+	CREATE TABLE search_docs (
+		id           TEXT PRIMARY KEY REFERENCES project_papers(id) ON DELETE CASCADE,
+		version      TEXT NOT NULL,
+		len_title    INTEGER NOT NULL,
+		len_summary  INTEGER NOT NULL,
+		len_note     INTEGER NOT NULL,
+		len_abstract INTEGER NOT NULL,
+		len_markdown INTEGER NOT NULL
+	);
+	CREATE TABLE search_postings (
+		term      TEXT NOT NULL,
+		doc_id    TEXT NOT NULL REFERENCES search_docs(id) ON DELETE CASCADE,
+		field     INTEGER NOT NULL,
+		positions TEXT NOT NULL,
+		PRIMARY KEY (term, doc_id, field)
+	) WITHOUT ROWID;
+	CREATE INDEX search_postings_doc ON search_postings(doc_id);`,
+}
+
+// SchemaVersion is the schema version this build writes.
+var SchemaVersion = len(migrations)
+
+type Library struct {
+	db   *sql.DB
+	path string
+}
+
+// Open opens or creates the library at path and migrates it to the current
+// schema.
+func Open(path string) (*Library, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create library directory: %w", err)
+	}
+
+	// The path is escaped so that ?, # or % in it are not read as part of
+	// the URI.
+	dsn := (&url.URL{
+		Scheme:   "file",
+		Opaque:   (&url.URL{Path: path}).EscapedPath(),
+		RawQuery: "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)",
+	}).String()
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open library: %w", err)
+	}
+	// SQLite allows one writer; a single connection avoids SQLITE_BUSY
+	// between our own goroutines.
+	db.SetMaxOpenConns(1)
+
+	lib := &Library{db: db, path: path}
+	if err := lib.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return lib, nil
+}
+
+func (l *Library) Close() error {
+	return l.db.Close()
+}
+
+func (l *Library) Path() string {
+	return l.path
+}
+
+// Version returns the schema version stored in the database.
+func (l *Library) Version() (int, error) {
+	var version int
+	if err := l.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read library version: %w", err)
+	}
+	return version, nil
+}
+
+func (l *Library) migrate() error {
+	version, err := l.Version()
+	if err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("library %s has schema version %d, newer than this pz supports (%d); update pz", l.path, version, len(migrations))
+	}
+
+	for version < len(migrations) {
+		tx, err := l.db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to migrate library: %w", err)
+		}
+		if _, err := tx.Exec(migrations[version]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to migrate library to version %d: %w", version+1, err)
+		}
+		// PRAGMA does not take bind parameters.
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", version+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to migrate library to version %d: %w", version+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to migrate library to version %d: %w", version+1, err)
+		}
+		version++
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func openTestLibrary(t *testing.T) *Library {
+	t.Helper()
+	lib, err := Open(filepath.Join(t.TempDir(), "sub", "library.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { lib.Close() })
+	return lib
+}
+
+func TestOpenMigratesToCurrentVersion(t *testing.T) {
+	lib := openTestLibrary(t)
+
+	version, err := lib.Version()
+	if err != nil || version != SchemaVersion {
+		t.Fatalf("Version = %d, %v; want %d", version, err, SchemaVersion)
+	}
+	lib.Close()
+
+	reopened, err := Open(lib.Path())
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer reopened.Close()
+	if version, _ := reopened.Version(); version != SchemaVersion {
+		t.Fatalf("reopened version = %d", version)
+	}
+}
+
+func TestOpenEscapesThePath(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "100% done?", "#1")
+	lib, err := Open(filepath.Join(dir, "library.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer lib.Close()
+	if _, err := lib.Version(); err != nil {
+		t.Fatalf("Version: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) == 0 || entries[0].Name() != "library.db" {
+		t.Fatalf("entries = %v, want library.db in %s", entries, dir)
+	}
+}
+
+func TestOpenRejectsNewerSchema(t *testing.T) {
+	lib := openTestLibrary(t)
+	if _, err := lib.db.Exec("PRAGMA user_version = 999"); err != nil {
+		t.Fatal(err)
+	}
+	lib.Close()
+
+	_, err := Open(lib.Path())
+	if err == nil || !strings.Contains(err.Error(), "newer than this pz supports") {
+		t.Fatalf("err = %v", err)
+	}
+}
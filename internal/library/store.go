@@ -0,0 +1,136 @@
+package library
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+)
+
+// ProjectState is what the library knows about one synced project.
+type ProjectState struct {
+	Project     api.Project
+	LastReadyAt string
+	SyncedAt    time.Time
+	Papers      int
+}
+
+func (l *Library) saveProject(tx *sql.Tx, project api.Project, syncedAt time.Time) error {
+	data, err := json.Marshal(project)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO projects (id, name, data, synced_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET name = excluded.name, data = excluded.data, synced_at = excluded.synced_at`,
+		project.ID, project.Name, string(data), formatTime(syncedAt))
+	return err
+}
+
+// saveProjectPaper upserts item and its paper. It reports whether the item was new.
+func (l *Library) saveProjectPaper(tx *sql.Tx, projectID string, item api.ProjectPaper, syncedAt time.Time) (bool, error) {
+	var exists int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM project_papers WHERE id = ?`, item.ID).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	if item.Paper.ID != "" {
+		paperData, err := json.Marshal(item.Paper)
+		if err != nil {
+			return false, err
+		}
+		names := make([]string, 0, len(item.Paper.Authors))
+		for _, author := range item.Paper.Authors {
+			names = append(names, author.Name)
+		}
+		title := item.Paper.Title
+		if title == "" {
+			title = item.PaperTitle
+		}
+		if _, err := tx.Exec(`INSERT INTO papers (id, short_id, title, abstract, authors, published_date, venue, data)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(id) DO UPDATE SET short_id = excluded.short_id, title = excluded.title,
+				abstract = excluded.abstract, authors = excluded.authors, published_date = excluded.published_date,
+				venue = excluded.venue, data = excluded.data`,
+			item.Paper.ID, item.Paper.ShortID, title, item.Paper.Abstract, strings.Join(names, "; "),
+			item.Paper.PublishedDate, item.Paper.VenueName, string(paperData)); err != nil {
+			return false, err
+		}
+	}
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return false, err
+	}
+	vote, reason := "", ""
+	if item.Feedback != nil {
+		vote, reason = item.Feedback.Vote, item.Feedback.DownvoteReason
+	}
+	_, err = tx.Exec(`INSERT INTO project_papers (id, project_id, paper_id, short_id, paper_title, summary,
+			personalized_note, relevance_class, relevance_score, ready_at, feedback_vote, feedback_reason, data, synced_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET project_id = excluded.project_id, paper_id = excluded.paper_id,
+			short_id = excluded.short_id, paper_title = excluded.paper_title, summary = excluded.summary,
+			personalized_note = excluded.personalized_note, relevance_class = excluded.relevance_class,
+			relevance_score = excluded.relevance_score, ready_at = excluded.ready_at,
+			feedback_vote = excluded.feedback_vote, feedback_reason = excluded.feedback_reason,
+			data = excluded.data, synced_at = excluded.synced_at`,
+		item.ID, projectID, item.Paper.ID, item.ShortID, item.PaperTitle, item.Summary, item.PersonalizedNote,
+		item.RelevanceClass, item.RelevanceScore, item.ReadyAt, vote, reason, string(data), formatTime(syncedAt))
+	return exists == 0, err
+}
+
+// Projects returns every synced project ordered by name.
+func (l *Library) Projects() ([]ProjectState, error) {
+	rows, err := l.db.Query(`SELECT p.data, p.last_ready_at, p.synced_at,
+			(SELECT COUNT(*) FROM project_papers pp WHERE pp.project_id = p.id)
+		FROM projects p ORDER BY p.name COLLATE NOCASE, p.id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read library projects: %w", err)
+	}
+	defer rows.Close()
+
+	var states []ProjectState
+	for rows.Next() {
+		var data, syncedAt string
+		var state ProjectState
+		if err := rows.Scan(&data, &state.LastReadyAt, &syncedAt, &state.Papers); err != nil {
+			return nil, fmt.Errorf("failed to read library projects: %w", err)
+		}
+		if err := json.Unmarshal([]byte(data), &state.Project); err != nil {
+			return nil, fmt.Errorf("failed to decode library project: %w", err)
+		}
+		state.SyncedAt, _ = time.Parse(time.RFC3339Nano, syncedAt)
+		states = append(states, state)
+	}
+	return states, rows.Err()
+}
+
+// ProjectPapers returns a project's synced feed items, newest first.
+func (l *Library) ProjectPapers(projectID string) ([]api.ProjectPaper, error) {
+	rows, err := l.db.Query(`SELECT data FROM project_papers WHERE project_id = ? ORDER BY ready_at DESC, id`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read library papers: %w", err)
+	}
+	defer rows.Close()
+
+	var items []api.ProjectPaper
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read library papers: %w", err)
+		}
+		var item api.ProjectPaper
+		if err := json.Unmarshal([]byte(data), &item); err != nil {
+			return nil, fmt.Errorf("failed to decode library paper: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
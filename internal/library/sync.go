@@ -0,0 +1,234 @@
+package library
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+)
+
+const syncPageSize = 50
+
+const pruneOrphanPapers = `DELETE FROM papers WHERE id NOT IN (SELECT paper_id FROM project_papers)`
+
+// Source is the slice of the API that Sync reads from.
+type Source interface {
+	Projects() ([]api.Project, error)
+	Project(id string) (api.Project, error)
+	Feed(projectID string, opts api.FeedOptions) (api.FeedResponse, error)
+	Markdown(projectPaperID string) (string, error)
+}
+
+type SyncOptions struct {
+	// ProjectIDs limits the sync to these projects. Empty syncs all of them
+	// and drops local projects that no longer exist.
+	ProjectIDs []string
+	// Full refetches every feed item instead of only those ready since the
+	// last sync, which also picks up feedback changed elsewhere and drops
+	// items that left the feed.
+	Full bool
+	// Markdown also stores the full-text markdown of papers that have it,
+	// so offline search covers it.
+	Markdown bool
+	Now      func() time.Time
+	// Progress is called after each project is written.
+	Progress func(ProjectResult)
+}
+
+type ProjectResult struct {
+	Project  api.Project
+	Full     bool
+	New      int
+	Updated  int
+	Removed  int
+	Markdown int
+	Total    int
+}
+
+// Sync mirrors projects and their feeds from src. Each project is written
+// in its own transaction, so an interrupted sync keeps finished projects
+// and the next run picks up where the last complete one left off.
+func (l *Library) Sync(src Source, opts SyncOptions) ([]ProjectResult, error) {
+	now := time.Now
+	if opts.Now != nil {
+		now = opts.Now
+	}
+	syncedAt := now()
+
+	ids := opts.ProjectIDs
+	if len(ids) == 0 {
+		projects, err := src.Projects()
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch projects: %w", err)
+		}
+		for _, project := range projects {
+			ids = append(ids, project.ID)
+		}
+		if err := l.removeProjectsExcept(ids); err != nil {
+			return nil, err
+		}
+	}
+
+	var results []ProjectResult
+	for _, id := range ids {
+		result, err := l.syncProject(src, id, opts.Full, syncedAt)
+		if err != nil {
+			return results, err
+		}
+		if opts.Markdown {
+			if result.Markdown, err = l.syncMarkdown(src, id, now); err != nil {
+				return results, err
+			}
+		}
+		results = append(results, result)
+		if opts.Progress != nil {
+			opts.Progress(result)
+		}
+	}
+
+	if _, err := l.RefreshIndex(); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// syncMarkdown fetches markdown that is ready on the server but missing
+// locally. Papers whose markdown is still being prepared are left for the
+// next sync.
+func (l *Library) syncMarkdown(src Source, projectID string, now func() time.Time) (int, error) {
+	ids, err := l.MissingMarkdown(projectID)
+	if err != nil {
+		return 0, err
+	}
+
+	fetched := 0
+	for _, id := range ids {
+		content, err := src.Markdown(id)
+		if err != nil {
+			var pending *api.PaperMarkdownPendingError
+			var apiErr *api.APIError
+			if errors.As(err, &pending) || (errors.As(err, &apiErr) && (apiErr.StatusCode == 404 || apiErr.Code == "markdown_not_ready")) {
+				continue
+			}
+			return fetched, fmt.Errorf("failed to fetch markdown for %s: %w", id, err)
+		}
+		if err := l.SaveMarkdown(id, content, now()); err != nil {
+			return fetched, err
+		}
+		fetched++
+	}
+	return fetched, nil
+}
+
+func (l *Library) syncProject(src Source, projectID string, full bool, syncedAt time.Time) (ProjectResult, error) {
+	project, err := src.Project(projectID)
+	if err != nil {
+		return ProjectResult{}, fmt.Errorf("failed to fetch project %s: %w", projectID, err)
+	}
+
+	since := ""
+	if !full {
+		err := l.db.QueryRow(`SELECT last_ready_at FROM projects WHERE id = ?`, projectID).Scan(&since)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return ProjectResult{}, fmt.Errorf("failed to read sync state: %w", err)
+		}
+	}
+	result := ProjectResult{Project: project, Full: since == ""}
+
+	var items []api.ProjectPaper
+	for offset := 0; ; {
+		page, err := src.Feed(projectID, api.FeedOptions{Since: since, Limit: syncPageSize, Offset: offset})
+		if err != nil {
+			return ProjectResult{}, fmt.Errorf("failed to fetch feed for project %s: %w", projectID, err)
+		}
+		items = append(items, page.Items...)
+		offset += len(page.Items)
+		if len(page.Items) == 0 || offset >= page.Total {
+			break
+		}
+	}
+
+	tx, err := l.db.Begin()
+	if err != nil {
+		return ProjectResult{}, fmt.Errorf("failed to write library: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := l.saveProject(tx, project, syncedAt); err != nil {
+		return ProjectResult{}, fmt.Errorf("failed to save project %s: %w", projectID, err)
+	}
+	for _, item := range items {
+		isNew, err := l.saveProjectPaper(tx, projectID, item, syncedAt)
+		if err != nil {
+			return ProjectResult{}, fmt.Errorf("failed to save paper %s: %w", item.ID, err)
+		}
+		if isNew {
+			result.New++
+		} else {
+			result.Updated++
+		}
+	}
+
+	if result.Full {
+		res, err := tx.Exec(`DELETE FROM project_papers WHERE project_id = ? AND synced_at <> ?`, projectID, formatTime(syncedAt))
+		if err != nil {
+			return ProjectResult{}, fmt.Errorf("failed to prune project %s: %w", projectID, err)
+		}
+		removed, _ := res.RowsAffected()
+		result.Removed = int(removed)
+		if _, err := tx.Exec(pruneOrphanPapers); err != nil {
+			return ProjectResult{}, fmt.Errorf("failed to prune papers: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE projects SET last_ready_at =
+			COALESCE((SELECT MAX(ready_at) FROM project_papers WHERE project_id = ?), '')
+		WHERE id = ?`, projectID, projectID); err != nil {
+		return ProjectResult{}, fmt.Errorf("failed to save sync state: %w", err)
+	}
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM project_papers WHERE project_id = ?`, projectID).Scan(&result.Total); err != nil {
+		return ProjectResult{}, fmt.Errorf("failed to count papers: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return ProjectResult{}, fmt.Errorf("failed to write library: %w", err)
+	}
+	return result, nil
+}
+
+func (l *Library) removeProjectsExcept(ids []string) error {
+	keep := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		keep[id] = true
+	}
+
+	rows, err := l.db.Query(`SELECT id FROM projects`)
+	if err != nil {
+		return fmt.Errorf("failed to read library projects: %w", err)
+	}
+	var stale []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read library projects: %w", err)
+		}
+		if !keep[id] {
+			stale = append(stale, id)
+		}
+	}
+	rows.Close()
+
+	for _, id := range stale {
+		if _, err := l.db.Exec(`DELETE FROM projects WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("failed to remove project %s: %w", id, err)
+		}
+	}
+	if len(stale) > 0 {
+		if _, err := l.db.Exec(pruneOrphanPapers); err != nil {
+			return fmt.Errorf("failed to prune papers: %w", err)
+		}
+	}
+	return nil
+}
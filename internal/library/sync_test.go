@@ -0,0 +1,149 @@
+package library
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/paperzilla/pz/internal/api"
+)
+
+type fakeSource struct {
+	projects []api.Project
+	items    map[string][]api.ProjectPaper
+	markdown map[string]string
+	requests []string
+}
+
+func (s *fakeSource) Projects() ([]api.Project, error) {
+	return s.projects, nil
+}
+
+func (s *fakeSource) Project(id string) (api.Project, error) {
+	for _, project := range s.projects {
+		if project.ID == id {
+			return project, nil
+		}
+	}
+	return api.Project{}, fmt.Errorf("no project %s", id)
+}
+
+func (s *fakeSource) Feed(projectID string, opts api.FeedOptions) (api.FeedResponse, error) {
+	s.requests = append(s.requests, fmt.Sprintf("%s since=%q offset=%d", projectID, opts.Since, opts.Offset))
+	var matching []api.ProjectPaper
+	for _, item := range s.items[projectID] {
+		if opts.Since == "" || item.ReadyAt > opts.Since {
+			matching = append(matching, item)
+		}
+	}
+	end := min(opts.Offset+opts.Limit, len(matching))
+	return api.FeedResponse{Items: matching[opts.Offset:end], Total: len(matching)}, nil
+}
+
+func (s *fakeSource) Markdown(projectPaperID string) (string, error) {
+	content, ok := s.markdown[projectPaperID]
+	if !ok {
+		return "", &api.PaperMarkdownPendingError{}
+	}
+	return content, nil
+}
+
+func feedItem(id, readyAt string) api.ProjectPaper {
+	return api.ProjectPaper{
+		ID:         id,
+		PaperTitle: "Title " + id,
+		ReadyAt:    readyAt,
+		Paper:      api.Paper{ID: "paper-" + id, Title: "Title " + id, Authors: []api.Author{{Name: "Ada Lovelace"}}},
+	}
+}
+
+func newFakeSource() *fakeSource {
+	source := &fakeSource{
+		projects: []api.Project{{ID: "p1", Name: "Graphs"}, {ID: "p2", Name: "Agents"}},
+		items:    map[string][]api.ProjectPaper{},
+	}
+	for i := 0; i < syncPageSize+5; i++ {
+		source.items["p1"] = append(source.items["p1"], feedItem(fmt.Sprintf("a%03d", i), fmt.Sprintf("2026-01-01T00:%02d:%02dZ", i/60, i%60)))
+	}
+	source.items["p2"] = []api.ProjectPaper{feedItem("b1", "2026-02-01T00:00:00Z")}
+	return source
+}
+
+func TestSyncIsIncremental(t *testing.T) {
+	lib := openTestLibrary(t)
+	source := newFakeSource()
+
+	results, err := lib.Sync(source, SyncOptions{})
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(results) != 2 || results[0].New != syncPageSize+5 || !results[0].Full || results[1].Total != 1 {
+		t.Fatalf("results = %+v", results)
+	}
+
+	source.requests = nil
+	source.items["p2"] = append(source.items["p2"], feedItem("b2", "2026-03-01T00:00:00Z"))
+	results, err = lib.Sync(source, SyncOptions{ProjectIDs: []string{"p2"}})
+	if err != nil {
+		t.Fatalf("second Sync: %v", err)
+	}
+	if source.requests[0] != `p2 since="2026-02-01T00:00:00Z" offset=0` {
+		t.Fatalf("requests = %q", source.requests)
+	}
+	if results[0].Full || results[0].New != 1 || results[0].Total != 2 {
+		t.Fatalf("results = %+v", results)
+	}
+
+	items, err := lib.ProjectPapers("p2")
+	if err != nil || len(items) != 2 || items[0].ID != "b2" || items[0].Paper.Authors[0].Name != "Ada Lovelace" {
+		t.Fatalf("ProjectPapers = %+v, %v", items, err)
+	}
+}
+
+func TestFullSyncRefreshesFeedbackAndPrunes(t *testing.T) {
+	lib := openTestLibrary(t)
+	source := newFakeSource()
+	if _, err := lib.Sync(source, SyncOptions{}); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	source.items["p1"] = source.items["p1"][1:]
+	source.items["p1"][0].Feedback = &api.Feedback{Vote: "star"}
+	results, err := lib.Sync(source, SyncOptions{ProjectIDs: []string{"p1"}, Full: true})
+	if err != nil {
+		t.Fatalf("full Sync: %v", err)
+	}
+	if results[0].Removed != 1 || results[0].Updated != syncPageSize+4 || results[0].Total != syncPageSize+4 {
+		t.Fatalf("results = %+v", results)
+	}
+
+	var vote string
+	if err := lib.db.QueryRow(`SELECT feedback_vote FROM project_papers WHERE id = 'a001'`).Scan(&vote); err != nil || vote != "star" {
+		t.Fatalf("vote = %q, %v", vote, err)
+	}
+	var papers int
+	lib.db.QueryRow(`SELECT COUNT(*) FROM papers WHERE id = 'paper-a000'`).Scan(&papers)
+	if papers != 0 {
+		t.Fatal("papers no longer in any feed should be pruned")
+	}
+}
+
+func TestSyncAllDropsDeletedProjects(t *testing.T) {
+	lib := openTestLibrary(t)
+	source := newFakeSource()
+	if _, err := lib.Sync(source, SyncOptions{}); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	source.projects = source.projects[:1]
+	if _, err := lib.Sync(source, SyncOptions{}); err != nil {
+		t.Fatalf("second Sync: %v", err)
+	}
+
+	states, err := lib.Projects()
+	if err != nil || len(states) != 1 || states[0].Project.ID != "p1" || states[0].Papers != syncPageSize+5 {
+		t.Fatalf("Projects = %+v, %v", states, err)
+	}
+	if items, _ := lib.ProjectPapers("p2"); len(items) != 0 {
+		t.Fatalf("deleted project items = %d", len(items))
+	}
+}
@@ -0,0 +1,249 @@
+package mdrender
+
+import (
+	"strings"
+)
+
+type style struct {
+	bold   bool
+	italic bool
+	strike bool
+	code   bool
+	link   bool
+	math   bool
+	dim    bool
+}
+
+type span struct {
+	text  string
+	style style
+}
+
+// parseInline turns one paragraph of markdown into styled spans. It handles
+// code spans, inline math, emphasis, strikethrough, links, images,
+// autolinks and backslash escapes; everything else is literal text.
+func parseInline(src string) []span {
+	p := inlineParser{src: src}
+	p.parse()
+	p.flush()
+	return p.spans
+}
+
+type inlineParser struct {
+	src     string
+	pos     int
+	current style
+	text    strings.Builder
+	spans   []span
+}
+
+func (p *inlineParser) flush() {
+	if p.text.Len() == 0 {
+		return
+	}
+	p.spans = append(p.spans, span{text: p.text.String(), style: p.current})
+	p.text.Reset()
+}
+
+func (p *inlineParser) emit(text string, s style) {
+	p.flush()
+	if text != "" {
+		p.spans = append(p.spans, span{text: text, style: s})
+	}
+}
+
+func (p *inlineParser) toggle(field *bool, delimiterLen int) {
+	p.flush()
+	*field = !*field
+	p.pos += delimiterLen
+}
+
+func (p *inlineParser) parse() {
+	for p.pos < len(p.src) {
+		rest := p.src[p.pos:]
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && (rest[1] == '(' || rest[1] == '['):
+			closer := `\)`
+			if rest[1] == '[' {
+				closer = `\]`
+			}
+			if end := strings.Index(rest[2:], closer); end >= 0 {
+				p.emitMath(rest[2 : 2+end])
+				p.pos += 2 + end + 2
+				continue
+			}
+			p.literal(2)
+		case rest[0] == '\\' && len(rest) > 1 && strings.ContainsRune("\\`*_{}[]()#+-.!|~$<>", rune(rest[1])):
+			p.text.WriteByte(rest[1])
+			p.pos += 2
+		case rest[0] == '`':
+			p.codeSpan(rest)
+		case rest[0] == '$':
+			p.inlineMath(rest)
+		case strings.HasPrefix(rest, "!["):
+			if alt, _, n, ok := parseLink(rest[1:]); ok {
+				p.emit("[image: "+strings.TrimSpace(alt)+"]", style{dim: true})
+				p.pos += 1 + n
+				continue
+			}
+			p.literal(1)
+		case rest[0] == '[':
+			if label, target, n, ok := parseLink(rest); ok {
+				p.emitLink(label, target)
+				p.pos += n
+				continue
+			}
+			p.literal(1)
+		case rest[0] == '<':
+			if end := strings.IndexByte(rest, '>'); end > 0 && isAutolink(rest[1:end]) {
+				target := rest[1:end]
+				p.emit(target, style{link: true})
+				p.pos += end + 1
+				continue
+			}
+			p.literal(1)
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if p.current.bold || strings.Contains(rest[2:], rest[:2]) {
+				p.toggle(&p.current.bold, 2)
+				continue
+			}
+			p.literal(2)
+		case strings.HasPrefix(rest, "~~"):
+			if p.current.strike || strings.Contains(rest[2:], "~~") {
+				p.toggle(&p.current.strike, 2)
+				continue
+			}
+			p.literal(2)
+		case rest[0] == '*' || (rest[0] == '_' && p.atWordBoundary()):
+			if p.current.italic || p.opensEmphasis(rest) {
+				p.toggle(&p.current.italic, 1)
+				continue
+			}
+			p.literal(1)
+		default:
+			p.literal(1)
+		}
+	}
+}
+
+func (p *inlineParser) literal(n int) {
+	p.text.WriteString(p.src[p.pos : p.pos+n])
+	p.pos += n
+}
+
+// atWordBoundary keeps snake_case identifiers from toggling emphasis.
+func (p *inlineParser) atWordBoundary() bool {
+	before := p.pos == 0 || !isWordByte(p.src[p.pos-1])
+	after := p.pos+1 >= len(p.src) || !isWordByte(p.src[p.pos+1])
+	return before || after
+}
+
+func (p *inlineParser) opensEmphasis(rest string) bool {
+	if len(rest) < 2 || rest[1] == ' ' {
+		return false
+	}
+	return strings.IndexByte(rest[1:], rest[0]) >= 0
+}
+
+func (p *inlineParser) codeSpan(rest string) {
+	ticks := len(rest) - len(strings.TrimLeft(rest, "`"))
+	fence := rest[:ticks]
+	end := strings.Index(rest[ticks:], fence)
+	if end < 0 {
+		p.literal(ticks)
+		return
+	}
+	code := strings.TrimSpace(rest[ticks : ticks+end])
+	p.emit(code, style{code: true})
+	p.pos += ticks + end + ticks
+}
+
+// inlineMath accepts $...$ when the opening dollar is followed by a
+// non-space and the closing one is not followed by a digit, so prices like
+// "$5 and $10" stay literal. $$...$$ on one line is treated the same way.
+func (p *inlineParser) inlineMath(rest string) {
+	delim := "$"
+	if strings.HasPrefix(rest, "$$") {
+		delim = "$$"
+	}
+	body := rest[len(delim):]
+	end := strings.Index(body, delim)
+	if end <= 0 || body[0] == ' ' || body[end-1] == ' ' {
+		p.literal(len(delim))
+		return
+	}
+	after := len(delim) + end + len(delim)
+	if after < len(rest) && rest[after] >= '0' && rest[after] <= '9' {
+		p.literal(len(delim))
+		return
+	}
+	p.emitMath(body[:end])
+	p.pos += after
+}
+
+func (p *inlineParser) emitMath(src string) {
+	s := p.current
+	s.math = true
+	p.emit(strings.TrimSpace(LatexToUnicode(src)), s)
+}
+
+func (p *inlineParser) emitLink(label, target string) {
+	labelSpans := parseInline(label)
+	p.flush()
+	for _, ls := range labelSpans {
+		ls.style.link = true
+		p.spans = append(p.spans, ls)
+	}
+	target = strings.TrimSpace(target)
+	if target != "" && target != strings.TrimSpace(label) && !strings.HasPrefix(target, "#") {
+		p.emit(" ("+target+")", style{dim: true})
+	}
+}
+
+// parseLink parses "[label](target)" at the start of s and returns the
+// number of bytes consumed.
+func parseLink(s string) (string, string, int, bool) {
+	if !strings.HasPrefix(s, "[") {
+		return "", "", 0, false
+	}
+	depth := 0
+	closeLabel := -1
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				closeLabel = i
+			}
+		}
+		if closeLabel >= 0 {
+			break
+		}
+	}
+	if closeLabel < 0 || closeLabel+1 >= len(s) || s[closeLabel+1] != '(' {
+		return "", "", 0, false
+	}
+	closeTarget := strings.IndexByte(s[closeLabel+2:], ')')
+	if closeTarget < 0 {
+		return "", "", 0, false
+	}
+	target := s[closeLabel+2 : closeLabel+2+closeTarget]
+	if space := strings.IndexAny(target, " \t"); space >= 0 {
+		// Drop an optional link title: [x](url "title").
+		target = target[:space]
+	}
+	return s[1:closeLabel], target, closeLabel + 2 + closeTarget + 1, true
+}
+
+func isAutolink(s string) bool {
+	return (strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "mailto:")) &&
+		!strings.ContainsAny(s, " \t<")
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || isASCIILetter(c) || c >= 0x80
+}
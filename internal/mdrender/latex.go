@@ -0,0 +1,249 @@
+package mdrender
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var latexSymbols = map[string]string{
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ϵ",
+	"varepsilon": "ε", "zeta": "ζ", "eta": "η", "theta": "θ", "vartheta": "ϑ",
+	"iota": "ι", "kappa": "κ", "lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ",
+	"pi": "π", "varpi": "ϖ", "rho": "ρ", "varrho": "ϱ", "sigma": "σ",
+	"varsigma": "ς", "tau": "τ", "upsilon": "υ", "phi": "ϕ", "varphi": "φ",
+	"chi": "χ", "psi": "ψ", "omega": "ω",
+	"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ",
+	"Pi": "Π", "Sigma": "Σ", "Upsilon": "Υ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+
+	"times": "×", "cdot": "·", "div": "÷", "pm": "±", "mp": "∓", "ast": "∗",
+	"star": "⋆", "circ": "∘", "bullet": "•", "oplus": "⊕", "otimes": "⊗",
+	"leq": "≤", "le": "≤", "geq": "≥", "ge": "≥", "neq": "≠", "ne": "≠",
+	"ll": "≪", "gg": "≫", "approx": "≈", "sim": "∼", "simeq": "≃", "cong": "≅",
+	"equiv": "≡", "propto": "∝", "coloneqq": "≔",
+	"infty": "∞", "partial": "∂", "nabla": "∇", "sum": "∑", "prod": "∏",
+	"int": "∫", "oint": "∮", "sqrt": "√", "ell": "ℓ", "hbar": "ℏ", "Re": "ℜ", "Im": "ℑ",
+	"in": "∈", "notin": "∉", "ni": "∋", "subset": "⊂", "subseteq": "⊆",
+	"supset": "⊃", "supseteq": "⊇", "cup": "∪", "cap": "∩", "setminus": "∖",
+	"emptyset": "∅", "varnothing": "∅", "forall": "∀", "exists": "∃",
+	"neg": "¬", "lnot": "¬", "wedge": "∧", "land": "∧", "vee": "∨", "lor": "∨",
+	"to": "→", "rightarrow": "→", "leftarrow": "←", "gets": "←",
+	"leftrightarrow": "↔", "Rightarrow": "⇒", "Leftarrow": "⇐",
+	"Leftrightarrow": "⇔", "implies": "⟹", "iff": "⟺", "mapsto": "↦",
+	"uparrow": "↑", "downarrow": "↓",
+	"ldots": "…", "dots": "…", "cdots": "⋯", "vdots": "⋮", "ddots": "⋱",
+	"langle": "⟨", "rangle": "⟩", "lceil": "⌈", "rceil": "⌉", "lfloor": "⌊",
+	"rfloor": "⌋", "mid": "∣", "parallel": "∥", "perp": "⊥", "top": "⊤",
+	"bot": "⊥", "prime": "′", "angle": "∠", "degree": "°",
+	"log": "log", "ln": "ln", "exp": "exp", "sin": "sin", "cos": "cos",
+	"tan": "tan", "max": "max", "min": "min", "arg": "arg", "lim": "lim",
+	"sup": "sup", "inf": "inf", "det": "det", "Pr": "Pr",
+}
+
+// latexSpacing commands render as a single space; latexIgnored ones are
+// purely presentational and vanish.
+var latexSpacing = map[string]bool{",": true, ";": true, ":": true, " ": true, "quad": true, "qquad": true}
+
+var latexIgnored = map[string]bool{
+	"!": true, "left": true, "right": true, "big": true, "Big": true, "bigg": true,
+	"Bigg": true, "bigl": true, "bigr": true, "Bigl": true, "Bigr": true,
+	"displaystyle": true, "textstyle": true, "limits": true, "nolimits": true,
+}
+
+// latexTextCommands keep their argument's content and drop the styling.
+var latexTextCommands = map[string]bool{
+	"text": true, "textrm": true, "textbf": true, "textit": true, "texttt": true,
+	"mathrm": true, "mathbf": true, "mathit": true, "mathsf": true, "mathtt": true,
+	"mathcal": true, "mathfrak": true, "mathscr": true, "boldsymbol": true,
+	"operatorname": true, "bm": true, "hat": true, "bar": true, "tilde": true,
+	"vec": true, "overline": true, "underline": true, "widehat": true, "widetilde": true,
+}
+
+var blackboard = map[rune]string{
+	'C': "ℂ", 'E': "𝔼", 'H': "ℍ", 'N': "ℕ", 'P': "ℙ", 'Q': "ℚ", 'R': "ℝ", 'Z': "ℤ",
+	'1': "𝟙",
+}
+
+var superscripts = map[rune]rune{
+	'0': '⁰', '1': '¹', '2': '²', '3': '³', '4': '⁴', '5': '⁵', '6': '⁶',
+	'7': '⁷', '8': '⁸', '9': '⁹', '+': '⁺', '-': '⁻', '−': '⁻', '=': '⁼',
+	'(': '⁽', ')': '⁾', 'a': 'ᵃ', 'b': 'ᵇ', 'c': 'ᶜ', 'd': 'ᵈ', 'e': 'ᵉ',
+	'f': 'ᶠ', 'g': 'ᵍ', 'h': 'ʰ', 'i': 'ⁱ', 'j': 'ʲ', 'k': 'ᵏ', 'l': 'ˡ',
+	'm': 'ᵐ', 'n': 'ⁿ', 'o': 'ᵒ', 'p': 'ᵖ', 'r': 'ʳ', 's': 'ˢ', 't': 'ᵗ',
+	'u': 'ᵘ', 'v': 'ᵛ', 'w': 'ʷ', 'x': 'ˣ', 'y': 'ʸ', 'z': 'ᶻ', 'T': 'ᵀ',
+	'′': '′', '∗': '*', '*': '*',
+}
+
+var subscripts = map[rune]rune{
+	'0': '₀', '1': '₁', '2': '₂', '3': '₃', '4': '₄', '5': '₅', '6': '₆',
+	'7': '₇', '8': '₈', '9': '₉', '+': '₊', '-': '₋', '−': '₋', '=': '₌',
+	'(': '₍', ')': '₎', 'a': 'ₐ', 'e': 'ₑ', 'h': 'ₕ', 'i': 'ᵢ', 'j': 'ⱼ',
+	'k': 'ₖ', 'l': 'ₗ', 'm': 'ₘ', 'n': 'ₙ', 'o': 'ₒ', 'p': 'ₚ', 'r': 'ᵣ',
+	's': 'ₛ', 't': 'ₜ', 'u': 'ᵤ', 'v': 'ᵥ', 'x': 'ₓ',
+}
+
+// LatexToUnicode converts common inline LaTeX math to plain Unicode text:
+// Greek letters, operators, relations, arrows, \frac, \sqrt, \mathbb and
+// simple super- and subscripts. Unknown commands are left as written.
+func LatexToUnicode(src string) string {
+	var out strings.Builder
+	for i := 0; i < len(src); {
+		switch c := src[i]; c {
+		case '\\':
+			name, next := readCommand(src, i+1)
+			i = next
+			out.WriteString(convertCommand(name, src, &i))
+		case '^', '_':
+			arg, next := readGroup(src, i+1)
+			i = next
+			out.WriteString(script(LatexToUnicode(arg), c == '^'))
+		case '{', '}':
+			i++
+		case '~':
+			out.WriteByte(' ')
+			i++
+		default:
+			r, size := utf8.DecodeRuneInString(src[i:])
+			out.WriteRune(r)
+			i += size
+		}
+	}
+	return out.String()
+}
+
+func convertCommand(name, src string, i *int) string {
+	switch {
+	case name == "":
+		return "\\"
+	case name == "frac" || name == "dfrac" || name == "tfrac":
+		num, next := readGroup(src, *i)
+		den, next := readGroup(src, next)
+		*i = next
+		return fraction(LatexToUnicode(num), LatexToUnicode(den))
+	case name == "sqrt":
+		arg, next := readGroup(src, *i)
+		*i = next
+		return "√" + wrapIfLong(LatexToUnicode(arg))
+	case name == "mathbb":
+		arg, next := readGroup(src, *i)
+		*i = next
+		var out strings.Builder
+		for _, r := range arg {
+			if mapped, ok := blackboard[r]; ok {
+				out.WriteString(mapped)
+			} else {
+				out.WriteRune(r)
+			}
+		}
+		return out.String()
+	case latexTextCommands[name]:
+		arg, next := readGroup(src, *i)
+		*i = next
+		return LatexToUnicode(arg)
+	case latexSpacing[name]:
+		return " "
+	case latexIgnored[name]:
+		return ""
+	case len(name) == 1 && strings.ContainsAny(name, "{}%$&_#|"):
+		return name
+	}
+	if symbol, ok := latexSymbols[name]; ok {
+		return symbol
+	}
+	return "\\" + name
+}
+
+// readCommand reads a control word (letters) or a single control symbol.
+func readCommand(src string, i int) (string, int) {
+	if i >= len(src) {
+		return "", i
+	}
+	start := i
+	for i < len(src) && isASCIILetter(src[i]) {
+		i++
+	}
+	if i == start {
+		_, size := utf8.DecodeRuneInString(src[i:])
+		return src[i : i+size], i + size
+	}
+	return src[start:i], i
+}
+
+// readGroup reads one macro argument: a braced group, a control sequence
+// or a single rune.
+func readGroup(src string, i int) (string, int) {
+	for i < len(src) && src[i] == ' ' {
+		i++
+	}
+	if i >= len(src) {
+		return "", i
+	}
+	switch src[i] {
+	case '{':
+		depth := 0
+		for j := i; j < len(src); j++ {
+			switch src[j] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+				if depth == 0 {
+					return src[i+1 : j], j + 1
+				}
+			}
+		}
+		return src[i+1:], len(src)
+	case '\\':
+		_, next := readCommand(src, i+1)
+		return src[i:next], next
+	default:
+		_, size := utf8.DecodeRuneInString(src[i:])
+		return src[i : i+size], i + size
+	}
+}
+
+func script(text string, sup bool) string {
+	table := subscripts
+	marker := "_"
+	if sup {
+		table = superscripts
+		marker = "^"
+	}
+
+	var out strings.Builder
+	for _, r := range text {
+		mapped, ok := table[r]
+		if !ok {
+			if utf8.RuneCountInString(text) > 1 {
+				return marker + "(" + text + ")"
+			}
+			return marker + text
+		}
+		out.WriteRune(mapped)
+	}
+	return out.String()
+}
+
+func fraction(num, den string) string {
+	return wrapIfLong(num) + "/" + wrapIfLong(den)
+}
+
+func wrapIfLong(s string) string {
+	if utf8.RuneCountInString(s) <= 1 || isSimpleTerm(s) {
+		return s
+	}
+	return "(" + s + ")"
+}
+
+func isSimpleTerm(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
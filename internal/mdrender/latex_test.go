@@ -0,0 +1,35 @@
+package mdrender
+
+import "testing"
+
+func TestLatexToUnicode(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{src: `\alpha + \beta`, want: "α + β"},
+		{src: `x^2 + y_i`, want: "x² + yᵢ"},
+		{src: `x^{n+1}`, want: "xⁿ⁺¹"},
+		{src: `e^{i\pi}`, want: "e^(iπ)"},
+		{src: `\frac{1}{2}`, want: "1/2"},
+		{src: `\frac{a+b}{c}`, want: "(a+b)/c"},
+		{src: `\sqrt{d_k}`, want: "√dₖ"},
+		{src: `\mathbb{R}^d`, want: "ℝᵈ"},
+		{src: `\mathbf{x} \in \mathcal{X}`, want: "x ∈ X"},
+		{src: `O(n \log n)`, want: "O(n log n)"},
+		{src: `\sum_{i=1}^{N} x_i`, want: "∑ᵢ₌₁^N xᵢ"},
+		{src: `a \leq b \neq c`, want: "a ≤ b ≠ c"},
+		{src: `\left( x \right)`, want: "( x )"},
+		{src: `\text{softmax}(z)`, want: "softmax(z)"},
+		{src: `\unknown{x}`, want: `\unknownx`},
+		{src: `50\%`, want: "50%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			if got := LatexToUnicode(tt.src); got != tt.want {
+				t.Fatalf("LatexToUnicode(%q) = %q, want %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,614 @@
+package mdrender
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/paperzilla/pz/internal/layout"
+)
+
+const defaultWidth = 80
+
+const (
+	sgrReset     = "\x1b[0m"
+	sgrBold      = "1"
+	sgrDim       = "2"
+	sgrItalic    = "3"
+	sgrUnderline = "4"
+	sgrStrike    = "9"
+	sgrCyan      = "36"
+	sgrMagenta   = "35"
+	sgrBlue      = "34"
+)
+
+// Options controls terminal rendering.
+type Options struct {
+	// Width is the column at which text wraps. Zero or less means 80.
+	Width int
+	// Color enables ANSI styling. Without it the structure (wrapping,
+	// bullets, indentation, Unicode math) is kept as plain text.
+	Color bool
+	// Escape neutralizes terminal control characters in document text. It
+	// runs on every piece of source text before styling is applied, so the
+	// only escape sequences in the output are the renderer's own.
+	Escape func(string) string
+}
+
+var (
+	atxHeading    = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	thematicBreak = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	listMarker    = regexp.MustCompile(`^( *)([-*+]|\d{1,9}[.)])([ \t]+|$)`)
+	tableDivider  = regexp.MustCompile(`^ *\|? *:?-+:? *(\| *:?-+:? *)*\|? *$`)
+	fenceOpen     = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+	setextLine    = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	taskMarker    = regexp.MustCompile(`^\[([ xX])\][ \t]+`)
+)
+
+// Render formats markdown for display in a terminal.
+func Render(src string, opts Options) string {
+	if opts.Width <= 0 {
+		opts.Width = defaultWidth
+	}
+	if opts.Escape == nil {
+		opts.Escape = stripControls
+	}
+
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	r := renderer{opts: opts}
+	lines := r.blocks(strings.Split(src, "\n"), opts.Width)
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+type renderer struct {
+	opts  Options
+	depth int
+}
+
+func (r renderer) sgr(text string, codes ...string) string {
+	if !r.opts.Color || text == "" || len(codes) == 0 {
+		return text
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m" + text + sgrReset
+}
+
+// blocks renders a sequence of source lines to output lines no wider than
+// width. Blank lines separate rendered blocks.
+func (r renderer) blocks(lines []string, width int) []string {
+	width = max(width, 10)
+	var out []string
+	var paragraph []string
+
+	addBlock := func(block []string) {
+		if len(block) == 0 {
+			return
+		}
+		if len(out) > 0 && out[len(out)-1] != "" {
+			out = append(out, "")
+		}
+		out = append(out, block...)
+	}
+	flushParagraph := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		addBlock(r.wrap(parseInline(strings.Join(paragraph, " ")), width, "", ""))
+		paragraph = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.ReplaceAll(lines[i], "\t", "    ")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flushParagraph()
+		case fenceOpen.MatchString(line):
+			flushParagraph()
+			fence := fenceOpen.FindStringSubmatch(line)[1]
+			var code []string
+			for i++; i < len(lines); i++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+					break
+				}
+				code = append(code, lines[i])
+			}
+			addBlock(r.codeBlock(code))
+		case trimmed == "$$" || (strings.HasPrefix(trimmed, "$$") && !strings.HasSuffix(trimmed[2:], "$$")):
+			flushParagraph()
+			math := []string{strings.TrimPrefix(trimmed, "$$")}
+			for i++; i < len(lines); i++ {
+				if end := strings.Index(lines[i], "$$"); end >= 0 {
+					math = append(math, lines[i][:end])
+					break
+				}
+				math = append(math, lines[i])
+			}
+			addBlock(r.displayMath(strings.Join(math, " "), width))
+		case strings.HasPrefix(trimmed, "$$") && strings.HasSuffix(trimmed, "$$") && len(trimmed) > 4:
+			flushParagraph()
+			addBlock(r.displayMath(trimmed[2:len(trimmed)-2], width))
+		case atxHeading.MatchString(line):
+			flushParagraph()
+			match := atxHeading.FindStringSubmatch(line)
+			addBlock(r.heading(len(match[1]), match[2], width))
+		case len(paragraph) > 0 && setextLine.MatchString(line):
+			level := 2
+			if strings.HasPrefix(trimmed, "=") {
+				level = 1
+			}
+			text := strings.Join(paragraph, " ")
+			paragraph = nil
+			addBlock(r.heading(level, text, width))
+		case thematicBreak.MatchString(line):
+			flushParagraph()
+			addBlock([]string{r.sgr(strings.Repeat("─", width), sgrDim)})
+		case strings.HasPrefix(strings.TrimLeft(line, " "), ">"):
+			flushParagraph()
+			var quote []string
+			for ; i < len(lines); i++ {
+				l := strings.TrimLeft(lines[i], " ")
+				if !strings.HasPrefix(l, ">") {
+					i--
+					break
+				}
+				l = strings.TrimPrefix(l, ">")
+				quote = append(quote, strings.TrimPrefix(l, " "))
+			}
+			addBlock(r.quote(quote, width))
+		case listMarker.MatchString(line) && (len(paragraph) == 0 || !startsOrderedAfterText(line)):
+			flushParagraph()
+			var block []string
+			block, i = r.list(lines, i, width)
+			addBlock(block)
+		case len(paragraph) == 0 && isTableStart(lines, i):
+			divider := lines[i+1]
+			var body []string
+			for i += 2; i < len(lines) && strings.Contains(lines[i], "|") && strings.TrimSpace(lines[i]) != ""; i++ {
+				body = append(body, lines[i])
+			}
+			i--
+			addBlock(r.table(line, divider, body, width))
+		case len(paragraph) == 0 && strings.HasPrefix(line, "    "):
+			var code []string
+			for ; i < len(lines); i++ {
+				if strings.TrimSpace(lines[i]) != "" && !strings.HasPrefix(strings.ReplaceAll(lines[i], "\t", "    "), "    ") {
+					break
+				}
+				code = append(code, strings.TrimPrefix(strings.ReplaceAll(lines[i], "\t", "    "), "    "))
+			}
+			i--
+			for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+				code = code[:len(code)-1]
+			}
+			addBlock(r.codeBlock(code))
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flushParagraph()
+	return out
+}
+
+// startsOrderedAfterText prevents "2019. A year" inside a paragraph from
+// starting an ordered list; only "1." may interrupt a paragraph.
+func startsOrderedAfterText(line string) bool {
+	marker := listMarker.FindStringSubmatch(line)[2]
+	if marker == "-" || marker == "*" || marker == "+" {
+		return false
+	}
+	return marker != "1." && marker != "1)"
+}
+
+func (r renderer) heading(level int, text string, width int) []string {
+	plain := r.plainText(parseInline(strings.TrimSpace(text)))
+	if r.opts.Color {
+		codes := []string{sgrBold}
+		switch level {
+		case 1:
+			codes = append(codes, sgrUnderline, sgrMagenta)
+		case 2:
+			codes = append(codes, sgrMagenta)
+		}
+		return r.wrapPlain(plain, width, codes...)
+	}
+
+	lines := r.wrapPlain(plain, width)
+	if level <= 2 {
+		underline := "="
+		if level == 2 {
+			underline = "-"
+		}
+		longest := 0
+		for _, line := range lines {
+			longest = max(longest, layout.Width(line))
+		}
+		lines = append(lines, strings.Repeat(underline, longest))
+	}
+	return lines
+}
+
+func (r renderer) plainText(spans []span) string {
+	var text strings.Builder
+	for _, s := range spans {
+		text.WriteString(s.text)
+	}
+	return text.String()
+}
+
+func (r renderer) wrapPlain(text string, width int, codes ...string) []string {
+	lines := r.wrap([]span{{text: text}}, width, "", "")
+	for i := range lines {
+		lines[i] = r.sgr(lines[i], codes...)
+	}
+	return lines
+}
+
+func (r renderer) codeBlock(code []string) []string {
+	out := make([]string, 0, len(code))
+	for _, line := range code {
+		line = r.opts.Escape(strings.ReplaceAll(line, "\t", "    "))
+		out = append(out, "    "+r.sgr(line, sgrCyan))
+	}
+	if len(out) == 0 {
+		out = append(out, "")
+	}
+	return out
+}
+
+func (r renderer) displayMath(src string, width int) []string {
+	return r.wrapPlain(strings.TrimSpace(LatexToUnicode(src)), width-4, sgrItalic)
+}
+
+func (r renderer) quote(lines []string, width int) []string {
+	inner := r.blocks(lines, width-2)
+	bar := r.sgr("│", sgrDim)
+	out := make([]string, 0, len(inner))
+	for _, line := range inner {
+		if line == "" {
+			out = append(out, bar)
+			continue
+		}
+		out = append(out, bar+" "+line)
+	}
+	return out
+}
+
+// list renders the list starting at lines[start] and returns the index of
+// its last source line.
+func (r renderer) list(lines []string, start, width int) ([]string, int) {
+	first := listMarker.FindStringSubmatch(lines[start])
+	baseIndent := len(first[1])
+	ordered := isOrderedMarker(first[2])
+	isSibling := func(line string) bool {
+		match := listMarker.FindStringSubmatch(line)
+		return match != nil && len(match[1]) == baseIndent && isOrderedMarker(match[2]) == ordered
+	}
+
+	var out []string
+	i := start
+	for i < len(lines) && isSibling(lines[i]) {
+		match := listMarker.FindStringSubmatch(lines[i])
+		contentIndent := len(match[0])
+		if len(match[3]) > 4 {
+			contentIndent = len(match[1]) + len(match[2]) + 1
+		}
+		item := []string{lines[i][len(match[0]):]}
+
+		j := i + 1
+	collect:
+		for ; j < len(lines); j++ {
+			line := strings.ReplaceAll(lines[j], "\t", "    ")
+			indent := len(line) - len(strings.TrimLeft(line, " "))
+			switch {
+			case strings.TrimSpace(line) == "":
+				if !nextIndentedBeyond(lines, j, baseIndent) {
+					break collect
+				}
+				item = append(item, "")
+			case indent > baseIndent:
+				item = append(item, trimIndent(line, contentIndent))
+			case startsBlock(line):
+				break collect
+			default:
+				// Lazy continuation of the item's paragraph.
+				item = append(item, strings.TrimSpace(line))
+			}
+		}
+
+		out = append(out, r.listItem(r.bullet(match[2]), item, width)...)
+		i = j
+		if next := nextNonBlank(lines, i); next > i && isSibling(lines[next]) {
+			i = next
+		}
+	}
+	return out, i - 1
+}
+
+func isOrderedMarker(marker string) bool {
+	return !strings.ContainsAny(marker, "-*+")
+}
+
+func startsBlock(line string) bool {
+	return listMarker.MatchString(line) || atxHeading.MatchString(line) || fenceOpen.MatchString(line) ||
+		thematicBreak.MatchString(line) || strings.HasPrefix(strings.TrimLeft(line, " "), ">")
+}
+
+func (r renderer) bullet(marker string) string {
+	if isOrderedMarker(marker) {
+		return marker
+	}
+	bullets := []string{"•", "◦", "▪"}
+	return bullets[r.depth%len(bullets)]
+}
+
+func (r renderer) listItem(bullet string, item []string, width int) []string {
+	if match := taskMarker.FindStringSubmatch(item[0]); match != nil {
+		bullet = "☐"
+		if match[1] != " " {
+			bullet = "☑"
+		}
+		item[0] = item[0][len(match[0]):]
+	}
+
+	hang := layout.Width(bullet) + 1
+	nested := renderer{opts: r.opts, depth: r.depth + 1}
+	inner := nested.blocks(item, width-hang)
+	if isTight(item) {
+		inner = dropBlank(inner)
+	}
+	for len(inner) > 0 && inner[len(inner)-1] == "" {
+		inner = inner[:len(inner)-1]
+	}
+	if len(inner) == 0 {
+		inner = []string{""}
+	}
+
+	out := make([]string, 0, len(inner))
+	pad := strings.Repeat(" ", hang)
+	for n, line := range inner {
+		switch {
+		case n == 0:
+			out = append(out, r.sgr(bullet, sgrBold)+" "+line)
+		case line == "":
+			out = append(out, "")
+		default:
+			out = append(out, pad+line)
+		}
+	}
+	return out
+}
+
+// isTight reports whether a list item has no blank lines, in which case its
+// nested blocks are rendered without blank separators.
+func isTight(item []string) bool {
+	for _, line := range item {
+		if strings.TrimSpace(line) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func dropBlank(lines []string) []string {
+	out := lines[:0]
+	for _, line := range lines {
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+func nextIndentedBeyond(lines []string, i, indent int) bool {
+	next := nextNonBlank(lines, i)
+	if next < 0 {
+		return false
+	}
+	line := strings.ReplaceAll(lines[next], "\t", "    ")
+	return len(line)-len(strings.TrimLeft(line, " ")) > indent
+}
+
+func nextNonBlank(lines []string, i int) int {
+	for ; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != "" {
+			return i
+		}
+	}
+	return -1
+}
+
+func trimIndent(line string, n int) string {
+	for n > 0 && strings.HasPrefix(line, " ") {
+		line = line[1:]
+		n--
+	}
+	return line
+}
+
+func isTableStart(lines []string, i int) bool {
+	if i+1 >= len(lines) || !strings.Contains(lines[i], "|") || !tableDivider.MatchString(lines[i+1]) {
+		return false
+	}
+	return len(splitRow(lines[i])) == len(splitRow(lines[i+1]))
+}
+
+func (r renderer) table(header, divider string, body []string, width int) []string {
+	aligns := splitRow(divider)
+	var table layout.Table
+	for n, cell := range splitRow(header) {
+		align := aligns[n]
+		table.Columns = append(table.Columns, layout.Column{
+			Header:     r.cellText(cell),
+			Priority:   len(aligns) - n,
+			Flexible:   true,
+			MinWidth:   6,
+			AlignRight: strings.HasSuffix(align, ":") && !strings.HasPrefix(align, ":"),
+		})
+	}
+	for _, row := range body {
+		cells := splitRow(row)
+		texts := make([]string, len(cells))
+		for n, cell := range cells {
+			texts[n] = r.cellText(cell)
+		}
+		table.Rows = append(table.Rows, texts)
+	}
+
+	var rendered strings.Builder
+	_ = table.Render(&rendered, width)
+	lines := strings.Split(strings.TrimSuffix(rendered.String(), "\n"), "\n")
+	ruleWidth := 0
+	for _, line := range lines {
+		ruleWidth = max(ruleWidth, layout.Width(line))
+	}
+
+	out := []string{r.sgr(lines[0], sgrBold), r.sgr(strings.Repeat("─", ruleWidth), sgrDim)}
+	return append(out, lines[1:]...)
+}
+
+func (r renderer) cellText(cell string) string {
+	return r.opts.Escape(r.plainText(parseInline(cell)))
+}
+
+// splitRow splits a pipe table row into trimmed cells, honoring "\|".
+func splitRow(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, "\\|") {
+		row = row[:len(row)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '\\' && i+1 < len(row) && row[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case row[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(row[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// wrap lays out styled spans as lines of at most width cells. firstPrefix
+// and restPrefix are prepended verbatim and must already be safe.
+func (r renderer) wrap(spans []span, width int, firstPrefix, restPrefix string) []string {
+	words := splitWords(spans, r.opts.Escape)
+	if len(words) == 0 {
+		return nil
+	}
+
+	var lines []string
+	var line strings.Builder
+	prefix := firstPrefix
+	available := width - layout.Width(firstPrefix)
+	used := 0
+	for _, w := range words {
+		ww := w.width()
+		if used > 0 && used+1+ww > available {
+			lines = append(lines, prefix+line.String())
+			line.Reset()
+			prefix = restPrefix
+			available = width - layout.Width(restPrefix)
+			used = 0
+		}
+		if used > 0 {
+			line.WriteString(" ")
+			used++
+		}
+		for _, piece := range w {
+			line.WriteString(r.styled(piece))
+		}
+		used += ww
+	}
+	lines = append(lines, prefix+line.String())
+	return lines
+}
+
+func (r renderer) styled(s span) string {
+	var codes []string
+	if s.style.bold {
+		codes = append(codes, sgrBold)
+	}
+	if s.style.dim {
+		codes = append(codes, sgrDim)
+	}
+	if s.style.italic || s.style.math {
+		codes = append(codes, sgrItalic)
+	}
+	if s.style.strike {
+		codes = append(codes, sgrStrike)
+	}
+	if s.style.link {
+		codes = append(codes, sgrUnderline, sgrBlue)
+	}
+	if s.style.code {
+		codes = append(codes, sgrCyan)
+	}
+	return r.sgr(s.text, codes...)
+}
+
+type word []span
+
+func (w word) width() int {
+	total := 0
+	for _, piece := range w {
+		total += layout.Width(piece.text)
+	}
+	return total
+}
+
+// splitWords breaks spans at whitespace while keeping adjacent differently
+// styled pieces of one word together, e.g. "**bold**," stays one word.
+// Code spans and math keep their inner spaces so they never wrap mid-span.
+func splitWords(spans []span, escape func(string) string) []word {
+	var words []word
+	var current word
+	for _, s := range spans {
+		if s.style.code || s.style.math {
+			current = append(current, span{text: escape(s.text), style: s.style})
+			continue
+		}
+		text := s.text
+		for text != "" {
+			idx := strings.IndexFunc(text, unicode.IsSpace)
+			if idx < 0 {
+				current = append(current, span{text: escape(text), style: s.style})
+				break
+			}
+			if idx > 0 {
+				current = append(current, span{text: escape(text[:idx]), style: s.style})
+			}
+			if len(current) > 0 {
+				words = append(words, current)
+				current = nil
+			}
+			text = strings.TrimLeftFunc(text[idx:], unicode.IsSpace)
+		}
+	}
+	if len(current) > 0 {
+		words = append(words, current)
+	}
+	return words
+}
+
+func stripControls(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsPrint(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
@@ -0,0 +1,124 @@
+package mdrender
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/paperzilla/pz/internal/layout"
+)
+
+func TestRenderPlainStructure(t *testing.T) {
+	src := strings.Join([]string{
+		"# Attention Is All You Need",
+		"",
+		"We propose the **Transformer** with cost $O(n^2)$.",
+		"See [the code](https://example.com/code).",
+		"",
+		"## Results",
+		"",
+		"- first",
+		"- second",
+		"  - nested",
+		"",
+		"1. one",
+		"2. two",
+		"",
+		"> quoted",
+		"",
+		"```",
+		"print('hi')",
+		"```",
+		"",
+		"- [x] done",
+	}, "\n")
+
+	got := Render(src, Options{Width: 60})
+	want := strings.Join([]string{
+		"Attention Is All You Need",
+		"=========================",
+		"",
+		"We propose the Transformer with cost O(n²). See the code",
+		"(https://example.com/code).",
+		"",
+		"Results",
+		"-------",
+		"",
+		"• first",
+		"• second",
+		"  ◦ nested",
+		"",
+		"1. one",
+		"2. two",
+		"",
+		"│ quoted",
+		"",
+		"    print('hi')",
+		"",
+		"☑ done",
+		"",
+	}, "\n")
+	if got != want {
+		t.Fatalf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderWrapsToWidth(t *testing.T) {
+	src := strings.Repeat("Ελληνικά 日本語 words wrap cleanly. ", 20)
+	for _, line := range strings.Split(strings.TrimSuffix(Render(src, Options{Width: 40}), "\n"), "\n") {
+		if width := layout.Width(line); width > 40 {
+			t.Fatalf("line %q is %d cells wide, want <= 40", line, width)
+		}
+	}
+}
+
+func TestRenderTable(t *testing.T) {
+	src := "| Model | BLEU |\n|---|---:|\n| Transformer | 28.4 |\n| ByteNet | 23.75 |\n"
+	got := Render(src, Options{Width: 60})
+	want := "Model         BLEU\n" +
+		"──────────────────\n" +
+		"Transformer   28.4\n" +
+		"ByteNet      23.75\n"
+	if got != want {
+		t.Fatalf("Render() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestRenderColorUsesANSIStyling(t *testing.T) {
+	got := Render("# Title\n\nSome **bold** and `code`.", Options{Width: 60, Color: true})
+	for _, want := range []string{"\x1b[1;4;35mTitle\x1b[0m", "\x1b[1mbold\x1b[0m", "\x1b[36mcode\x1b[0m"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("Render() = %q, missing %q", got, want)
+		}
+	}
+}
+
+func TestRenderEscapesDocumentControls(t *testing.T) {
+	escape := func(s string) string { return strings.ReplaceAll(s, "\x1b", `\x1b`) }
+	src := "# Evil \x1b]52;c;payload\a\n\nText \x1b[2J here\n\n```\ncode \x1b[31m\n```\n\n| a |\n|---|\n| \x1b[5m |\n"
+
+	got := Render(src, Options{Width: 60, Color: true, Escape: escape})
+	stripped := strings.ReplaceAll(got, "\x1b[0m", "")
+	for _, code := range []string{"\x1b[1;4;35m", "\x1b[36m", "\x1b[1m", "\x1b[2m"} {
+		stripped = strings.ReplaceAll(stripped, code, "")
+	}
+	if strings.Contains(stripped, "\x1b") {
+		t.Fatalf("document escape sequence survived rendering: %q", got)
+	}
+	if !strings.Contains(got, `\x1b[2J`) {
+		t.Fatalf("escaped control not visible: %q", got)
+	}
+}
+
+func TestRenderDefaultEscapeDropsControls(t *testing.T) {
+	got := Render("Text \x1b[2J\a here", Options{})
+	if strings.ContainsAny(got, "\x1b\a") {
+		t.Fatalf("Render() = %q, want controls removed", got)
+	}
+}
+
+func TestRenderLeavesPricesAndIdentifiersAlone(t *testing.T) {
+	got := Render("Costs $5 and $10 for snake_case_name.", Options{Width: 80})
+	if got != "Costs $5 and $10 for snake_case_name.\n" {
+		t.Fatalf("Render() = %q", got)
+	}
+}
@@ -0,0 +1,269 @@
+// Package notify posts new papers to chat and webhook endpoints.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/paperzilla/pz/internal/config"
+)
+
+const (
+	DefaultAttempts = 4
+
+	requestTimeout = 15 * time.Second
+	initialBackoff = time.Second
+	maxBackoff     = 30 * time.Second
+)
+
+// Validate checks sinks from the settings file.
+func Validate(sinks []config.NotifySink) error {
+	names := map[string]bool{}
+	for i, sink := range sinks {
+		if strings.TrimSpace(sink.Name) == "" {
+			return fmt.Errorf("notify sink %d has no name", i+1)
+		}
+		if names[sink.Name] {
+			return fmt.Errorf("notify sink %q is defined twice", sink.Name)
+		}
+		names[sink.Name] = true
+		switch sink.Type {
+		case TypeWebhook, TypeSlack, TypeMattermost:
+		default:
+			return fmt.Errorf("notify sink %q: type must be webhook, slack or mattermost", sink.Name)
+		}
+		u, err := url.Parse(sink.URL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("notify sink %q: url must be an http(s) URL", sink.Name)
+		}
+	}
+	return nil
+}
+
+// Wants reports whether a sink announces this message.
+func Wants(sink config.NotifySink, msg Message) bool {
+	if msg.Item.RelevanceClass != 2 && !sink.IncludeRelated {
+		return false
+	}
+	if len(sink.Projects) == 0 {
+		return true
+	}
+	for _, id := range sink.Projects {
+		if id == msg.ProjectID {
+			return true
+		}
+	}
+	return false
+}
+
+// Notifier delivers messages to sinks, retrying transient failures and
+// announcing each paper at most once per sink.
+type Notifier struct {
+	Sinks  []config.NotifySink
+	State  *State
+	Client *http.Client
+	// Attempts is the number of tries per delivery. Defaults to
+	// DefaultAttempts.
+	Attempts  int
+	UserAgent string
+	// Logf reports failed attempts.
+	Logf func(format string, args ...any)
+	// Sleep waits between attempts. Defaults to a context-aware sleep.
+	Sleep func(context.Context, time.Duration) error
+}
+
+// Notify retries queued deliveries, then announces msg on every sink that
+// wants it and has not announced the paper yet. Deliveries that still fail
+// after retrying are returned and queued in the state, so one broken sink
+// neither holds up the others nor misses papers. While a sink is failing,
+// new messages for it are queued without being sent.
+func (n *Notifier) Notify(ctx context.Context, msg Message) error {
+	failing, errs := n.retryPending(ctx)
+	for _, sink := range n.Sinks {
+		if !Wants(sink, msg) || n.State.Announced(sink.Name, msg) || n.State.Queued(sink.Name, msg) {
+			continue
+		}
+		if failing[sink.Name] {
+			n.State.Queue(sink.Name, msg)
+		} else if err := n.Send(ctx, sink, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name, err))
+			n.State.Queue(sink.Name, msg)
+		} else {
+			n.State.Record(sink.Name, msg, time.Now())
+		}
+		if err := n.State.Save(); err != nil {
+			return err
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RetryPending resends the queued deliveries of n's sinks, oldest first,
+// and returns the errors of those that still fail.
+func (n *Notifier) RetryPending(ctx context.Context) error {
+	_, errs := n.retryPending(ctx)
+	return errors.Join(errs...)
+}
+
+// retryPending stops at a sink's first failure, keeping the rest of its
+// queue for later, and reports which sinks are still failing.
+func (n *Notifier) retryPending(ctx context.Context) (map[string]bool, []error) {
+	failing := map[string]bool{}
+	var errs []error
+	for _, sink := range n.Sinks {
+		queue := n.State.Pending[sink.Name]
+		if len(queue) == 0 {
+			continue
+		}
+		sent := 0
+		for _, msg := range queue {
+			if !n.State.Announced(sink.Name, msg) {
+				if err := n.Send(ctx, sink, msg); err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", sink.Name, err))
+					failing[sink.Name] = true
+					break
+				}
+				n.State.Record(sink.Name, msg, time.Now())
+			}
+			sent++
+		}
+		if sent == 0 {
+			continue
+		}
+		if rest := queue[sent:]; len(rest) > 0 {
+			n.State.Pending[sink.Name] = rest
+		} else {
+			delete(n.State.Pending, sink.Name)
+		}
+		if err := n.State.Save(); err != nil {
+			return failing, append(errs, err)
+		}
+	}
+	return failing, errs
+}
+
+// Send delivers msg to one sink without checking or recording whether it
+// was announced before.
+func (n *Notifier) Send(ctx context.Context, sink config.NotifySink, msg Message) error {
+	body, err := Payload(sink.Type, msg)
+	if err != nil {
+		return err
+	}
+
+	attempts := n.Attempts
+	if attempts < 1 {
+		attempts = DefaultAttempts
+	}
+	sleep := n.Sleep
+	if sleep == nil {
+		sleep = sleepContext
+	}
+
+	delay := initialBackoff
+	for attempt := 1; ; attempt++ {
+		retryDelay, err := n.post(ctx, sink.URL, body)
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt >= attempts {
+			return fmt.Errorf("delivery failed after %d attempt(s): %w", attempt, err)
+		}
+
+		wait := delay
+		if retryDelay > 0 {
+			wait = min(retryDelay, maxBackoff)
+		}
+		if n.Logf != nil {
+			n.Logf("%s: attempt %d failed: %v (retrying in %s)", sink.Name, attempt, err, wait)
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+		delay = min(delay*2, maxBackoff)
+	}
+}
+
+// permanentError is a rejection that retrying will not fix.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+func (n *Notifier) post(ctx context.Context, target string, body []byte) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, &permanentError{fmt.Errorf("invalid webhook URL")}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.UserAgent != "" {
+		req.Header.Set("User-Agent", n.UserAgent)
+	}
+
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, redactURL(err)
+	}
+	defer resp.Body.Close()
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return 0, nil
+	}
+	err = fmt.Errorf("HTTP %d", resp.StatusCode)
+	if text := strings.TrimSpace(string(detail)); text != "" {
+		err = fmt.Errorf("HTTP %d: %s", resp.StatusCode, text)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return retryAfter(resp.Header.Get("Retry-After")), err
+	}
+	return 0, &permanentError{err}
+}
+
+// redactURL drops the webhook URL from transport errors. Incoming webhook
+// URLs are secrets, and these errors end up in logs.
+func redactURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		host := ""
+		if u, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+			host = u.Host
+		}
+		return fmt.Errorf("POST %s: %w", host, urlErr.Err)
+	}
+	return err
+}
+
+func retryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,217 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/config"
+)
+
+func testMessage() Message {
+	return Message{
+		ProjectID:   "proj-1",
+		ProjectName: "Graph <Learning>",
+		Item: api.ProjectPaper{
+			ID:               "pp-1",
+			PaperTitle:       "Attention *is* all\nyou need",
+			Summary:          "Transformers & friends.",
+			PersonalizedNote: "Matches your interest in [sequence] models.",
+			RelevanceClass:   2,
+			RelevanceScore:   0.87,
+			Paper:            api.Paper{ID: "paper-1"},
+		},
+		Links: Links{Paperzilla: "https://paperzilla.ai/recommendations/pp-1", PDF: "https://arxiv.org/pdf/1706.03762"},
+	}
+}
+
+func TestPayloads(t *testing.T) {
+	msg := testMessage()
+
+	var slack chatPayload
+	data, _ := Payload(TypeSlack, msg)
+	json.Unmarshal(data, &slack)
+	wantSlack := "*<https://paperzilla.ai/recommendations/pp-1|Attention *is* all you need>*\n" +
+		"★ Must Read (87%) · Graph &lt;Learning&gt;\n" +
+		"Transformers &amp; friends.\n" +
+		"_Why it matters:_ Matches your interest in [sequence] models.\n" +
+		"<https://paperzilla.ai/recommendations/pp-1|Paperzilla> · <https://arxiv.org/pdf/1706.03762|PDF>"
+	if slack.Text != wantSlack {
+		t.Errorf("slack text =\n%s\nwant\n%s", slack.Text, wantSlack)
+	}
+
+	var mattermost chatPayload
+	data, _ = Payload(TypeMattermost, msg)
+	json.Unmarshal(data, &mattermost)
+	if !strings.HasPrefix(mattermost.Text, `**[Attention \*is\* all you need](https://paperzilla.ai/recommendations/pp-1)**`) ||
+		!strings.Contains(mattermost.Text, `interest in \[sequence\] models.`) || mattermost.Username != "Paperzilla" {
+		t.Errorf("mattermost payload = %+v", mattermost)
+	}
+
+	var webhook webhookPayload
+	data, _ = Payload(TypeWebhook, msg)
+	json.Unmarshal(data, &webhook)
+	if webhook.Event != "paper.ready" || webhook.Relevance != "must_read" || webhook.Title != "Attention *is* all you need" ||
+		webhook.Links.PDF != msg.Links.PDF || webhook.Item.ID != "pp-1" {
+		t.Errorf("webhook payload = %+v", webhook)
+	}
+
+	if _, err := Payload("irc", msg); err == nil {
+		t.Error("unknown type should fail")
+	}
+}
+
+func TestNotifyRetriesDeduplicatesAndFilters(t *testing.T) {
+	var bodies []string
+	status := []int{http.StatusServiceUnavailable, http.StatusOK}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, r.URL.Path+" "+string(body))
+		code := http.StatusOK
+		if len(status) > 0 {
+			code, status = status[0], status[1:]
+		}
+		w.WriteHeader(code)
+	}))
+	defer server.Close()
+
+	state, err := LoadState(filepath.Join(t.TempDir(), "notified.json"))
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	var logs []string
+	var waits []time.Duration
+	n := &Notifier{
+		Sinks: []config.NotifySink{
+			{Name: "team", Type: TypeWebhook, URL: server.URL + "/team"},
+			{Name: "other", Type: TypeSlack, URL: server.URL + "/other", Projects: []string{"proj-2"}},
+		},
+		State: state,
+		Logf:  func(format string, args ...any) { logs = append(logs, format) },
+		Sleep: func(_ context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		},
+	}
+
+	msg := testMessage()
+	if err := n.Notify(context.Background(), msg); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if len(bodies) != 2 || !strings.HasPrefix(bodies[1], "/team ") || len(logs) != 1 || waits[0] != time.Second {
+		t.Fatalf("bodies = %q, logs = %q, waits = %v", bodies, logs, waits)
+	}
+
+	// The same paper from another project is not announced again.
+	msg.ProjectID, msg.Item.ID = "proj-3", "pp-9"
+	if err := n.Notify(context.Background(), msg); err != nil {
+		t.Fatalf("second Notify: %v", err)
+	}
+	reloaded, _ := LoadState(state.path)
+	if len(bodies) != 2 || !reloaded.Announced("team", msg) {
+		t.Fatalf("paper announced twice: %q", bodies)
+	}
+
+	related := testMessage()
+	related.Item.RelevanceClass, related.Item.Paper.ID = 1, "paper-2"
+	if err := n.Notify(context.Background(), related); err != nil || len(bodies) != 2 {
+		t.Fatalf("related paper announced: %q, %v", bodies, err)
+	}
+}
+
+func TestNotifyQueuesFailedDeliveriesAndRetriesThem(t *testing.T) {
+	var delivered []string
+	fail := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			fail = false
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var payload webhookPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		delivered = append(delivered, payload.Item.ID)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "notified.json")
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	n := &Notifier{
+		Sinks:    []config.NotifySink{{Name: "team", Type: TypeWebhook, URL: server.URL}},
+		State:    state,
+		Attempts: 1,
+	}
+
+	first := testMessage()
+	if err := n.Notify(context.Background(), first); err == nil {
+		t.Fatal("Notify should report the failed delivery")
+	}
+	reloaded, _ := LoadState(path)
+	if !reloaded.Queued("team", first) || reloaded.Announced("team", first) {
+		t.Fatalf("failed delivery was not queued: %+v", reloaded)
+	}
+
+	// A restarted watch retries the queue before anything new.
+	n.State = reloaded
+	second := testMessage()
+	second.Item.ID, second.Item.Paper.ID = "pp-2", "paper-2"
+	if err := n.Notify(context.Background(), second); err != nil {
+		t.Fatalf("second Notify: %v", err)
+	}
+	if strings.Join(delivered, ",") != "pp-1,pp-2" {
+		t.Fatalf("delivered = %q", delivered)
+	}
+	reloaded, _ = LoadState(path)
+	if len(reloaded.Pending) != 0 || !reloaded.Announced("team", first) {
+		t.Fatalf("state = %+v", reloaded)
+	}
+}
+
+func TestSendStopsOnPermanentFailureAndRedactsURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	n := &Notifier{State: &State{Sinks: map[string]map[string]time.Time{}}}
+	err := n.Send(context.Background(), config.NotifySink{Name: "team", Type: TypeSlack, URL: server.URL + "/secret"}, testMessage())
+	if err == nil || err.Error() != "delivery failed after 1 attempt(s): HTTP 403: invalid_token" {
+		t.Fatalf("err = %v", err)
+	}
+
+	server.Close()
+	n.Attempts = 1
+	err = n.Send(context.Background(), config.NotifySink{Name: "team", Type: TypeSlack, URL: server.URL + "/secret"}, testMessage())
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		sinks []config.NotifySink
+		want  string
+	}{
+		{[]config.NotifySink{{Name: "a", Type: "slack", URL: "https://hooks.slack.com/x"}}, ""},
+		{[]config.NotifySink{{Type: "slack", URL: "https://x"}}, "has no name"},
+		{[]config.NotifySink{{Name: "a", Type: "irc", URL: "https://x"}}, "type must be"},
+		{[]config.NotifySink{{Name: "a", Type: "slack", URL: "file:///etc"}}, "http(s) URL"},
+		{[]config.NotifySink{{Name: "a", Type: "slack", URL: "https://x"}, {Name: "a", Type: "slack", URL: "https://y"}}, "defined twice"},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.sinks)
+		if (tt.want == "") != (err == nil) || (err != nil && !strings.Contains(err.Error(), tt.want)) {
+			t.Errorf("Validate(%+v) = %v, want %q", tt.sinks, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/paperzilla/pz/internal/api"
+)
+
+const (
+	TypeWebhook    = "webhook"
+	TypeSlack      = "slack"
+	TypeMattermost = "mattermost"
+
+	// summaryLimit keeps chat messages short; the full summary is one
+	// click away.
+	summaryLimit = 600
+)
+
+// Message is one new paper to announce.
+type Message struct {
+	ProjectID   string           `json:"project_id"`
+	ProjectName string           `json:"project_name"`
+	Item        api.ProjectPaper `json:"item"`
+	Links       Links            `json:"links"`
+}
+
+// Links are the http(s) links shown with a paper. Empty links are left out.
+type Links struct {
+	Paperzilla string `json:"paperzilla,omitempty"`
+	Paper      string `json:"paper,omitempty"`
+	PDF        string `json:"pdf,omitempty"`
+}
+
+type webhookPayload struct {
+	Event            string           `json:"event"`
+	ProjectID        string           `json:"project_id"`
+	ProjectName      string           `json:"project_name"`
+	Title            string           `json:"title"`
+	Relevance        string           `json:"relevance"`
+	RelevanceScore   float64          `json:"relevance_score"`
+	Summary          string           `json:"summary,omitempty"`
+	PersonalizedNote string           `json:"personalized_note,omitempty"`
+	Links            Links            `json:"links"`
+	Item             api.ProjectPaper `json:"item"`
+}
+
+type chatPayload struct {
+	Text     string `json:"text"`
+	Username string `json:"username,omitempty"`
+}
+
+// Payload builds the request body for a sink type.
+func Payload(sinkType string, msg Message) ([]byte, error) {
+	switch sinkType {
+	case TypeWebhook:
+		relevance := "related"
+		if msg.Item.RelevanceClass == 2 {
+			relevance = "must_read"
+		}
+		return json.Marshal(webhookPayload{
+			Event:            "paper.ready",
+			ProjectID:        msg.ProjectID,
+			ProjectName:      msg.ProjectName,
+			Title:            title(msg.Item),
+			Relevance:        relevance,
+			RelevanceScore:   msg.Item.RelevanceScore,
+			Summary:          msg.Item.Summary,
+			PersonalizedNote: msg.Item.PersonalizedNote,
+			Links:            msg.Links,
+			Item:             msg.Item,
+		})
+	case TypeSlack:
+		return json.Marshal(chatPayload{Text: chatText(msg, slackText{})})
+	case TypeMattermost:
+		return json.Marshal(chatPayload{Text: chatText(msg, markdownText{}), Username: "Paperzilla"})
+	default:
+		return nil, fmt.Errorf("unknown notification type %q", sinkType)
+	}
+}
+
+// chatFormat is the markup of one chat service.
+type chatFormat interface {
+	escape(text string) string
+	link(label, url string) string
+	bold(text string) string
+}
+
+// slackText is Slack's mrkdwn, which only needs &, < and > escaped.
+type slackText struct{}
+
+func (slackText) escape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+func (f slackText) link(label, url string) string {
+	return "<" + url + "|" + strings.ReplaceAll(f.escape(label), "|", "¦") + ">"
+}
+
+func (slackText) bold(text string) string { return "*" + text + "*" }
+
+// markdownText is the Markdown used by Mattermost.
+type markdownText struct{}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`,
+	"(", `\(`, ")", `\)`, "#", `\#`, "<", `\<`, ">", `\>`, "|", `\|`, "~", `\~`,
+)
+
+func (markdownText) escape(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+func (f markdownText) link(label, url string) string {
+	return "[" + f.escape(label) + "](" + strings.NewReplacer("(", "%28", ")", "%29").Replace(url) + ")"
+}
+
+func (markdownText) bold(text string) string { return "**" + text + "**" }
+
+func chatText(msg Message, f chatFormat) string {
+	item := msg.Item
+	var lines []string
+
+	heading := f.escape(title(item))
+	if url := firstNonEmpty(msg.Links.Paperzilla, msg.Links.Paper); url != "" {
+		heading = f.link(title(item), url)
+	}
+	lines = append(lines, f.bold(heading))
+
+	relevance := "Related"
+	if item.RelevanceClass == 2 {
+		relevance = "★ Must Read"
+	}
+	if item.RelevanceScore > 0 {
+		relevance += fmt.Sprintf(" (%d%%)", int(item.RelevanceScore*100))
+	}
+	lines = append(lines, relevance+" · "+f.escape(msg.ProjectName))
+
+	if summary := singleLine(item.Summary); summary != "" {
+		lines = append(lines, f.escape(truncate(summary, summaryLimit)))
+	}
+	if note := singleLine(item.PersonalizedNote); note != "" {
+		lines = append(lines, "_Why it matters:_ "+f.escape(truncate(note, summaryLimit)))
+	}
+
+	var links []string
+	for _, link := range []struct{ label, url string }{
+		{"Paperzilla", msg.Links.Paperzilla},
+		{"Paper", msg.Links.Paper},
+		{"PDF", msg.Links.PDF},
+	} {
+		if link.url != "" {
+			links = append(links, f.link(link.label, link.url))
+		}
+	}
+	if len(links) > 0 {
+		lines = append(lines, strings.Join(links, " · "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func title(item api.ProjectPaper) string {
+	if t := singleLine(item.PaperTitle); t != "" {
+		return t
+	}
+	if t := singleLine(item.Paper.Title); t != "" {
+		return t
+	}
+	return item.ID
+}
+
+// singleLine collapses whitespace and drops control characters, which
+// chat services render unpredictably.
+func singleLine(text string) string {
+	return strings.Join(strings.Fields(strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f {
+			return ' '
+		}
+		return r
+	}, text)), " ")
+}
+
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:limit-1])) + "…"
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/paperzilla/pz/internal/config"
+)
+
+// State records which papers each sink has announced, and the deliveries
+// that failed and are waiting to be retried.
+type State struct {
+	Sinks   map[string]map[string]time.Time `json:"sinks"`
+	Pending map[string][]Message            `json:"pending,omitempty"`
+
+	path string
+}
+
+func LoadState(path string) (*State, error) {
+	state := &State{Sinks: map[string]map[string]time.Time{}, path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notification state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse notification state %s: %w", path, err)
+	}
+	if state.Sinks == nil {
+		state.Sinks = map[string]map[string]time.Time{}
+	}
+	return state, nil
+}
+
+// announcementKey identifies the paper rather than the recommendation, so
+// a paper recommended in two projects is announced once per sink.
+func announcementKey(msg Message) string {
+	if msg.Item.Paper.ID != "" {
+		return "paper:" + msg.Item.Paper.ID
+	}
+	return "rec:" + msg.Item.ID
+}
+
+func (s *State) Announced(sink string, msg Message) bool {
+	_, ok := s.Sinks[sink][announcementKey(msg)]
+	return ok
+}
+
+func (s *State) Record(sink string, msg Message, at time.Time) {
+	if s.Sinks[sink] == nil {
+		s.Sinks[sink] = map[string]time.Time{}
+	}
+	s.Sinks[sink][announcementKey(msg)] = at.UTC()
+}
+
+// Queued reports whether msg is waiting to be retried on sink.
+func (s *State) Queued(sink string, msg Message) bool {
+	key := announcementKey(msg)
+	for _, pending := range s.Pending[sink] {
+		if announcementKey(pending) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Queue keeps a failed delivery so it can be retried later.
+func (s *State) Queue(sink string, msg Message) {
+	if s.Queued(sink, msg) {
+		return
+	}
+	if s.Pending == nil {
+		s.Pending = map[string][]Message{}
+	}
+	s.Pending[sink] = append(s.Pending[sink], msg)
+}
+
+// Save writes the state atomically.
+func (s *State) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode notification state: %w", err)
+	}
+	if err := config.WriteFileAtomic(s.path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write notification state: %w", err)
+	}
+	return nil
+}
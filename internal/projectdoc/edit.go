@@ -0,0 +1,177 @@
+package projectdoc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const errorComment = "# ERROR: "
+
+// editable is the subset of a document opened by pz project edit.
+type editable struct {
+	InterestDescription string   `yaml:"interest_description"`
+	Keywords            Keywords `yaml:"keywords"`
+	Email               Email    `yaml:"email"`
+}
+
+var editComments = map[string]string{
+	"interest_description": "What this project is about, in plain prose. Matching reads this text.",
+	"keywords":             "Positive keywords boost papers that mention them; negative keywords demote them.",
+	"email":                "Digest email schedule: frequency (e.g. daily or weekly) and time of day as HH:MM.",
+}
+
+// EditTemplate renders the editable fields of doc as YAML, with a comment
+// above each field explaining it.
+func EditTemplate(doc Document) ([]byte, error) {
+	var mapping yaml.Node
+	if err := mapping.Encode(editable{
+		InterestDescription: doc.InterestDescription,
+		Keywords:            doc.Keywords,
+		Email:               doc.Email,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to encode project: %w", err)
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key := mapping.Content[i]
+		key.HeadComment = editComments[key.Value]
+	}
+	root := &yaml.Node{
+		Kind: yaml.DocumentNode,
+		HeadComment: fmt.Sprintf("Editing project %s (%s).\n"+
+			"Save and quit to review the changes before they are applied.\n"+
+			"Quit without changes, or empty the file, to abort.", doc.Name, doc.ID),
+		Content: []*yaml.Node{&mapping},
+	}
+	return encodeNode(root)
+}
+
+// DecodeEdit reads a file written by EditTemplate on top of base. Only the
+// fields EditTemplate writes can be changed: any other key, such as name
+// or visibility, is unknown and an error.
+func DecodeEdit(data []byte, base Document) (Document, error) {
+	fields := editable{
+		InterestDescription: base.InterestDescription,
+		Keywords:            base.Keywords,
+		Email:               base.Email,
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&fields); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Document{}, errors.New("project file is empty")
+		}
+		return Document{}, fmt.Errorf("invalid project file: %w", err)
+	}
+	doc := base
+	doc.InterestDescription = fields.InterestDescription
+	doc.Keywords = fields.Keywords
+	doc.Email = fields.Email
+	return clean(doc), nil
+}
+
+// Annotate returns data with a comment above each field that has a problem,
+// replacing comments from an earlier Annotate. Problems without a field,
+// and every problem when data is not a YAML mapping, go at the top.
+func Annotate(data []byte, problems []Problem) []byte {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		var top strings.Builder
+		for _, problem := range problems {
+			top.WriteString(errorComment + problem.Message + "\n")
+		}
+		return append([]byte(top.String()), stripErrorLines(string(data))...)
+	}
+
+	clearErrorComments(&root)
+	var unplaced []string
+	for _, problem := range problems {
+		key := findKey(root.Content[0], problem.Field)
+		if key == nil {
+			unplaced = append(unplaced, errorComment+problem.Message)
+			continue
+		}
+		key.HeadComment = joinComments(errorComment+problem.Message, key.HeadComment)
+	}
+	if len(unplaced) > 0 {
+		root.HeadComment = joinComments(strings.Join(unplaced, "\n"), root.HeadComment)
+	}
+	annotated, err := encodeNode(&root)
+	if err != nil {
+		return data
+	}
+	return annotated
+}
+
+// Blank reports whether data holds nothing but comments and whitespace.
+func Blank(data []byte) bool {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
+
+func encodeNode(node *yaml.Node) ([]byte, error) {
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return nil, fmt.Errorf("failed to encode project: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode project: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// findKey returns the key node for a dotted field, or nil.
+func findKey(mapping *yaml.Node, field string) *yaml.Node {
+	if field == "" {
+		return nil
+	}
+	name, rest, nested := strings.Cut(field, ".")
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		if key.Value != name {
+			continue
+		}
+		if nested && value.Kind == yaml.MappingNode {
+			if found := findKey(value, rest); found != nil {
+				return found
+			}
+		}
+		return key
+	}
+	return nil
+}
+
+func clearErrorComments(node *yaml.Node) {
+	node.HeadComment = stripErrorLines(node.HeadComment)
+	for _, child := range node.Content {
+		clearErrorComments(child)
+	}
+}
+
+func stripErrorLines(text string) string {
+	var kept []string
+	for _, line := range strings.Split(text, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), strings.TrimSpace(errorComment)) {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+func joinComments(first, second string) string {
+	if second == "" {
+		return first
+	}
+	return first + "\n" + second
+}
@@ -0,0 +1,96 @@
+package projectdoc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEditTemplateRoundTrips(t *testing.T) {
+	doc := FromProject(testProject())
+	data, err := EditTemplate(doc)
+	if err != nil {
+		t.Fatalf("EditTemplate: %v", err)
+	}
+	want := `# Editing project Graph Learning (proj-1).
+# Save and quit to review the changes before they are applied.
+# Quit without changes, or empty the file, to abort.
+
+# What this project is about, in plain prose. Matching reads this text.
+interest_description: |-
+  Graph neural networks
+  for molecules
+# Positive keywords boost papers that mention them; negative keywords demote them.
+keywords:
+  positive:
+    - GNN
+    - message passing
+  negative: []
+# Digest email schedule: frequency (e.g. daily or weekly) and time of day as HH:MM.
+email:
+  frequency: weekly
+  time: "07:00"
+`
+	if string(data) != want {
+		t.Fatalf("template =\n%s", data)
+	}
+
+	decoded, err := DecodeEdit(data, doc)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if changes := Plan(doc, decoded); len(changes) != 0 {
+		t.Fatalf("changes = %#v", changes)
+	}
+}
+
+func TestAnnotatePlacesErrorsAboveFields(t *testing.T) {
+	data := []byte("# my note\ninterest_description: GNNs\nemail:\n  frequency: weekly\n  time: 7am\n")
+	annotated := string(Annotate(data, []Problem{
+		{Field: "email.time", Message: "email.time: must be HH:MM"},
+		{Field: "name", Message: "name is required"},
+	}))
+	want := "# ERROR: name is required\n\n# my note\ninterest_description: GNNs\nemail:\n  frequency: weekly\n  # ERROR: email.time: must be HH:MM\n  time: 7am\n"
+	if annotated != want {
+		t.Fatalf("annotated =\n%s", annotated)
+	}
+
+	// Fixing the field clears its old comment.
+	fixed := strings.Replace(annotated, "7am", `"07:30"`, 1)
+	again := string(Annotate([]byte(fixed), []Problem{{Field: "interest_description", Message: "too short"}}))
+	if strings.Contains(again, "HH:MM") || strings.Contains(again, "name is required") || !strings.Contains(again, "# ERROR: too short\n# my note\ninterest_description: GNNs\n") {
+		t.Fatalf("again =\n%s", again)
+	}
+}
+
+func TestAnnotateKeepsUnparseableText(t *testing.T) {
+	data := []byte("# ERROR: old\nemail: [unclosed\n")
+	annotated := string(Annotate(data, []Problem{{Message: "yaml: line 1: did not find expected ',' or ']'"}}))
+	want := "# ERROR: yaml: line 1: did not find expected ',' or ']'\nemail: [unclosed\n"
+	if annotated != want {
+		t.Fatalf("annotated = %q", annotated)
+	}
+	if !Blank([]byte("# only comments\n\n")) || Blank([]byte("# note\nname: x\n")) {
+		t.Fatal("Blank")
+	}
+}
+
+func TestDecodeEditRejectsFieldsOutsideTheTemplate(t *testing.T) {
+	doc := FromProject(testProject())
+	for _, data := range []string{
+		"visibility: public\n",
+		"name: Renamed\n",
+		"sources:\n  - id: 9\n",
+	} {
+		if _, err := DecodeEdit([]byte(data), doc); err == nil || !strings.Contains(err.Error(), "not found in type") {
+			t.Fatalf("DecodeEdit(%q) err = %v", data, err)
+		}
+	}
+
+	decoded, err := DecodeEdit([]byte("email:\n  time: \"08:15\"\n"), doc)
+	if err != nil {
+		t.Fatalf("DecodeEdit: %v", err)
+	}
+	if decoded.Email.Time != "08:15" || decoded.Email.Frequency != "weekly" || decoded.Name != doc.Name || decoded.Visibility != doc.Visibility {
+		t.Fatalf("decoded = %#v", decoded)
+	}
+}
@@ -0,0 +1,63 @@
+package projectdoc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// NormalizeKeyword lowercases a keyword and collapses its whitespace.
+func NormalizeKeyword(keyword string) string {
+	return strings.ToLower(strings.Join(strings.Fields(keyword), " "))
+}
+
+// NormalizeKeywords normalizes every keyword and drops empty ones and
+// duplicates, keeping the first occurrence.
+func NormalizeKeywords(keywords []string) []string {
+	normalized := []string{}
+	seen := map[string]bool{}
+	for _, keyword := range keywords {
+		keyword = NormalizeKeyword(keyword)
+		if keyword == "" || seen[keyword] {
+			continue
+		}
+		seen[keyword] = true
+		normalized = append(normalized, keyword)
+	}
+	return normalized
+}
+
+// KeywordConflicts returns the keywords that are in both lists, compared
+// after normalization, in the order they appear in positive.
+func KeywordConflicts(positive, negative []string) []string {
+	inNegative := map[string]bool{}
+	for _, keyword := range NormalizeKeywords(negative) {
+		inNegative[keyword] = true
+	}
+	var conflicts []string
+	for _, keyword := range NormalizeKeywords(positive) {
+		if inNegative[keyword] {
+			conflicts = append(conflicts, keyword)
+		}
+	}
+	return conflicts
+}
+
+// ReadKeywords reads one keyword per line. Blank lines and lines starting
+// with # are skipped.
+func ReadKeywords(r io.Reader) ([]string, error) {
+	var keywords []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keywords = append(keywords, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read keywords: %w", err)
+	}
+	return keywords, nil
+}
@@ -0,0 +1,28 @@
+package projectdoc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeKeywordsAndConflicts(t *testing.T) {
+	got := NormalizeKeywords([]string{" Graph  Transformers", "GNN", "", "graph transformers", "gnn"})
+	if !reflect.DeepEqual(got, []string{"graph transformers", "gnn"}) {
+		t.Fatalf("NormalizeKeywords = %#v", got)
+	}
+	conflicts := KeywordConflicts([]string{"Survey", "GNN", "review"}, []string{"review", "survey "})
+	if !reflect.DeepEqual(conflicts, []string{"survey", "review"}) {
+		t.Fatalf("KeywordConflicts = %#v", conflicts)
+	}
+}
+
+func TestReadKeywordsSkipsCommentsAndBlankLines(t *testing.T) {
+	keywords, err := ReadKeywords(strings.NewReader("# too broad\nsurvey\n\n  position paper  \n"))
+	if err != nil {
+		t.Fatalf("ReadKeywords: %v", err)
+	}
+	if !reflect.DeepEqual(keywords, []string{"survey", "position paper"}) {
+		t.Fatalf("keywords = %#v", keywords)
+	}
+}
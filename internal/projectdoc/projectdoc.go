@@ -0,0 +1,330 @@
+// Package projectdoc converts projects to and from a stable, reviewable
+// document and plans the changes needed to make a project match one.
+package projectdoc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/paperzilla/pz/internal/api"
+	"gopkg.in/yaml.v3"
+)
+
+// Document holds the editable fields of a project. Source names and
+// category codes are informational; only IDs are applied.
+type Document struct {
+	ID                  string     `yaml:"id" json:"id"`
+	Name                string     `yaml:"name" json:"name"`
+	Mode                string     `yaml:"mode" json:"mode"`
+	Visibility          string     `yaml:"visibility" json:"visibility"`
+	InterestDescription string     `yaml:"interest_description" json:"interest_description"`
+	Email               Email      `yaml:"email" json:"email"`
+	MaxCandidates       int        `yaml:"max_candidates" json:"max_candidates"`
+	MaxPapersPerDigest  int        `yaml:"max_papers_per_digest" json:"max_papers_per_digest"`
+	Keywords            Keywords   `yaml:"keywords" json:"keywords"`
+	Sources             []Source   `yaml:"sources" json:"sources"`
+	Categories          []Category `yaml:"categories" json:"categories"`
+}
+
+type Email struct {
+	Frequency string `yaml:"frequency" json:"frequency"`
+	Time      string `yaml:"time" json:"time"`
+}
+
+type Keywords struct {
+	Positive []string `yaml:"positive" json:"positive"`
+	Negative []string `yaml:"negative" json:"negative"`
+}
+
+type Source struct {
+	ID   int    `yaml:"id" json:"id"`
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+}
+
+type Category struct {
+	ID     int     `yaml:"id" json:"id"`
+	Code   string  `yaml:"code,omitempty" json:"code,omitempty"`
+	Weight float64 `yaml:"weight" json:"weight"`
+}
+
+// FromProject builds a document from a fetched project. Sources and
+// categories are sorted by ID so exports diff cleanly.
+func FromProject(p api.Project) Document {
+	doc := Document{
+		ID:                  p.ID,
+		Name:                strings.TrimSpace(p.Name),
+		Mode:                p.Mode,
+		Visibility:          p.Visibility,
+		InterestDescription: strings.TrimSpace(p.InterestDescription),
+		Email:               Email{Frequency: p.EmailFrequency, Time: p.EmailTime},
+		MaxCandidates:       p.MaxCandidates,
+		MaxPapersPerDigest:  p.MaxPapersPerDigests,
+		Keywords: Keywords{
+			Positive: append([]string{}, p.PositiveKeywords...),
+			Negative: append([]string{}, p.NegativeKeywords...),
+		},
+		Sources:    []Source{},
+		Categories: []Category{},
+	}
+	for _, source := range p.Sources {
+		doc.Sources = append(doc.Sources, Source{ID: source.ID, Name: source.Name})
+	}
+	for _, category := range p.Categories {
+		doc.Categories = append(doc.Categories, Category{ID: category.ID, Code: category.Code, Weight: category.Weight})
+	}
+	sort.Slice(doc.Sources, func(i, j int) bool { return doc.Sources[i].ID < doc.Sources[j].ID })
+	sort.Slice(doc.Categories, func(i, j int) bool { return doc.Categories[i].ID < doc.Categories[j].ID })
+	return doc
+}
+
+// EncodeYAML writes doc as YAML with two-space indentation.
+func EncodeYAML(w io.Writer, doc Document) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode project: %w", err)
+	}
+	return encoder.Close()
+}
+
+// Decode reads a YAML or JSON document on top of base, so keys missing from
+// data keep their value in base. Unknown keys are an error.
+func Decode(data []byte, base Document) (Document, error) {
+	doc := base
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Document{}, errors.New("project file is empty")
+		}
+		return Document{}, fmt.Errorf("invalid project file: %w", err)
+	}
+	return clean(doc), nil
+}
+
+// clean trims the free-text fields of doc and drops blank keywords.
+func clean(doc Document) Document {
+	doc.Name = strings.TrimSpace(doc.Name)
+	doc.InterestDescription = strings.TrimSpace(doc.InterestDescription)
+	doc.Keywords.Positive = cleanKeywords(doc.Keywords.Positive)
+	doc.Keywords.Negative = cleanKeywords(doc.Keywords.Negative)
+	return doc
+}
+
+func cleanKeywords(keywords []string) []string {
+	cleaned := []string{}
+	for _, keyword := range keywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			cleaned = append(cleaned, keyword)
+		}
+	}
+	return cleaned
+}
+
+// Problem is one invalid field. Field is a dotted key such as "email.time",
+// or empty when the problem is not tied to a field.
+type Problem struct {
+	Field   string
+	Message string
+}
+
+// ValidationError lists every problem in a document.
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		messages[i] = problem.Message
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Validate reports every local problem in doc as a *ValidationError.
+func Validate(doc Document) error {
+	var problems []Problem
+	add := func(field, format string, args ...any) {
+		problems = append(problems, Problem{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	if doc.Name == "" {
+		add("name", "name is required")
+	}
+	if doc.InterestDescription == "" {
+		add("interest_description", "interest_description is required")
+	}
+	if doc.MaxCandidates < 0 {
+		add("max_candidates", "max_candidates must not be negative")
+	}
+	if doc.MaxPapersPerDigest < 0 {
+		add("max_papers_per_digest", "max_papers_per_digest must not be negative")
+	}
+	sources := map[int]bool{}
+	for i, source := range doc.Sources {
+		switch {
+		case source.ID < 1:
+			add("sources", "sources[%d]: id must be a source ID", i)
+		case sources[source.ID]:
+			add("sources", "sources[%d]: source %d is listed twice", i, source.ID)
+		}
+		sources[source.ID] = true
+	}
+	categories := map[int]bool{}
+	for i, category := range doc.Categories {
+		switch {
+		case category.ID < 1:
+			add("categories", "categories[%d]: id must be a category ID", i)
+		case categories[category.ID]:
+			add("categories", "categories[%d]: category %d is listed twice", i, category.ID)
+		case category.Weight < 0:
+			add("categories", "categories[%d]: weight must not be negative", i)
+		}
+		categories[category.ID] = true
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// Change is one field that differs between two documents. Scalar fields set
+// Before and After; list fields set Added and Removed.
+type Change struct {
+	Field   string   `json:"field"`
+	Before  string   `json:"before,omitempty"`
+	After   string   `json:"after,omitempty"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// Plan lists the fields that must change to turn current into desired, in
+// document order. Keyword order is not significant.
+func Plan(current, desired Document) []Change {
+	var changes []Change
+	scalar := func(field, before, after string) {
+		if before != after {
+			changes = append(changes, Change{Field: field, Before: before, After: after})
+		}
+	}
+	list := func(field string, before, after []string, key func(string) string) {
+		added, removed := diffSets(before, after, key)
+		if len(added) > 0 || len(removed) > 0 {
+			changes = append(changes, Change{Field: field, Added: added, Removed: removed})
+		}
+	}
+
+	scalar("name", current.Name, desired.Name)
+	scalar("mode", current.Mode, desired.Mode)
+	scalar("visibility", current.Visibility, desired.Visibility)
+	scalar("interest_description", current.InterestDescription, desired.InterestDescription)
+	scalar("email.frequency", current.Email.Frequency, desired.Email.Frequency)
+	scalar("email.time", current.Email.Time, desired.Email.Time)
+	scalar("max_candidates", fmt.Sprint(current.MaxCandidates), fmt.Sprint(desired.MaxCandidates))
+	scalar("max_papers_per_digest", fmt.Sprint(current.MaxPapersPerDigest), fmt.Sprint(desired.MaxPapersPerDigest))
+	list("keywords.positive", current.Keywords.Positive, desired.Keywords.Positive, keywordKey)
+	list("keywords.negative", current.Keywords.Negative, desired.Keywords.Negative, keywordKey)
+	list("sources", sourceLabels(current.Sources), sourceLabels(desired.Sources), labelKey)
+	list("categories", categoryLabels(current.Categories), categoryLabels(desired.Categories), labelKey)
+	return changes
+}
+
+// Input builds an update that sends only the fields named in changes.
+func Input(desired Document, changes []Change) api.ProjectInput {
+	var input api.ProjectInput
+	for _, change := range changes {
+		switch change.Field {
+		case "name":
+			input.Name = &desired.Name
+		case "mode":
+			input.Mode = &desired.Mode
+		case "visibility":
+			input.Visibility = &desired.Visibility
+		case "interest_description":
+			input.InterestDescription = &desired.InterestDescription
+		case "email.frequency":
+			input.EmailFrequency = &desired.Email.Frequency
+		case "email.time":
+			input.EmailTime = &desired.Email.Time
+		case "max_candidates":
+			input.MaxCandidates = &desired.MaxCandidates
+		case "max_papers_per_digest":
+			input.MaxPapersPerDigests = &desired.MaxPapersPerDigest
+		case "keywords.positive":
+			keywords := append([]string{}, desired.Keywords.Positive...)
+			input.PositiveKeywords = &keywords
+		case "keywords.negative":
+			keywords := append([]string{}, desired.Keywords.Negative...)
+			input.NegativeKeywords = &keywords
+		case "sources":
+			ids := make([]int, len(desired.Sources))
+			for i, source := range desired.Sources {
+				ids[i] = source.ID
+			}
+			input.SourceIDs = &ids
+		case "categories":
+			categories := make([]api.ProjectCategoryInput, len(desired.Categories))
+			for i, category := range desired.Categories {
+				categories[i] = api.ProjectCategoryInput{ID: category.ID, Weight: category.Weight}
+			}
+			input.Categories = &categories
+		}
+	}
+	return input
+}
+
+func sourceLabels(sources []Source) []string {
+	labels := make([]string, len(sources))
+	for i, source := range sources {
+		labels[i] = fmt.Sprint(source.ID)
+		if source.Name != "" {
+			labels[i] += " (" + source.Name + ")"
+		}
+	}
+	return labels
+}
+
+// categoryLabels includes the weight, so a weight change shows as the old
+// entry removed and the new one added.
+func categoryLabels(categories []Category) []string {
+	labels := make([]string, len(categories))
+	for i, category := range categories {
+		labels[i] = fmt.Sprintf("%d:%g", category.ID, category.Weight)
+		if category.Code != "" {
+			labels[i] += " (" + category.Code + ")"
+		}
+	}
+	return labels
+}
+
+func keywordKey(keyword string) string { return keyword }
+
+// labelKey drops the informational name from a source or category label, so
+// a missing or renamed name does not count as a change.
+func labelKey(label string) string {
+	key, _, _ := strings.Cut(label, " (")
+	return key
+}
+
+func diffSets(before, after []string, key func(string) string) (added, removed []string) {
+	inBefore := map[string]bool{}
+	for _, label := range before {
+		inBefore[key(label)] = true
+	}
+	inAfter := map[string]bool{}
+	for _, label := range after {
+		inAfter[key(label)] = true
+		if !inBefore[key(label)] {
+			added = append(added, label)
+		}
+	}
+	for _, label := range before {
+		if !inAfter[key(label)] {
+			removed = append(removed, label)
+		}
+	}
+	return added, removed
+}
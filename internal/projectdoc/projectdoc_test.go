@@ -0,0 +1,139 @@
+package projectdoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/paperzilla/pz/internal/api"
+)
+
+func testProject() api.Project {
+	return api.Project{
+		ID:                  "proj-1",
+		Name:                "Graph Learning",
+		Mode:                "auto",
+		Visibility:          "private",
+		InterestDescription: "Graph neural networks\nfor molecules\n",
+		EmailFrequency:      "weekly",
+		EmailTime:           "07:00",
+		MaxCandidates:       200,
+		MaxPapersPerDigests: 10,
+		PositiveKeywords:    []string{"GNN", "message passing"},
+		NegativeKeywords:    []string{},
+		Sources:             []api.ProjectSource{{ID: 3, Name: "bioRxiv"}, {ID: 1, Name: "arXiv"}},
+		Categories:          []api.ProjectCategory{{ID: 15, Code: "cs.LG", Weight: 0.5}, {ID: 12, Code: "q-bio.BM", Weight: 1}},
+	}
+}
+
+func TestEncodeYAMLIsStableAndRoundTrips(t *testing.T) {
+	doc := FromProject(testProject())
+	var out bytes.Buffer
+	if err := EncodeYAML(&out, doc); err != nil {
+		t.Fatalf("EncodeYAML: %v", err)
+	}
+	want := `id: proj-1
+name: Graph Learning
+mode: auto
+visibility: private
+interest_description: |-
+  Graph neural networks
+  for molecules
+email:
+  frequency: weekly
+  time: "07:00"
+max_candidates: 200
+max_papers_per_digest: 10
+keywords:
+  positive:
+    - GNN
+    - message passing
+  negative: []
+sources:
+  - id: 1
+    name: arXiv
+  - id: 3
+    name: bioRxiv
+categories:
+  - id: 12
+    code: q-bio.BM
+    weight: 1
+  - id: 15
+    code: cs.LG
+    weight: 0.5
+`
+	if out.String() != want {
+		t.Fatalf("yaml =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	decoded, err := Decode(out.Bytes(), Document{})
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, doc) {
+		t.Fatalf("decoded = %#v\nwant %#v", decoded, doc)
+	}
+	if changes := Plan(doc, decoded); len(changes) != 0 {
+		t.Fatalf("changes = %#v", changes)
+	}
+}
+
+func TestDecodeKeepsMissingKeysAndRejectsUnknownOnes(t *testing.T) {
+	base := FromProject(testProject())
+	doc, err := Decode([]byte(`{"email": {"time": "08:15"}, "keywords": {"negative": [" survey ", ""]}}`), base)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if doc.Email.Frequency != "weekly" || doc.Email.Time != "08:15" || doc.Name != "Graph Learning" {
+		t.Fatalf("doc = %#v", doc)
+	}
+	if !reflect.DeepEqual(doc.Keywords.Negative, []string{"survey"}) || len(doc.Keywords.Positive) != 2 {
+		t.Fatalf("keywords = %#v", doc.Keywords)
+	}
+
+	if _, err := Decode([]byte("name: x\nemail_time: \"07:00\"\n"), base); err == nil || !strings.Contains(err.Error(), "email_time not found") {
+		t.Fatalf("err = %v", err)
+	}
+	if _, err := Decode([]byte(""), base); err == nil || err.Error() != "project file is empty" {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestPlanAndInputSendOnlyChanges(t *testing.T) {
+	current := FromProject(testProject())
+	desired := current
+	desired.Email.Time = "07:30"
+	desired.Keywords.Positive = []string{"message passing", "GNN", "equivariance"}
+	desired.Sources = []Source{{ID: 1}, {ID: 3, Name: "renamed"}}
+	desired.Categories = []Category{{ID: 12, Weight: 1}, {ID: 15, Weight: 0.8}}
+
+	changes := Plan(current, desired)
+	want := []Change{
+		{Field: "email.time", Before: "07:00", After: "07:30"},
+		{Field: "keywords.positive", Added: []string{"equivariance"}},
+		{Field: "categories", Added: []string{"15:0.8"}, Removed: []string{"15:0.5 (cs.LG)"}},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("changes = %#v", changes)
+	}
+
+	data, _ := json.Marshal(Input(desired, changes))
+	wantInput := `{"email_time":"07:30","positive_keywords":["message passing","GNN","equivariance"],"categories":[{"id":12,"weight":1},{"id":15,"weight":0.8}]}`
+	if string(data) != wantInput {
+		t.Fatalf("input = %s", data)
+	}
+}
+
+func TestValidateListsEveryProblem(t *testing.T) {
+	doc := FromProject(testProject())
+	doc.Name = ""
+	doc.Sources = append(doc.Sources, Source{ID: 1})
+	doc.Categories = append(doc.Categories, Category{ID: 0}, Category{ID: 20, Weight: -1})
+	err := Validate(doc)
+	want := "name is required\nsources[2]: source 1 is listed twice\ncategories[2]: id must be a category ID\ncategories[3]: weight must not be negative"
+	if err == nil || err.Error() != want {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package search
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/paperzilla/pz/internal/authors"
+)
+
+// Query is a parsed search. Documents must contain every term and phrase
+// and pass every filter.
+type Query struct {
+	Terms   []string
+	Phrases [][]string
+	Authors []string
+	Venues  []string
+	// YearFrom and YearTo bound the publication year; 0 means open.
+	YearFrom, YearTo int
+}
+
+// ParseQuery parses words, "quoted phrases" and the field prefixes
+// author:, venue: and year: (a year or a range like 2020-2023). Prefix
+// values may be quoted.
+func ParseQuery(input string) (Query, error) {
+	var q Query
+	words, err := splitQuery(input)
+	if err != nil {
+		return Query{}, err
+	}
+
+	for _, word := range words {
+		if !word.quoted {
+			if prefix, value, ok := strings.Cut(word.text, ":"); ok && value != "" {
+				handled, err := q.addFilter(strings.ToLower(prefix), value)
+				if err != nil {
+					return Query{}, err
+				}
+				if handled {
+					continue
+				}
+			}
+		}
+		q.addText(word.text)
+	}
+	return q, nil
+}
+
+func (q *Query) addFilter(prefix, value string) (bool, error) {
+	value = strings.Trim(value, `"`)
+	switch prefix {
+	case "author":
+		q.Authors = append(q.Authors, value)
+	case "venue":
+		q.Venues = append(q.Venues, authors.Fold(value))
+	case "year":
+		from, to, err := parseYears(value)
+		if err != nil {
+			return false, err
+		}
+		q.YearFrom, q.YearTo = from, to
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// addText adds a word or phrase. Words that tokenize into several terms,
+// like "graph-based", are treated as phrases.
+func (q *Query) addText(text string) {
+	var terms []string
+	for _, token := range Tokenize(text) {
+		terms = append(terms, token.Term)
+	}
+	switch len(terms) {
+	case 0:
+	case 1:
+		q.Terms = append(q.Terms, terms[0])
+	default:
+		q.Phrases = append(q.Phrases, terms)
+	}
+}
+
+// AllTerms returns each distinct term from words and phrases.
+func (q Query) AllTerms() []string {
+	seen := map[string]bool{}
+	var terms []string
+	add := func(term string) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	for _, term := range q.Terms {
+		add(term)
+	}
+	for _, phrase := range q.Phrases {
+		for _, term := range phrase {
+			add(term)
+		}
+	}
+	return terms
+}
+
+// HasText reports whether the query has words to rank by, as opposed to
+// only filters.
+func (q Query) HasText() bool {
+	return len(q.Terms) > 0 || len(q.Phrases) > 0
+}
+
+func (q Query) Empty() bool {
+	return !q.HasText() && len(q.Authors) == 0 && len(q.Venues) == 0 && q.YearFrom == 0 && q.YearTo == 0
+}
+
+// MatchesFilters checks the author, venue and year filters. Authors match
+// by name with authors.Matches; venues by case- and accent-insensitive
+// substring.
+func (q Query) MatchesFilters(authorNames []string, venue string, year int) bool {
+	for _, query := range q.Authors {
+		found := false
+		for _, name := range authorNames {
+			if authors.Parse(name).Matches(query) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	foldedVenue := authors.Fold(venue)
+	for _, query := range q.Venues {
+		if !strings.Contains(foldedVenue, query) {
+			return false
+		}
+	}
+	if q.YearFrom != 0 && year < q.YearFrom {
+		return false
+	}
+	if q.YearTo != 0 && (year == 0 || year > q.YearTo) {
+		return false
+	}
+	return true
+}
+
+func parseYears(value string) (int, int, error) {
+	if from, to, ok := strings.Cut(value, "-"); ok {
+		fromYear, err1 := parseYear(from)
+		toYear, err2 := parseYear(to)
+		if err1 != nil || err2 != nil || (fromYear != 0 && toYear != 0 && fromYear > toYear) {
+			return 0, 0, fmt.Errorf("invalid year range %q: use year:2024 or year:2020-2023", value)
+		}
+		return fromYear, toYear, nil
+	}
+	year, err := parseYear(value)
+	if err != nil || year == 0 {
+		return 0, 0, fmt.Errorf("invalid year %q: use year:2024 or year:2020-2023", value)
+	}
+	return year, year, nil
+}
+
+// parseYear accepts four digits or an empty string for an open bound.
+func parseYear(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if len(value) != 4 {
+		return 0, fmt.Errorf("invalid year")
+	}
+	return strconv.Atoi(value)
+}
+
+type queryWord struct {
+	text   string
+	quoted bool
+}
+
+// splitQuery splits on whitespace outside double quotes. A prefix followed
+// by a quoted value, like author:"van der Berg", stays one word.
+func splitQuery(input string) ([]queryWord, error) {
+	var words []queryWord
+	var current strings.Builder
+	inQuote, quotedWord := false, false
+
+	flush := func() {
+		if current.Len() > 0 {
+			words = append(words, queryWord{text: current.String(), quoted: quotedWord})
+		}
+		current.Reset()
+		quotedWord = false
+	}
+
+	for _, r := range input {
+		switch {
+		case r == '"':
+			if !inQuote && current.Len() == 0 {
+				quotedWord = true
+			} else if !inQuote {
+				// A quoted value after a prefix: keep the quote so the
+				// filter can strip it.
+				current.WriteRune(r)
+			} else if !quotedWord {
+				current.WriteRune(r)
+			}
+			inQuote = !inQuote
+			if !inQuote && quotedWord {
+				flush()
+			}
+		case unicode.IsSpace(r) && !inQuote:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quote in query")
+	}
+	flush()
+	return words, nil
+}
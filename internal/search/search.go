@@ -0,0 +1,283 @@
+// Package search implements the tokenizer, query language, BM25 ranking and
+// snippets behind offline search. Storage lives in the library package.
+package search
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/paperzilla/pz/internal/authors"
+)
+
+type Field int
+
+const (
+	FieldTitle Field = iota
+	FieldSummary
+	FieldNote
+	FieldAbstract
+	FieldMarkdown
+	NumFields
+)
+
+var FieldNames = [NumFields]string{"title", "summary", "note", "abstract", "markdown"}
+
+// Titles count most; full-text markdown least, since it is long and
+// mentions everything.
+var fieldWeights = [NumFields]float64{3, 1.5, 1.5, 1, 0.5}
+
+// BM25 parameters, the usual defaults.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+type Token struct {
+	Term       string
+	Start, End int // byte offsets in the original text
+	Pos        int
+}
+
+// Tokenize splits text into words of letters and digits and normalizes
+// each one with Normalize.
+func Tokenize(text string) []Token {
+	var tokens []Token
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		if term := Normalize(text[start:end]); term != "" {
+			tokens = append(tokens, Token{Term: term, Start: start, End: end, Pos: len(tokens)})
+		}
+		start = -1
+	}
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+	}
+	flush(len(text))
+	return tokens
+}
+
+// Normalize lowercases word, folds common diacritics and strips simple
+// English plurals so "Graphs" matches "graph".
+func Normalize(word string) string {
+	term := authors.Fold(word)
+	switch {
+	case len(term) > 4 && strings.HasSuffix(term, "ies"):
+		return term[:len(term)-3] + "y"
+	case len(term) > 3 && strings.HasSuffix(term, "s") &&
+		!strings.HasSuffix(term, "ss") && !strings.HasSuffix(term, "us") && !strings.HasSuffix(term, "is"):
+		return term[:len(term)-1]
+	}
+	return term
+}
+
+// Posting is one term's occurrences in one field of a document.
+type Posting struct {
+	Positions []int
+}
+
+// DocTerms maps each query term to its postings per field for one document.
+type DocTerms map[string]*[NumFields]Posting
+
+// Stats are collection-wide numbers used by BM25.
+type Stats struct {
+	Docs    int
+	DocFreq map[string]int
+	AvgLen  [NumFields]float64
+}
+
+// Score ranks a document with a field-weighted BM25: each field's term
+// frequency is normalized by that field's average length.
+func Score(q Query, lengths [NumFields]int, terms DocTerms, stats Stats) float64 {
+	score := 0.0
+	for _, term := range q.AllTerms() {
+		postings := terms[term]
+		if postings == nil {
+			continue
+		}
+		df := float64(stats.DocFreq[term])
+		idf := math.Log(1 + (float64(stats.Docs)-df+0.5)/(df+0.5))
+
+		for f := Field(0); f < NumFields; f++ {
+			tf := float64(len(postings[f].Positions))
+			if tf == 0 {
+				continue
+			}
+			norm := 1.0
+			if stats.AvgLen[f] > 0 {
+				norm = 1 - b + b*float64(lengths[f])/stats.AvgLen[f]
+			}
+			score += fieldWeights[f] * idf * tf * (k1 + 1) / (tf + k1*norm)
+		}
+	}
+	return score
+}
+
+// Matches reports whether the document contains every term and every
+// phrase of q. Phrase words must be adjacent within one field.
+func Matches(q Query, terms DocTerms) bool {
+	for _, term := range q.Terms {
+		if !hasTerm(terms[term]) {
+			return false
+		}
+	}
+	for _, phrase := range q.Phrases {
+		if !hasPhrase(phrase, terms) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasTerm(postings *[NumFields]Posting) bool {
+	if postings == nil {
+		return false
+	}
+	for _, posting := range postings {
+		if len(posting.Positions) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPhrase(phrase []string, terms DocTerms) bool {
+	first := terms[phrase[0]]
+	if first == nil {
+		return false
+	}
+	for f := Field(0); f < NumFields; f++ {
+	starts:
+		for _, start := range first[f].Positions {
+			for i, term := range phrase[1:] {
+				postings := terms[term]
+				if postings == nil || !containsInt(postings[f].Positions, start+i+1) {
+					continue starts
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, want int) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
+
+// Segment is a piece of a snippet; Match marks highlighted words.
+type Segment struct {
+	Text  string
+	Match bool
+}
+
+// Snippet cuts a window of about width runes from text around the first
+// cluster of query terms, collapsing whitespace. It returns nil when text
+// contains none of the terms.
+func Snippet(text string, terms []string, width int) []Segment {
+	want := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		want[term] = true
+	}
+
+	tokens := Tokenize(text)
+	best, bestCount := -1, 0
+	for i, token := range tokens {
+		if !want[token.Term] {
+			continue
+		}
+		count := 0
+		for _, other := range tokens[i:] {
+			if utf8.RuneCountInString(text[token.Start:other.End]) > width {
+				break
+			}
+			if want[other.Term] {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = i, count
+		}
+	}
+	if best < 0 {
+		return nil
+	}
+
+	// Start a little before the first match so it reads in context.
+	start := tokens[best].Start
+	for i := best - 1; i >= 0 && utf8.RuneCountInString(text[tokens[i].Start:tokens[best].Start]) < width/4; i-- {
+		start = tokens[i].Start
+	}
+	end := start
+	for _, token := range tokens {
+		if token.Start < start {
+			continue
+		}
+		if utf8.RuneCountInString(text[start:token.End]) > width {
+			break
+		}
+		end = token.End
+	}
+
+	var segments []Segment
+	if start > tokens[0].Start {
+		segments = append(segments, Segment{Text: "… "})
+	}
+	cursor := start
+	for _, token := range tokens {
+		if token.Start < start || token.End > end || !want[token.Term] {
+			continue
+		}
+		segments = appendText(segments, text[cursor:token.Start])
+		segments = append(segments, Segment{Text: text[token.Start:token.End], Match: true})
+		cursor = token.End
+	}
+	truncated := end < tokens[len(tokens)-1].End
+	if !truncated {
+		// Keep closing punctuation after the last word.
+		end = len(strings.TrimRightFunc(text, unicode.IsSpace))
+	}
+	segments = appendText(segments, text[cursor:end])
+	if truncated {
+		segments = append(segments, Segment{Text: " …"})
+	}
+	return segments
+}
+
+func appendText(segments []Segment, text string) []Segment {
+	var collapsed strings.Builder
+	space := false
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if space {
+			collapsed.WriteByte(' ')
+			space = false
+		}
+		collapsed.WriteRune(r)
+	}
+	if space {
+		collapsed.WriteByte(' ')
+	}
+	if collapsed.Len() == 0 {
+		return segments
+	}
+	return append(segments, Segment{Text: collapsed.String()})
+}
@@ -0,0 +1,133 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenizeNormalizesAndKeepsOffsets(t *testing.T) {
+	text := "Graph Networks für Gödel's theories"
+	var terms []string
+	for _, token := range Tokenize(text) {
+		terms = append(terms, token.Term)
+	}
+	want := []string{"graph", "network", "fur", "godel", "s", "theory"}
+	if !reflect.DeepEqual(terms, want) {
+		t.Fatalf("terms = %q, want %q", terms, want)
+	}
+	token := Tokenize(text)[3]
+	if text[token.Start:token.End] != "Gödel" || token.Pos != 3 {
+		t.Fatalf("token = %+v", token)
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	q, err := ParseQuery(`graphs "message passing" author:"van der Berg" venue:NeurIPS year:2020-2023 graph-based foo:bar`)
+	if err != nil {
+		t.Fatalf("ParseQuery: %v", err)
+	}
+	want := Query{
+		Terms:    []string{"graph"},
+		Phrases:  [][]string{{"message", "passing"}, {"graph", "based"}, {"foo", "bar"}},
+		Authors:  []string{"van der Berg"},
+		Venues:   []string{"neurips"},
+		YearFrom: 2020,
+		YearTo:   2023,
+	}
+	if !reflect.DeepEqual(q, want) {
+		t.Fatalf("query = %+v\nwant    %+v", q, want)
+	}
+
+	for _, bad := range []string{`"open`, "year:20", "year:2024-2020"} {
+		if _, err := ParseQuery(bad); err == nil {
+			t.Errorf("ParseQuery(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestMatchesFilters(t *testing.T) {
+	q, _ := ParseQuery("author:berg venue:neurips year:2022-")
+	authors := []string{"Ada Lovelace", "Jan van den Berg"}
+	if !q.MatchesFilters(authors, "NeurIPS 2023", 2023) {
+		t.Fatal("should match")
+	}
+	if q.MatchesFilters(authors, "ICML", 2023) || q.MatchesFilters(authors, "NeurIPS", 2021) || q.MatchesFilters([]string{"Greenberg"}, "NeurIPS", 2023) {
+		t.Fatal("filters should reject")
+	}
+}
+
+func docTerms(fields [NumFields]string, q Query) (DocTerms, [NumFields]int) {
+	terms := DocTerms{}
+	var lengths [NumFields]int
+	for f, text := range fields {
+		tokens := Tokenize(text)
+		lengths[f] = len(tokens)
+		for _, token := range tokens {
+			if terms[token.Term] == nil {
+				terms[token.Term] = &[NumFields]Posting{}
+			}
+			terms[token.Term][f].Positions = append(terms[token.Term][f].Positions, token.Pos)
+		}
+	}
+	return terms, lengths
+}
+
+func TestMatchesRequiresAllTermsAndAdjacentPhrases(t *testing.T) {
+	q, _ := ParseQuery(`graph "neural network"`)
+	match, _ := docTerms([NumFields]string{"Graph neural networks", "", "", "", ""}, q)
+	split, _ := docTerms([NumFields]string{"Graph neural", "", "", "network theory", ""}, q)
+	missing, _ := docTerms([NumFields]string{"Neural networks", "", "", "", ""}, q)
+
+	if !Matches(q, match) {
+		t.Fatal("adjacent phrase should match")
+	}
+	if Matches(q, split) {
+		t.Fatal("phrase split across fields should not match")
+	}
+	if Matches(q, missing) {
+		t.Fatal("missing term should not match")
+	}
+}
+
+func TestScorePrefersTitleMatchesAndRareTerms(t *testing.T) {
+	q, _ := ParseQuery("transformer")
+	inTitle, titleLengths := docTerms([NumFields]string{"Transformer models", "", "", "We study attention.", ""}, q)
+	inAbstract, abstractLengths := docTerms([NumFields]string{"Attention models", "", "", "We study the transformer.", ""}, q)
+	stats := Stats{Docs: 10, DocFreq: map[string]int{"transformer": 2}, AvgLen: [NumFields]float64{2, 0, 0, 4, 0}}
+
+	title := Score(q, titleLengths, inTitle, stats)
+	abstract := Score(q, abstractLengths, inAbstract, stats)
+	if title <= abstract || abstract <= 0 {
+		t.Fatalf("title score %f should beat abstract score %f", title, abstract)
+	}
+
+	stats.DocFreq["transformer"] = 9
+	if common := Score(q, titleLengths, inTitle, stats); common >= title {
+		t.Fatalf("common term score %f should be below rare term score %f", common, title)
+	}
+}
+
+func TestSnippetHighlightsMatches(t *testing.T) {
+	text := "Intro text that goes on for a while before the important part.\n\nWe propose graph   transformers for molecules, and graph pooling."
+	segments := Snippet(text, []string{"graph", "transformer"}, 60)
+
+	var plain, marked strings.Builder
+	for _, segment := range segments {
+		plain.WriteString(segment.Text)
+		if segment.Match {
+			marked.WriteString("[" + segment.Text + "]")
+		} else {
+			marked.WriteString(segment.Text)
+		}
+	}
+	if !strings.HasPrefix(plain.String(), "… ") {
+		t.Fatalf("snippet should mark the cut start: %q", plain.String())
+	}
+	if !strings.Contains(marked.String(), "[graph] [transformers] for molecules") {
+		t.Fatalf("snippet = %q", marked.String())
+	}
+	if Snippet(text, []string{"absent"}, 60) != nil {
+		t.Fatal("no match should give no snippet")
+	}
+}
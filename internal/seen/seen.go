@@ -0,0 +1,87 @@
+// Package seen tracks which recommendations have been shown or opened, since
+// the API has no read/unread state.
+package seen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/paperzilla/pz/internal/config"
+)
+
+// Mark is when a recommendation was first seen, and in which project. The
+// project is empty for recommendations opened outside a feed.
+type Mark struct {
+	ProjectID string    `json:"project_id,omitempty"`
+	SeenAt    time.Time `json:"seen_at"`
+}
+
+// Store holds seen recommendations keyed by account and project-paper ID.
+type Store struct {
+	Accounts map[string]map[string]Mark `json:"accounts"`
+
+	path string
+}
+
+func Load(path string) (*Store, error) {
+	store := &Store{Accounts: map[string]map[string]Mark{}, path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seen state: %w", err)
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("failed to parse seen state %s: %w", path, err)
+	}
+	if store.Accounts == nil {
+		store.Accounts = map[string]map[string]Mark{}
+	}
+	return store, nil
+}
+
+func (s *Store) Seen(account, id string) bool {
+	_, ok := s.Accounts[account][id]
+	return ok
+}
+
+// Mark records ids as seen in projectID and returns how many were new.
+// Recommendations seen before keep their first mark.
+func (s *Store) Mark(account, projectID string, ids []string, at time.Time) int {
+	if s.Accounts[account] == nil {
+		s.Accounts[account] = map[string]Mark{}
+	}
+	added := 0
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		if mark, ok := s.Accounts[account][id]; ok {
+			if mark.ProjectID == "" && projectID != "" {
+				mark.ProjectID = projectID
+				s.Accounts[account][id] = mark
+			}
+			continue
+		}
+		s.Accounts[account][id] = Mark{ProjectID: projectID, SeenAt: at.UTC()}
+		added++
+	}
+	return added
+}
+
+// Save writes the store atomically.
+func (s *Store) Save() error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode seen state: %w", err)
+	}
+	if err := config.WriteFileAtomic(s.path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write seen state: %w", err)
+	}
+	return nil
+}
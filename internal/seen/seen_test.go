@@ -0,0 +1,37 @@
+package seen
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMarkIsPerAccountAndSurvivesReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seen.json")
+	store, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	first := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	if n := store.Mark("alice", "", []string{"pp-1"}, first); n != 1 {
+		t.Fatalf("Mark = %d", n)
+	}
+	if n := store.Mark("alice", "proj-1", []string{"pp-1", "pp-2", ""}, first.Add(time.Hour)); n != 1 {
+		t.Fatalf("second Mark = %d", n)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reloaded.Seen("alice", "pp-1") || !reloaded.Seen("alice", "pp-2") || reloaded.Seen("bob", "pp-1") {
+		t.Fatalf("accounts = %+v", reloaded.Accounts)
+	}
+	if mark := reloaded.Accounts["alice"]["pp-1"]; mark.ProjectID != "proj-1" || !mark.SeenAt.Equal(first) {
+		t.Fatalf("pp-1 mark = %+v, want first time kept and project filled in", mark)
+	}
+}
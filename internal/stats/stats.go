@@ -0,0 +1,244 @@
+// Package stats summarizes a project's feed to show whether its matching
+// is healthy.
+package stats
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+	"github.com/paperzilla/pz/internal/authors"
+)
+
+// TopN is how many venues, sources and first authors a report keeps.
+const TopN = 5
+
+// HistogramBuckets splits relevance scores from 0 to 1 into equal buckets.
+const HistogramBuckets = 10
+
+type Report struct {
+	Items         int           `json:"items"`
+	Weeks         []WeekCount   `json:"weeks"`
+	MustRead      int           `json:"must_read"`
+	Related       int           `json:"related"`
+	MustReadRatio float64       `json:"must_read_ratio"`
+	Relevance     []Bucket      `json:"relevance"`
+	Feedback      Feedback      `json:"feedback"`
+	TopVenues     []Count       `json:"top_venues"`
+	TopSources    []Count       `json:"top_sources"`
+	TopAuthors    []Count       `json:"top_first_authors"`
+	Latency       *LatencyStats `json:"latency,omitempty"`
+}
+
+// WeekCount is the number of items that became ready in the week starting
+// on Monday Start (UTC).
+type WeekCount struct {
+	Start string `json:"start"`
+	Count int    `json:"count"`
+}
+
+// Bucket counts relevance scores from Min up to, but not including, Max;
+// the last bucket includes 1.
+type Bucket struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
+
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Feedback counts items by vote; Reasons breaks down downvotes, with
+// "unspecified" for downvotes without a reason.
+type Feedback struct {
+	None     int     `json:"none"`
+	Upvote   int     `json:"upvote"`
+	Star     int     `json:"star"`
+	Downvote int     `json:"downvote"`
+	Reasons  []Count `json:"downvote_reasons"`
+}
+
+// LatencyStats is how long papers took from publication to being ready in
+// the feed. Samples is how many items had both dates.
+type LatencyStats struct {
+	MedianDays float64 `json:"median_days"`
+	Samples    int     `json:"samples"`
+}
+
+// Compute builds a report from feed items. Weeks run from the first item's
+// week to now, so weeks without items show up as zeros.
+func Compute(items []api.ProjectPaper, now time.Time) Report {
+	report := Report{
+		Items:      len(items),
+		Weeks:      []WeekCount{},
+		Relevance:  make([]Bucket, HistogramBuckets),
+		TopVenues:  []Count{},
+		TopSources: []Count{},
+		TopAuthors: []Count{},
+	}
+	for i := range report.Relevance {
+		report.Relevance[i] = Bucket{Min: float64(i) / HistogramBuckets, Max: float64(i+1) / HistogramBuckets}
+	}
+
+	weeks := map[time.Time]int{}
+	venues := newCounter()
+	sources := newCounter()
+	firstAuthors := newCounter()
+	reasons := newCounter()
+	var latencies []float64
+	for _, item := range items {
+		if item.RelevanceClass == 2 {
+			report.MustRead++
+		} else {
+			report.Related++
+		}
+		report.Relevance[bucketIndex(item.RelevanceScore)].Count++
+
+		switch vote := feedbackVote(item.Feedback); vote {
+		case "upvote":
+			report.Feedback.Upvote++
+		case "star":
+			report.Feedback.Star++
+		case "downvote":
+			report.Feedback.Downvote++
+			reason := item.Feedback.DownvoteReason
+			if reason == "" {
+				reason = "unspecified"
+			}
+			reasons.add(reason, reason)
+		default:
+			report.Feedback.None++
+		}
+
+		if venue := strings.TrimSpace(item.Paper.VenueName); venue != "" {
+			venues.add(strings.ToLower(venue), venue)
+		}
+		if item.Paper.Source != nil && strings.TrimSpace(item.Paper.Source.Name) != "" {
+			name := strings.TrimSpace(item.Paper.Source.Name)
+			sources.add(strings.ToLower(name), name)
+		}
+		if len(item.Paper.Authors) > 0 && strings.TrimSpace(item.Paper.Authors[0].Name) != "" {
+			name := authors.Parse(item.Paper.Authors[0].Name)
+			firstAuthors.add(name.SortKey(), name.String())
+		}
+
+		ready, readyOK := parseTime(item.ReadyAt)
+		if readyOK {
+			weeks[weekStart(ready)]++
+		}
+		if published, ok := parseTime(item.Paper.PublishedDate); ok && readyOK && !ready.Before(published) {
+			latencies = append(latencies, ready.Sub(published).Hours()/24)
+		}
+	}
+
+	if report.Items > 0 {
+		report.MustReadRatio = float64(report.MustRead) / float64(report.Items)
+	}
+	report.Feedback.Reasons = reasons.top(0)
+	report.TopVenues = venues.top(TopN)
+	report.TopSources = sources.top(TopN)
+	report.TopAuthors = firstAuthors.top(TopN)
+	report.Weeks = weekCounts(weeks, weekStart(now))
+	if len(latencies) > 0 {
+		report.Latency = &LatencyStats{MedianDays: median(latencies), Samples: len(latencies)}
+	}
+	return report
+}
+
+func feedbackVote(f *api.Feedback) string {
+	if f == nil {
+		return ""
+	}
+	return f.Vote
+}
+
+func bucketIndex(score float64) int {
+	index := int(math.Floor(score * HistogramBuckets))
+	return min(max(index, 0), HistogramBuckets-1)
+}
+
+// parseTime reads RFC 3339 timestamps and plain dates.
+func parseTime(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+		if t, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func weekCounts(weeks map[time.Time]int, last time.Time) []WeekCount {
+	counts := []WeekCount{}
+	if len(weeks) == 0 {
+		return counts
+	}
+	first := last
+	for week := range weeks {
+		if week.Before(first) {
+			first = week
+		}
+		if week.After(last) {
+			last = week
+		}
+	}
+	for week := first; !week.After(last); week = week.AddDate(0, 0, 7) {
+		counts = append(counts, WeekCount{Start: week.Format("2006-01-02"), Count: weeks[week]})
+	}
+	return counts
+}
+
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	middle := len(values) / 2
+	if len(values)%2 == 1 {
+		return values[middle]
+	}
+	return (values[middle-1] + values[middle]) / 2
+}
+
+// counter counts by key and remembers the first display name for each key.
+type counter struct {
+	counts map[string]int
+	names  map[string]string
+}
+
+func newCounter() *counter {
+	return &counter{counts: map[string]int{}, names: map[string]string{}}
+}
+
+func (c *counter) add(key, name string) {
+	if _, ok := c.names[key]; !ok {
+		c.names[key] = name
+	}
+	c.counts[key]++
+}
+
+// top returns the n largest counts, or all of them when n is 0. Ties are
+// broken by name so reports are stable.
+func (c *counter) top(n int) []Count {
+	counts := make([]Count, 0, len(c.counts))
+	for key, count := range c.counts {
+		counts = append(counts, Count{Name: c.names[key], Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	if n > 0 && len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
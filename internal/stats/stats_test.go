@@ -0,0 +1,75 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+)
+
+func testItem(class int, score float64, ready, published string, feedback *api.Feedback, venue, source, firstAuthor string) api.ProjectPaper {
+	item := api.ProjectPaper{
+		RelevanceClass: class,
+		RelevanceScore: score,
+		ReadyAt:        ready,
+		Feedback:       feedback,
+		Paper:          api.Paper{PublishedDate: published, VenueName: venue},
+	}
+	if source != "" {
+		item.Paper.Source = &api.Source{Name: source}
+	}
+	if firstAuthor != "" {
+		item.Paper.Authors = []api.Author{{Name: firstAuthor}, {Name: "Someone Else"}}
+	}
+	return item
+}
+
+func TestCompute(t *testing.T) {
+	items := []api.ProjectPaper{
+		testItem(2, 0.95, "2025-09-03T10:00:00Z", "2025-09-01", &api.Feedback{Vote: "star"}, "NeurIPS", "arXiv", "Ada Lovelace"),
+		testItem(1, 0.42, "2025-09-04T10:00:00Z", "2025-09-02T10:00:00Z", &api.Feedback{Vote: "downvote", DownvoteReason: "not_relevant"}, "neurips", "arXiv", "Lovelace, Ada"),
+		testItem(1, 1.0, "2025-09-17T08:00:00Z", "2025-09-10", &api.Feedback{Vote: "downvote"}, "", "bioRxiv", "Li Wei"),
+		testItem(2, 0.05, "not a date", "", nil, "ICML", "", ""),
+	}
+	report := Compute(items, time.Date(2025, 9, 24, 12, 0, 0, 0, time.UTC))
+
+	if report.Items != 4 || report.MustRead != 2 || report.Related != 2 || report.MustReadRatio != 0.5 {
+		t.Fatalf("counts = %+v", report)
+	}
+	wantWeeks := []WeekCount{{"2025-09-01", 2}, {"2025-09-08", 0}, {"2025-09-15", 1}, {"2025-09-22", 0}}
+	if !reflect.DeepEqual(report.Weeks, wantWeeks) {
+		t.Fatalf("weeks = %+v", report.Weeks)
+	}
+	var histogram []int
+	for _, bucket := range report.Relevance {
+		histogram = append(histogram, bucket.Count)
+	}
+	if !reflect.DeepEqual(histogram, []int{1, 0, 0, 0, 1, 0, 0, 0, 0, 2}) {
+		t.Fatalf("histogram = %v", histogram)
+	}
+	wantFeedback := Feedback{None: 1, Star: 1, Downvote: 2, Reasons: []Count{{"not_relevant", 1}, {"unspecified", 1}}}
+	if !reflect.DeepEqual(report.Feedback, wantFeedback) {
+		t.Fatalf("feedback = %+v", report.Feedback)
+	}
+	if !reflect.DeepEqual(report.TopVenues, []Count{{"NeurIPS", 2}, {"ICML", 1}}) {
+		t.Fatalf("venues = %+v", report.TopVenues)
+	}
+	if !reflect.DeepEqual(report.TopSources, []Count{{"arXiv", 2}, {"bioRxiv", 1}}) {
+		t.Fatalf("sources = %+v", report.TopSources)
+	}
+	if !reflect.DeepEqual(report.TopAuthors, []Count{{"Ada Lovelace", 2}, {"Li Wei", 1}}) {
+		t.Fatalf("authors = %+v", report.TopAuthors)
+	}
+	// Latencies are 2.42, 2 and 7.33 days.
+	if report.Latency == nil || report.Latency.Samples != 3 || report.Latency.MedianDays < 2.41 || report.Latency.MedianDays > 2.42 {
+		t.Fatalf("latency = %+v", report.Latency)
+	}
+}
+
+func TestComputeEmpty(t *testing.T) {
+	report := Compute(nil, time.Now())
+	if report.Items != 0 || len(report.Weeks) != 0 || report.Latency != nil || report.MustReadRatio != 0 || len(report.Relevance) != HistogramBuckets {
+		t.Fatalf("report = %+v", report)
+	}
+}
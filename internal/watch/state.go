@@ -0,0 +1,83 @@
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/paperzilla/pz/internal/config"
+)
+
+// Cursor marks how far a project's feed has been reported.
+type Cursor struct {
+	ReadyAt string `json:"ready_at"`
+	// IDs are the items already reported at ReadyAt, so items sharing the
+	// cursor's timestamp are not reported twice.
+	IDs []string `json:"ids,omitempty"`
+}
+
+func (c Cursor) seen(item string) bool {
+	for _, id := range c.IDs {
+		if id == item {
+			return true
+		}
+	}
+	return false
+}
+
+// State holds the cursors that survive restarts, keyed by account,
+// project and feed filter (see Watcher.Account).
+type State struct {
+	Projects map[string]Cursor `json:"projects"`
+
+	path string
+}
+
+func LoadState(path string) (*State, error) {
+	state := &State{Projects: map[string]Cursor{}, path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watch state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse watch state %s: %w", path, err)
+	}
+	if state.Projects == nil {
+		state.Projects = map[string]Cursor{}
+	}
+	return state, nil
+}
+
+// cursor returns the cursor stored under key. When there is none, a cursor
+// an older version saved under legacy, the bare project ID, is moved to key
+// so upgrading does not restart the watch. An empty legacy skips this.
+func (s *State) cursor(key, legacy string) (Cursor, bool) {
+	if cursor, ok := s.Projects[key]; ok {
+		return cursor, true
+	}
+	cursor, ok := s.Projects[legacy]
+	if legacy == "" || !ok {
+		return Cursor{}, false
+	}
+	delete(s.Projects, legacy)
+	s.Projects[key] = cursor
+	return cursor, true
+}
+
+// Save writes the state atomically so a watcher killed mid-write keeps its
+// previous cursors.
+func (s *State) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode watch state: %w", err)
+	}
+	if err := config.WriteFileAtomic(s.path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write watch state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+// Package watch polls project feeds and reports each newly ready
+// recommendation once.
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+)
+
+const (
+	pageSize = 50
+
+	// MinInterval keeps polling gentle on the API.
+	MinInterval = time.Minute
+
+	initialBackoff = 15 * time.Second
+	maxBackoff     = 30 * time.Minute
+)
+
+// Source is the slice of the API the watcher reads from.
+type Source interface {
+	Feed(projectID string, opts api.FeedOptions) (api.FeedResponse, error)
+}
+
+// Event is one newly ready recommendation.
+type Event struct {
+	ProjectID string
+	Item      api.ProjectPaper
+}
+
+type Watcher struct {
+	Source     Source
+	State      *State
+	ProjectIDs []string
+	Interval   time.Duration
+	// Account is who the feeds are read as. Cursors are kept per account,
+	// project and MustReadOnly, so watchers that see different feeds never
+	// advance each other's cursors.
+	Account string
+	// MustReadOnly only reports must-read papers.
+	MustReadOnly bool
+	// Emit reports one event. An error stops the watcher, and the event is
+	// reported again on the next run.
+	Emit func(Event) error
+	// Logf reports poll failures and retries.
+	Logf func(format string, args ...any)
+	// After waits between polls. Defaults to time.After.
+	After func(time.Duration) <-chan time.Time
+}
+
+// fatalError stops the watcher instead of being retried.
+type fatalError struct{ err error }
+
+func (e *fatalError) Error() string { return e.err.Error() }
+func (e *fatalError) Unwrap() error { return e.err }
+
+// Run polls until ctx is cancelled. Failed polls are retried with
+// exponential backoff; only a failing Emit or state write stops it.
+func (w *Watcher) Run(ctx context.Context) error {
+	after := w.After
+	if after == nil {
+		after = time.After
+	}
+
+	failures := 0
+	for {
+		err := w.PollOnce(ctx)
+		var fatal *fatalError
+		if errors.As(err, &fatal) {
+			return fatal.err
+		}
+
+		delay := w.Interval
+		if err != nil {
+			failures++
+			delay = Backoff(failures, w.Interval)
+			w.logf("poll failed: %v (retrying in %s)", err, delay)
+		} else {
+			failures = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-after(delay):
+		}
+	}
+}
+
+// Backoff is the delay before retry number failures. It doubles from 15s
+// up to the larger of interval and 30 minutes.
+func Backoff(failures int, interval time.Duration) time.Duration {
+	limit := max(interval, maxBackoff)
+	delay := initialBackoff
+	for i := 1; i < failures && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
+
+// PollOnce checks every project once. A project that fails does not stop
+// the others; its error is returned after all projects were polled.
+func (w *Watcher) PollOnce(ctx context.Context) error {
+	var errs []error
+	for _, projectID := range w.ProjectIDs {
+		if ctx.Err() != nil {
+			return nil
+		}
+		err := w.poll(ctx, projectID)
+		var fatal *fatalError
+		if errors.As(err, &fatal) {
+			return err
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w *Watcher) poll(ctx context.Context, projectID string) error {
+	key, legacy := w.cursorKey(projectID), ""
+	if w.Account != "" {
+		legacy = projectID
+	}
+	cursor, ok := w.State.cursor(key, legacy)
+	if !ok {
+		return w.start(projectID)
+	}
+
+	var items []api.ProjectPaper
+	for offset := 0; ; {
+		page, err := w.Source.Feed(projectID, api.FeedOptions{
+			MustReadOnly: w.MustReadOnly,
+			Since:        cursor.ReadyAt,
+			Limit:        pageSize,
+			Offset:       offset,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch feed for project %s: %w", projectID, err)
+		}
+		items = append(items, page.Items...)
+		offset += len(page.Items)
+		if len(page.Items) == 0 || offset >= page.Total {
+			break
+		}
+	}
+
+	// Report oldest first so the cursor only ever moves forward.
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := readyTime(items[i].ReadyAt), readyTime(items[j].ReadyAt)
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return items[i].ID < items[j].ID
+	})
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return nil
+		}
+		ready, at := readyTime(item.ReadyAt), readyTime(cursor.ReadyAt)
+		if ready.Before(at) || (ready.Equal(at) && cursor.seen(item.ID)) {
+			continue
+		}
+		if err := w.Emit(Event{ProjectID: projectID, Item: item}); err != nil {
+			return &fatalError{err}
+		}
+		if !ready.Equal(at) {
+			cursor = Cursor{ReadyAt: item.ReadyAt}
+		}
+		cursor.IDs = append(cursor.IDs, item.ID)
+		w.State.Projects[key] = cursor
+		if err := w.State.Save(); err != nil {
+			return &fatalError{err}
+		}
+	}
+	return nil
+}
+
+// start records where a project's feed currently ends without reporting
+// anything, so the first run does not replay the whole feed. It reads every
+// page, so the cursor does not depend on the order the feed is served in.
+func (w *Watcher) start(projectID string) error {
+	var cursor Cursor
+	for offset := 0; ; {
+		page, err := w.Source.Feed(projectID, api.FeedOptions{MustReadOnly: w.MustReadOnly, Limit: pageSize, Offset: offset})
+		if err != nil {
+			return fmt.Errorf("failed to fetch feed for project %s: %w", projectID, err)
+		}
+		for _, item := range page.Items {
+			ready, at := readyTime(item.ReadyAt), readyTime(cursor.ReadyAt)
+			switch {
+			case ready.After(at):
+				cursor = Cursor{ReadyAt: item.ReadyAt, IDs: []string{item.ID}}
+			case ready.Equal(at) && !cursor.seen(item.ID):
+				cursor.IDs = append(cursor.IDs, item.ID)
+			}
+		}
+		offset += len(page.Items)
+		if len(page.Items) == 0 || offset >= page.Total {
+			break
+		}
+	}
+	w.State.Projects[w.cursorKey(projectID)] = cursor
+	if err := w.State.Save(); err != nil {
+		return &fatalError{err}
+	}
+	w.logf("watching project %s from %s", projectID, startLabel(cursor.ReadyAt))
+	return nil
+}
+
+// cursorKey is the state key of projectID's cursor for this watcher:
+// account/project, with a /must-read suffix for MustReadOnly.
+func (w *Watcher) cursorKey(projectID string) string {
+	key := projectID
+	if w.Account != "" {
+		key = w.Account + "/" + key
+	}
+	if w.MustReadOnly {
+		key += "/must-read"
+	}
+	return key
+}
+
+// readyTime parses a ready_at timestamp. Timestamps are compared as times,
+// not strings, because the number of fractional digits varies. An
+// unparseable one is the zero time.
+func readyTime(value string) time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, value)
+	return t
+}
+
+func startLabel(readyAt string) string {
+	if readyAt == "" {
+		return "the beginning of its feed"
+	}
+	return readyAt
+}
+
+func (w *Watcher) logf(format string, args ...any) {
+	if w.Logf != nil {
+		w.Logf(format, args...)
+	}
+}
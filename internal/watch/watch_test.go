@@ -0,0 +1,235 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paperzilla/pz/internal/api"
+)
+
+type fakeSource struct {
+	items    []api.ProjectPaper
+	err      error
+	requests []api.FeedOptions
+}
+
+// Feed treats since as inclusive, like the API may.
+func (s *fakeSource) Feed(projectID string, opts api.FeedOptions) (api.FeedResponse, error) {
+	s.requests = append(s.requests, opts)
+	if s.err != nil {
+		return api.FeedResponse{}, s.err
+	}
+	var matching []api.ProjectPaper
+	for _, item := range s.items {
+		if !readyTime(item.ReadyAt).Before(readyTime(opts.Since)) && (!opts.MustReadOnly || item.RelevanceClass == 2) {
+			matching = append(matching, item)
+		}
+	}
+	end := min(opts.Offset+opts.Limit, len(matching))
+	return api.FeedResponse{Items: matching[opts.Offset:end], Total: len(matching)}, nil
+}
+
+func newTestWatcher(t *testing.T, source Source) (*Watcher, *[]string) {
+	t.Helper()
+	state, err := LoadState(filepath.Join(t.TempDir(), "watch.json"))
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	var emitted []string
+	return &Watcher{
+		Source:     source,
+		State:      state,
+		ProjectIDs: []string{"p1"},
+		Interval:   time.Minute,
+		Emit: func(event Event) error {
+			emitted = append(emitted, event.Item.ID)
+			return nil
+		},
+	}, &emitted
+}
+
+func item(id, readyAt string) api.ProjectPaper {
+	return api.ProjectPaper{ID: id, ReadyAt: readyAt}
+}
+
+func TestPollReportsEachNewItemOnce(t *testing.T) {
+	source := &fakeSource{items: []api.ProjectPaper{item("old", "2026-01-01T00:00:00Z")}}
+	w, emitted := newTestWatcher(t, source)
+
+	if err := w.PollOnce(context.Background()); err != nil {
+		t.Fatalf("first poll: %v", err)
+	}
+	if len(*emitted) != 0 {
+		t.Fatalf("first poll should only record the cursor, emitted %q", *emitted)
+	}
+
+	source.items = append(source.items, item("b", "2026-01-03T00:00:00Z"), item("a", "2026-01-02T00:00:00Z"), item("c", "2026-01-03T00:00:00Z"))
+	if err := w.PollOnce(context.Background()); err != nil {
+		t.Fatalf("second poll: %v", err)
+	}
+	if err := w.PollOnce(context.Background()); err != nil {
+		t.Fatalf("third poll: %v", err)
+	}
+	if got := strings.Join(*emitted, " "); got != "a b c" {
+		t.Fatalf("emitted = %q", got)
+	}
+	if since := source.requests[len(source.requests)-1].Since; since != "2026-01-03T00:00:00Z" {
+		t.Fatalf("since = %q", since)
+	}
+
+	// A restarted watcher picks up from the saved cursor.
+	state, err := LoadState(w.State.path)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	restarted, emittedAfter := newTestWatcher(t, source)
+	restarted.State = state
+	source.items = append(source.items, item("d", "2026-01-04T00:00:00Z"))
+	if err := restarted.PollOnce(context.Background()); err != nil {
+		t.Fatalf("restarted poll: %v", err)
+	}
+	if got := strings.Join(*emittedAfter, " "); got != "d" {
+		t.Fatalf("emitted after restart = %q", got)
+	}
+}
+
+func TestStartReadsEveryPage(t *testing.T) {
+	source := &fakeSource{}
+	for i := range pageSize + 10 {
+		source.items = append(source.items, item(fmt.Sprintf("old-%d", i), fmt.Sprintf("2026-01-01T00:%02d:00Z", i)))
+	}
+	w, emitted := newTestWatcher(t, source)
+	if err := w.PollOnce(context.Background()); err != nil {
+		t.Fatalf("first poll: %v", err)
+	}
+	if cursor := w.State.Projects["p1"]; cursor.ReadyAt != "2026-01-01T00:59:00Z" {
+		t.Fatalf("cursor = %+v", cursor)
+	}
+	if err := w.PollOnce(context.Background()); err != nil {
+		t.Fatalf("second poll: %v", err)
+	}
+	if len(*emitted) != 0 {
+		t.Fatalf("old items were reported: %q", *emitted)
+	}
+}
+
+func TestEmitFailureStopsWithoutAdvancingCursor(t *testing.T) {
+	source := &fakeSource{}
+	w, _ := newTestWatcher(t, source)
+	w.PollOnce(context.Background())
+
+	source.items = []api.ProjectPaper{item("a", "2026-01-02T00:00:00Z")}
+	w.Emit = func(Event) error { return errors.New("broken pipe") }
+	if err := w.Run(context.Background()); err == nil || err.Error() != "broken pipe" {
+		t.Fatalf("Run error = %v", err)
+	}
+	if cursor := w.State.Projects["p1"]; cursor.ReadyAt != "" {
+		t.Fatalf("cursor advanced to %+v", cursor)
+	}
+}
+
+func TestRunBacksOffOnErrorsAndStopsOnCancel(t *testing.T) {
+	source := &fakeSource{err: errors.New("connection refused")}
+	w, _ := newTestWatcher(t, source)
+	var logs []string
+	w.Logf = func(format string, args ...any) {
+		logs = append(logs, format)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var delays []time.Duration
+	w.After = func(d time.Duration) <-chan time.Time {
+		delays = append(delays, d)
+		if len(delays) == 3 {
+			source.err = nil
+		}
+		ch := make(chan time.Time, 1)
+		if len(delays) == 4 {
+			cancel()
+		} else {
+			ch <- time.Time{}
+		}
+		return ch
+	}
+
+	if err := w.Run(ctx); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := []time.Duration{15 * time.Second, 30 * time.Second, time.Minute, time.Minute}
+	if len(delays) != len(want) {
+		t.Fatalf("delays = %v", delays)
+	}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Fatalf("delays = %v, want %v", delays, want)
+		}
+	}
+	if len(logs) != 4 {
+		t.Fatalf("logs = %q", logs)
+	}
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	if got := Backoff(20, time.Minute); got != 30*time.Minute {
+		t.Fatalf("Backoff = %s", got)
+	}
+	if got := Backoff(20, 2*time.Hour); got != 2*time.Hour {
+		t.Fatalf("Backoff = %s", got)
+	}
+}
+
+func TestPollComparesReadyTimesAsTimes(t *testing.T) {
+	source := &fakeSource{items: []api.ProjectPaper{item("old", "2026-01-01T00:00:00Z")}}
+	w, emitted := newTestWatcher(t, source)
+	if err := w.PollOnce(context.Background()); err != nil {
+		t.Fatalf("first poll: %v", err)
+	}
+
+	// Half a second later, but it sorts before the cursor as a string.
+	source.items = append(source.items, item("later", "2026-01-01T00:00:00.5Z"), item("earlier", "2025-12-31T23:59:59.9Z"))
+	if err := w.PollOnce(context.Background()); err != nil {
+		t.Fatalf("second poll: %v", err)
+	}
+	if strings.Join(*emitted, ",") != "later" {
+		t.Fatalf("emitted = %q", *emitted)
+	}
+}
+
+func TestCursorsAreKeptPerAccountAndFilter(t *testing.T) {
+	mustRead := item("must-read", "2026-01-02T00:00:00Z")
+	mustRead.RelevanceClass = 2
+	source := &fakeSource{}
+	full, fullEmitted := newTestWatcher(t, source)
+	full.Account = "user-1"
+	filtered, filteredEmitted := newTestWatcher(t, source)
+	filtered.State, filtered.Account, filtered.MustReadOnly = full.State, "user-1", true
+	other, otherEmitted := newTestWatcher(t, source)
+	other.State, other.Account = full.State, "user-2"
+
+	// An older version's cursor is taken over by the first watcher to poll.
+	full.State.Projects["p1"] = Cursor{ReadyAt: "2026-01-01T00:00:00Z"}
+	for _, w := range []*Watcher{full, filtered, other} {
+		if err := w.PollOnce(context.Background()); err != nil {
+			t.Fatalf("start: %v", err)
+		}
+	}
+	if _, ok := full.State.Projects["p1"]; ok || full.State.Projects["user-1/p1"].ReadyAt != "2026-01-01T00:00:00Z" {
+		t.Fatalf("state = %+v", full.State.Projects)
+	}
+
+	source.items = []api.ProjectPaper{item("related", "2026-01-02T00:00:00Z"), mustRead}
+	for _, w := range []*Watcher{full, filtered, other} {
+		if err := w.PollOnce(context.Background()); err != nil {
+			t.Fatalf("poll: %v", err)
+		}
+	}
+	if strings.Join(*fullEmitted, ",") != "must-read,related" || strings.Join(*filteredEmitted, ",") != "must-read" ||
+		strings.Join(*otherEmitted, ",") != "must-read,related" {
+		t.Fatalf("full = %q, must-read = %q, other account = %q", *fullEmitted, *filteredEmitted, *otherEmitted)
+	}
+}